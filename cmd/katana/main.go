@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 
@@ -33,7 +34,6 @@ func main() {
 			gologger.Fatal().Msgf("could not create runner: %s\n", err)
 		}
 	}
-	defer runner.Close()
 
 	// close handler
 	go func() {
@@ -42,15 +42,23 @@ func main() {
 		go func() {
 			<-c
 			gologger.DefaultLogger.Info().Msg("- Ctrl+C pressed in Terminal")
-			runner.Close()
+			if err := runner.Close(); err != nil {
+				gologger.Fatal().Msgf("could not close runner: %s\n", err)
+			}
 			os.Exit(0)
 		}()
 	}()
 
-	if err := runner.ExecuteCrawling(); err != nil {
-		gologger.Fatal().Msgf("could not execute crawling: %s", err)
+	// the runner is closed before exiting on errors, as closing flushes
+	// and uploads the output which can fail as well
+	crawlErr := runner.ExecuteCrawling()
+	closeErr := runner.Close()
+	if crawlErr != nil {
+		gologger.Fatal().Msgf("could not execute crawling: %s", crawlErr)
+	}
+	if closeErr != nil {
+		gologger.Fatal().Msgf("could not close runner: %s\n", closeErr)
 	}
-
 }
 
 func readFlags() error {
@@ -65,6 +73,7 @@ pipelines offering both headless and non-headless crawling.`)
 	flagSet.CreateGroup("config", "Configuration",
 		flagSet.IntVarP(&options.MaxDepth, "depth", "d", 2, "maximum depth to crawl"),
 		flagSet.BoolVarP(&options.ScrapeJSResponses, "js-crawl", "jc", false, "enable endpoint parsing / crawling in javascript file"),
+		flagSet.BoolVarP(&options.IncludeSnippet, "include-snippet", "isn", false, "include the source snippet around urls extracted from javascript in output"),
 		flagSet.IntVarP(&options.CrawlDuration, "crawl-duration", "ct", 0, "maximum duration to crawl the target for"),
 		flagSet.StringVarP(&options.KnownFiles, "known-files", "kf", "", "enable crawling of known files (all,robotstxt,sitemapxml)"),
 		flagSet.IntVarP(&options.BodyReadSize, "max-response-size", "mrs", 2*1024*1024, "maximum response size to read"),
@@ -92,14 +101,42 @@ pipelines offering both headless and non-headless crawling.`)
 		flagSet.StringVarP(&options.FieldScope, "field-scope", "fs", "rdn", "pre-defined scope field (dn,rdn,fqdn)"),
 		flagSet.BoolVarP(&options.NoScope, "no-scope", "ns", false, "disables host based default scope"),
 		flagSet.BoolVarP(&options.DisplayOutScope, "display-out-scope", "do", false, "display external endpoint from scoped crawling"),
+		flagSet.BoolVarP(&options.IncludeErrors, "include-errors", "ie", false, "display failed requests with their error in output"),
 	)
 
 	availableFields := strings.Join(output.FieldNames, ",")
 	flagSet.CreateGroup("filter", "Filter",
 		flagSet.StringVarP(&options.Fields, "field", "f", "", fmt.Sprintf("field to display in output (%s)", availableFields)),
+		flagSet.StringSliceVarP(&options.FieldAliases, "field-alias", "fa", nil, "rename json output keys (endpoint=loc)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.StringVarP(&options.StoreFields, "store-field", "sf", "", fmt.Sprintf("field to store in per-host output (%s)", availableFields)),
+		flagSet.StringVarP(&options.StoreFieldsDir, "store-field-dir", "sfd", output.DefaultStoreFieldsDir, "base directory of the per-run store field directories (empty to write to the working directory)"),
+		flagSet.StringVar(&options.StoreFieldsRunID, "store-field-run-id", "", "name of the store field directory of the run (default timestamp with random suffix)"),
+		flagSet.StringSliceVarP(&options.CaptureHeaders, "capture-header", "ch", nil, "response headers to capture in output, all if not specified (eg, -ch server,x-powered-by)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.BoolVarP(&options.CaptureRequestHeaders, "capture-request-header", "crh", false, "capture the sent request headers in output"),
+		flagSet.BoolVarP(&options.TechDetect, "tech-detect", "td", false, "display technologies fingerprinted from the responses in output"),
+		flagSet.StringSliceVarP(&options.ExtractSelectors, "extract-selector", "es", nil, "extract values from html responses into output with css selectors (eg, -es 'desc=meta[name=description]@content')", goflags.StringSliceOptions),
+		flagSet.BoolVarP(&options.SniffContentType, "sniff-content-type", "sct", false, "detect the content-type of responses without a content-type header"),
+		flagSet.BoolVarP(&options.CaptureTLS, "capture-tls", "ctls", false, "capture the tls certificate details of https responses in output"),
+		flagSet.StringVarP(&options.HashAlgorithm, "hash-algorithm", "ha", "sha256", "algorithm to hash response bodies with (sha256,sha1,md5)"),
+		flagSet.StringVarP(&options.OutputTemplate, "output-template", "ot", "", "template to format output with field placeholders (eg, -ot '{url}\\t{status_code}')"),
 		flagSet.StringSliceVarP(&options.ExtensionsMatch, "extension-match", "em", nil, "match output for given extension (eg, -em php,html,js)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.StringSliceVarP(&options.ExtensionFilter, "extension-filter", "ef", nil, "filter output for given extension (eg, -ef png,css)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.MatchContentType, "match-content-type", "mct", nil, "match output for given content-type (eg, -mct text/html,application/json)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.FilterContentType, "filter-content-type", "fct", nil, "filter output for given content-type (eg, -fct image/png)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.MatchStatusCode, "match-status-code", "msc", nil, "match output for given status code (eg, -msc 200,3xx)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.FilterStatusCode, "filter-status-code", "fsc", nil, "filter output for given status code (eg, -fsc 404,5xx)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.MatchRegex, "match-regex", "mr", nil, "regex or list of regex to match on output url (eg, -mr '/api/')", goflags.FileStringSliceOptions),
+		flagSet.StringSliceVarP(&options.FilterRegex, "filter-regex", "fr", nil, "regex or list of regex to filter on output url (eg, -fr 'logout')", goflags.FileStringSliceOptions),
+		flagSet.IntVarP(&options.MaxOutputDepth, "max-output-depth", "mod", 0, "maximum crawl depth of results to display in output"),
+		flagSet.IntVar(&options.MinBodySize, "min-body-size", 0, "minimum response body size in bytes of results to display in output"),
+		flagSet.IntVar(&options.MaxBodySize, "max-body-size", 0, "maximum response body size in bytes of results to display in output"),
+		flagSet.StringVarP(&options.BaselineFile, "baseline", "bl", "", "file of known urls to drop from output, displaying only new urls"),
+		flagSet.BoolVarP(&options.UpdateBaseline, "baseline-update", "blu", false, "append the new urls in output to the baseline file"),
+		flagSet.BoolVarP(&options.SequenceIDs, "sequence-ids", "sid", false, "assign an increasing sequence id to every result in output"),
+		flagSet.BoolVarP(&options.LogFiltered, "log-filtered", "lf", false, "log the results dropped by the output filters with the reason to stderr"),
+		flagSet.Var((*floatValue)(&options.SampleRate), "sample-rate", "probability between 0 and 1 of displaying each result in output"),
+		flagSet.IntVar(&options.SampleEveryN, "sample-every", 0, "display only every nth result in output"),
+		flagSet.IntVar(&options.MaxResultsPerSecond, "max-results-per-second", 0, "maximum number of results to output per second (slows down the crawl)"),
 	)
 
 	flagSet.CreateGroup("ratelimit", "Rate-Limit",
@@ -112,12 +149,78 @@ pipelines offering both headless and non-headless crawling.`)
 
 	flagSet.CreateGroup("output", "Output",
 		flagSet.StringVarP(&options.OutputFile, "output", "o", "", "file to write output to"),
+		flagSet.BoolVarP(&options.CompressOutput, "output-compress", "oc", false, "gzip compress the output file (enabled for .gz files)"),
+		flagSet.StringVarP(&options.JSOutput, "js-output", "jo", "", "file to write endpoints extracted from javascript to in JSONL format"),
+		flagSet.BoolVarP(&options.ParamsOnly, "params-only", "po", false, "write only the unique query parameter names of the results on exit"),
+		flagSet.BoolVar(&options.SortOutput, "sort-output", false, "buffer results in memory and write them sorted by url on exit"),
+		flagSet.BoolVarP(&options.DedupByBody, "dedup-by-body", "dbb", false, "write only the first result for every unique response body with its duplicate count on exit"),
+		flagSet.BoolVarP(&options.NormalizeURLs, "normalize-urls", "nu", false, "write absolute normalized urls with lowercase host and without default port"),
+		flagSet.BoolVarP(&options.SortQueryParams, "sort-query-params", "sqp", false, "sort the query parameters of the normalized urls"),
+		flagSet.StringVar(&options.SplitBy, "split-by", "", "split output into a file per source, status or host in the output directory (source,status,host)"),
+		flagSet.StringSliceVar(&options.Sinks, "sink", nil, "additional output in format[:file] written to stdout without file (text,json,jsonl,csv,tsv,yaml,cef,curl)", goflags.StringSliceOptions),
+		flagSet.StringVar(&options.Bundle, "bundle", "", "package output file and stored responses into a .zip or .tar.gz file on exit"),
+		flagSet.BoolVar(&options.BundleRemove, "bundle-remove", false, "remove the original output files after bundling"),
+		flagSet.BoolVar(&options.Manifest, "manifest", false, "write a manifest.json of the output files with checksums on exit"),
+		flagSet.IntVar(&options.SyncEvery, "sync-every", 0, "sync the output file to disk after number of results (slower)"),
+		flagSet.DurationVar(&options.SyncInterval, "sync-interval", 0, "sync the output file to disk at interval (eg, 10s)"),
+		flagSet.IntVar(&options.RotateSize, "rotate-size", 0, "rotate the output file after size in bytes"),
+		flagSet.DurationVar(&options.RotateInterval, "rotate-interval", 0, "rotate the output file after interval (eg, 1h)"),
+		flagSet.BoolVar(&options.NoStdout, "no-stdout", false, "disable writing results to stdout (results are still written to the output file)"),
 		flagSet.BoolVarP(&options.StoreResponse, "store-response", "sr", false, "store http requests/responses"),
 		flagSet.StringVarP(&options.StoreResponseDir, "store-response-dir", "srd", "", "store http requests/responses to custom directory"),
+		flagSet.StringVarP(&options.StoreResponseIndex, "store-response-index", "sri", "txt", "format of the stored http responses index (txt,json)"),
+		flagSet.BoolVarP(&options.ResumeResponses, "store-response-resume", "srr", false, "keep previously stored http requests/responses and append to index"),
+		flagSet.StringVarP(&options.StoreResponseNaming, "store-response-naming", "srn", "default", "naming scheme of the stored http responses (default,sha1,hierarchical)"),
+		flagSet.StringSliceVarP(&options.RedactPatterns, "redact-pattern", "rdp", nil, "regex or list of regex of secrets to redact from request bodies and stored responses", goflags.FileStringSliceOptions),
+		flagSet.BoolVarP(&options.RedactBuiltins, "redact-builtins", "rdb", false, "redact common secrets like aws keys and jwts from request bodies and stored responses"),
+		flagSet.BoolVarP(&options.StoreResponseDedup, "store-response-dedup", "srdd", false, "store http responses with an already stored body as a link to the first response"),
+		flagSet.BoolVarP(&options.SplitResponses, "store-response-split", "srs", false, "store http requests, responses and metadata in separate files"),
+		flagSet.BoolVarP(&options.CompressResponses, "store-response-compress", "src", false, "gzip compress stored http requests/responses"),
+		flagSet.StringSliceVarP(&options.StoreMatchStatusCode, "store-match-status-code", "smsc", nil, "store only responses with given status code (eg, -smsc 200,3xx)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringSliceVarP(&options.StoreMatchContentType, "store-match-content-type", "smct", nil, "store only responses with given content-type (eg, -smct text/html)", goflags.CommaSeparatedStringSliceOptions),
 		flagSet.BoolVarP(&options.JSON, "json", "j", false, "write output in JSONL(ines) format"),
+		flagSet.BoolVar(&options.JSONArray, "json-array", false, "write output as a single JSON array"),
+		flagSet.BoolVar(&options.CSV, "csv", false, "write output in CSV format"),
+		flagSet.BoolVar(&options.TSV, "tsv", false, "write output in TSV format"),
+		flagSet.BoolVar(&options.YAML, "yaml", false, "write output in YAML format"),
+		flagSet.BoolVar(&options.CEF, "cef", false, "write output in Common Event Format (CEF)"),
+		flagSet.BoolVar(&options.OutputCurl, "curl", false, "write output as curl commands replaying the requests (use with -crh)"),
+		flagSet.StringVar(&options.CEFVendor, "cef-vendor", "", "device vendor of the CEF output (default ProjectDiscovery)"),
+		flagSet.StringVar(&options.CEFProduct, "cef-product", "", "device product of the CEF output (default katana)"),
+		flagSet.StringVar(&options.CEFVersion, "cef-version", "", "device version of the CEF output (default 1.0)"),
+		flagSet.BoolVarP(&options.Protobuf, "protobuf", "pb", false, "write output as length-prefixed protobuf messages"),
+		flagSet.BoolVar(&options.HAR, "har", false, "write http requests/responses in HAR format"),
+		flagSet.StringVarP(&options.SARIFExport, "sarif-export", "se", "", "file to write output to in SARIF format"),
+		flagSet.StringVarP(&options.SQLiteOutput, "sqlite-output", "sqo", "", "sqlite database file to write output to (requires a build with the sqlite tag)"),
+		flagSet.StringVarP(&options.ESURL, "es-url", "esu", "", "elasticsearch/opensearch url to send output to with bulk requests"),
+		flagSet.StringVarP(&options.ESIndex, "es-index", "esi", output.DefaultElasticsearchIndex, "elasticsearch index to send output to"),
+		flagSet.StringVarP(&options.ESAuthHeader, "es-auth-header", "esah", "", "elasticsearch authentication header (e.g. 'Authorization: ApiKey <key>')"),
+		flagSet.StringSliceVarP(&options.KafkaBrokers, "kafka-brokers", "kb", nil, "kafka brokers to produce output to (requires a build with the kafka tag)", goflags.CommaSeparatedStringSliceOptions),
+		flagSet.StringVarP(&options.KafkaTopic, "kafka-topic", "kt", "", "kafka topic to produce output to"),
+		flagSet.BoolVarP(&options.KafkaRequireAck, "kafka-require-ack", "kra", false, "require acknowledgement of all kafka replicas and fail on produce errors"),
+		flagSet.StringVarP(&options.S3Bucket, "s3-bucket", "s3b", "", "s3 bucket to upload output to on completion (requires a build with the objectstorage tag)"),
+		flagSet.StringVarP(&options.S3Prefix, "s3-prefix", "s3p", "", "prefix of the objects uploaded to the s3 bucket"),
+		flagSet.StringVarP(&options.GCSBucket, "gcs-bucket", "gcsb", "", "gcs bucket to upload output to on completion (requires a build with the objectstorage tag)"),
+		flagSet.StringVarP(&options.GCSPrefix, "gcs-prefix", "gcsp", "", "prefix of the objects uploaded to the gcs bucket"),
+		flagSet.StringVarP(&options.WebhookURL, "webhook-url", "wu", "", "webhook url to POST output to as json"),
+		flagSet.IntVarP(&options.WebhookBatchSize, "webhook-batch-size", "wbs", 1, "maximum number of results per webhook request"),
+		flagSet.StringSliceVarP(&options.WebhookHeaders, "webhook-header", "wh", nil, "header to include in webhook requests", goflags.StringSliceOptions),
+		flagSet.BoolVarP(&options.WebhookDrop, "webhook-drop", "wd", false, "drop results instead of blocking when the webhook queue is full"),
+		flagSet.StringVarP(&options.UnixSocket, "unix-socket", "us", "", "unix socket path to stream output to as jsonl"),
+		flagSet.BoolVarP(&options.UnixSocketListen, "unix-socket-listen", "usl", false, "listen on the unix socket instead of connecting to it"),
 		flagSet.BoolVarP(&options.NoColors, "no-color", "nc", false, "disable output content coloring (ANSI escape codes)"),
+		flagSet.StringVarP(&options.ColorScheme, "color-scheme", "csc", "default", "output content color scheme (default,source)"),
+		flagSet.StringVarP(&options.TimestampFormat, "timestamp-format", "tsf", "rfc3339", "result timestamp format (rfc3339,epoch,epochmillis)"),
+		flagSet.BoolVarP(&options.Tabular, "tabular", "tab", false, "display url, status code and content type in aligned columns"),
+		flagSet.BoolVarP(&options.PreserveFileColor, "preserve-file-color", "pfc", false, "keep output content coloring in the output file"),
+		flagSet.BoolVar(&options.Summary, "summary", false, "display a summary of the crawl results"),
+		flagSet.StringVarP(&options.SummaryFile, "summary-file", "sumf", "", "file to write the summary of the crawl results to"),
+		flagSet.BoolVarP(&options.CountOnly, "count-only", "co", false, "only count the crawl results and display the summary without writing output"),
+		flagSet.BoolVar(&options.Progress, "progress", false, "display a progress line with the written results, unique hosts and rate on stderr"),
+		flagSet.StringVarP(&options.GraphOutput, "graph-output", "gro", "", "file to write a graphviz dot graph of the crawl links to"),
 		flagSet.BoolVar(&options.Silent, "silent", false, "display output only"),
 		flagSet.BoolVarP(&options.Verbose, "verbose", "v", false, "display verbose output"),
+		flagSet.BoolVar(&options.ValidateOutput, "validate-output", false, "validate json output against the result schema"),
 		flagSet.BoolVar(&options.Version, "version", false, "display project version"),
 	)
 
@@ -132,3 +235,19 @@ pipelines offering both headless and non-headless crawling.`)
 	}
 	return nil
 }
+
+// floatValue is a flag value for float options
+type floatValue float64
+
+func (f *floatValue) String() string {
+	return strconv.FormatFloat(float64(*f), 'g', -1, 64)
+}
+
+func (f *floatValue) Set(value string) error {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return err
+	}
+	*f = floatValue(parsed)
+	return nil
+}
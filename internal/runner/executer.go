@@ -1,8 +1,11 @@
 package runner
 
 import (
+	"sync"
+
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/remeh/sizedwaitgroup"
 )
 
@@ -15,6 +18,10 @@ func (r *Runner) ExecuteCrawling() error {
 
 	defer r.crawler.Close()
 
+	// the crawls are stopped on a fatal output error, which is returned
+	var outputErr error
+	outputErrMutex := &sync.Mutex{}
+
 	wg := sizedwaitgroup.New(r.options.Parallelism)
 	for _, input := range inputs {
 		wg.Add()
@@ -22,11 +29,21 @@ func (r *Runner) ExecuteCrawling() error {
 		go func(input string) {
 			defer wg.Done()
 
-			if err := r.crawler.Crawl(input); err != nil {
-				gologger.Warning().Msgf("Could not crawl %s: %s", input, err)
+			err := r.crawler.Crawl(input)
+			if err == nil {
+				return
+			}
+			if output.IsFatalError(err) {
+				outputErrMutex.Lock()
+				if outputErr == nil {
+					outputErr = err
+				}
+				outputErrMutex.Unlock()
+				return
 			}
+			gologger.Warning().Msgf("Could not crawl %s: %s", input, err)
 		}(input)
 	}
 	wg.Wait()
-	return nil
+	return outputErr
 }
@@ -37,6 +37,9 @@ func validateOptions(options *types.Options) error {
 			return errors.New("specified system chrome binary does not exist")
 		}
 	}
+	if countTrue(options.JSON, options.JSONArray, options.CSV, options.TSV, options.YAML, options.HAR, options.Protobuf, options.CEF, options.OutputCurl) > 1 {
+		return errors.New("only one of json, json-array, csv, tsv, yaml, har, protobuf, cef or curl output formats can be used")
+	}
 	if options.StoreResponseDir != "" && !options.StoreResponse {
 		gologger.Debug().Msgf("store response directory specified, enabling \"sr\" flag automatically\n")
 		options.StoreResponse = true
@@ -98,6 +101,9 @@ func configureOutput(options *types.Options) {
 	if options.Silent {
 		gologger.DefaultLogger.SetMaxLevel(levels.LevelSilent)
 	}
+	if options.LogFiltered && !options.Silent {
+		gologger.DefaultLogger.SetMaxLevel(levels.LevelDebug)
+	}
 	if options.Verbose {
 		gologger.DefaultLogger.SetMaxLevel(levels.LevelVerbose)
 	}
@@ -125,3 +131,14 @@ func initExampleFormFillConfig() error {
 	err = yaml.NewEncoder(exampleConfig).Encode(utils.DefaultFormFillData)
 	return err
 }
+
+// countTrue returns the number of true values
+func countTrue(values ...bool) int {
+	var count int
+	for _, value := range values {
+		if value {
+			count++
+		}
+	}
+	return count
+}
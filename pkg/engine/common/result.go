@@ -0,0 +1,96 @@
+package common
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/output"
+	"github.com/projectdiscovery/katana/pkg/types"
+	"github.com/projectdiscovery/katana/pkg/utils/queue"
+)
+
+// NewResult returns an output result for a navigation request
+func NewResult(nr navigation.Request) *output.Result {
+	result := &output.Result{
+		Timestamp:     time.Now(),
+		Body:          nr.Body,
+		URL:           nr.URL,
+		Source:        nr.Source,
+		Tag:           nr.Tag,
+		Attribute:     nr.Attribute,
+		Form:          nr.Form,
+		Depth:         nr.Depth,
+		Parent:        nr.Source,
+		SourceSnippet: nr.Snippet,
+	}
+	if nr.Method != http.MethodGet {
+		result.Method = nr.Method
+	}
+	return result
+}
+
+// WriteResult writes the result for a crawled navigation request along
+// with its optional response, request latency and request error to output.
+//
+// The seed requests are not written as results, only their response,
+// unless they failed and errors are included. The error writing the
+// result is returned.
+func WriteResult(options *types.CrawlerOptions, nr navigation.Request, resp *http.Response, latency time.Duration, err error) error {
+	var result *output.Result
+	if nr.Depth > 0 || (err != nil && options.Options.IncludeErrors) {
+		result = NewResult(nr)
+		result.Latency = output.Duration(latency)
+	}
+	if err != nil && options.Options.IncludeErrors {
+		result.Error = err.Error()
+	}
+	if result == nil && resp == nil {
+		return nil
+	}
+	return options.OutputWriter.Write(result, resp)
+}
+
+// WriteQueuedResults writes the results for the requests which were
+// left in the queue without being crawled, returning the first error
+// writing a result.
+func WriteQueuedResults(options *types.CrawlerOptions, queue *queue.VarietyQueue) error {
+	var writeErr error
+	for queue.Len() > 0 {
+		if nr, ok := queue.Pop().(navigation.Request); ok {
+			if err := WriteResult(options, nr, nil, 0, nil); err != nil && writeErr == nil {
+				writeErr = err
+			}
+		}
+	}
+	return writeErr
+}
+
+// OutputError keeps the first fatal output error of the crawls of a
+// crawler, after which the results are lost and the crawls are stopped.
+type OutputError struct {
+	mutex sync.Mutex
+	err   error
+}
+
+// Set keeps the error if it is the first fatal output error
+func (o *OutputError) Set(err error) {
+	if !output.IsFatalError(err) {
+		return
+	}
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if o.err == nil {
+		o.err = err
+	}
+}
+
+// Get returns the first fatal output error
+func (o *OutputError) Get() error {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.err
+}
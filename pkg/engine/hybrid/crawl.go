@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
@@ -20,7 +21,40 @@ import (
 	"github.com/projectdiscovery/retryablehttp-go"
 )
 
-func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp.Client, queue *queue.VarietyQueue, parseResponseCallback func(nr navigation.Request), browser *rod.Browser, request navigation.Request, rootHostname string) (*navigation.Response, error) {
+// pageDocument is the response of the document of a navigated page,
+// which is written to output with the result of the navigated request
+type pageDocument struct {
+	mutex   sync.Mutex
+	found   bool
+	resp    *http.Response
+	latency time.Duration
+}
+
+// set sets the response of the document if it was not found before, the
+// response is nil for the documents with duplicate content.
+func (d *pageDocument) set(resp *http.Response, latency time.Duration) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.found {
+		return
+	}
+	d.found = true
+	d.resp = resp
+	d.latency = latency
+}
+
+// get returns a copy of the document response
+func (d *pageDocument) get() *pageDocument {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return &pageDocument{found: d.found, resp: d.resp, latency: d.latency}
+}
+
+// navigateRequest navigates the page to the request, returning the
+// rendered response and the response of the navigated document.
+func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp.Client, queue *queue.VarietyQueue, parseResponseCallback func(nr navigation.Request), browser *rod.Browser, request navigation.Request, rootHostname string) (*navigation.Response, *pageDocument, error) {
 	depth := request.Depth + 1
 	response := &navigation.Response{
 		Depth:        depth,
@@ -30,7 +64,7 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 
 	page, err := browser.Page(proto.TargetCreateTarget{})
 	if err != nil {
-		return nil, errors.Wrap(err, "could not create target")
+		return nil, nil, errors.Wrap(err, "could not create target")
 	}
 	defer page.Close()
 
@@ -39,12 +73,14 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 		URLPattern:   "*",
 		RequestStage: proto.FetchRequestStageResponse,
 	})
+	document := &pageDocument{}
+	start := time.Now()
 	go pageRouter.Start(func(e *proto.FetchRequestPaused) error {
 		URL, _ := url.Parse(e.Request.URL)
 		body, _ := FetchGetResponseBody(page, e)
-		headers := make(map[string][]string)
+		headers := make(http.Header)
 		for _, h := range e.ResponseHeaders {
-			headers[h.Name] = []string{h.Value}
+			headers.Add(h.Name, h.Value)
 		}
 		var statuscode int
 		if e.ResponseStatusCode != nil {
@@ -61,7 +97,19 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 				Body:   io.NopCloser(strings.NewReader(e.Request.PostData)),
 			},
 		}
-		if !c.options.UniqueFilter.UniqueContent(body) {
+		unique := c.options.UniqueFilter.UniqueContent(body)
+		// the first document response which is not a redirect is the
+		// response of the navigated request
+		if e.ResourceType == proto.NetworkResourceTypeDocument && !isRedirect(httpresp) {
+			var documentResp *http.Response
+			if unique {
+				copied := *httpresp
+				copied.Body = io.NopCloser(bytes.NewReader(body))
+				documentResp = &copied
+			}
+			document.set(documentResp, time.Since(start))
+		}
+		if !unique {
 			return FetchContinueRequest(page, e)
 		}
 
@@ -93,7 +141,7 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 	waitNavigation := page.WaitNavigation(proto.PageLifecycleEventNameFirstMeaningfulPaint)
 
 	if err := page.Navigate(request.URL); err != nil {
-		return nil, errors.Wrap(err, "could not navigate target")
+		return nil, nil, errors.Wrap(err, "could not navigate target")
 	}
 	waitNavigation()
 
@@ -111,14 +159,14 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 	getDocument := &proto.DOMGetDocument{Depth: &getDocumentDepth, Pierce: true}
 	result, err := getDocument.Call(page)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not get dom")
+		return nil, nil, errors.Wrap(err, "could not get dom")
 	}
 	var builder strings.Builder
 	traverseDOMNode(result.Root, &builder)
 
 	body, err := page.HTML()
 	if err != nil {
-		return nil, errors.Wrap(err, "could not get html")
+		return nil, nil, errors.Wrap(err, "could not get html")
 	}
 
 	parsed, _ := url.Parse(request.URL)
@@ -128,7 +176,7 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 	responseCopy := *response
 	responseCopy.Body = []byte(builder.String())
 	if !c.options.UniqueFilter.UniqueContent(responseCopy.Body) {
-		return &navigation.Response{}, nil
+		return &navigation.Response{}, document.get(), nil
 	}
 
 	responseCopy.Reader, _ = goquery.NewDocumentFromReader(bytes.NewReader(responseCopy.Body))
@@ -138,13 +186,18 @@ func (c *Crawler) navigateRequest(ctx context.Context, httpclient *retryablehttp
 
 	response.Body = []byte(body)
 	if !c.options.UniqueFilter.UniqueContent(response.Body) {
-		return &navigation.Response{}, nil
+		return &navigation.Response{}, document.get(), nil
 	}
 	response.Reader, err = goquery.NewDocumentFromReader(bytes.NewReader(response.Body))
 	if err != nil {
-		return nil, errors.Wrap(err, "could not parse html")
+		return nil, nil, errors.Wrap(err, "could not parse html")
 	}
-	return response, nil
+	return response, document.get(), nil
+}
+
+// isRedirect returns true if the response redirects to another location
+func isRedirect(resp *http.Response) bool {
+	return resp.StatusCode >= 300 && resp.StatusCode < 400 && resp.Header.Get("Location") != ""
 }
 
 // traverseDOMNode performs traversal of node completely building a pseudo-HTML
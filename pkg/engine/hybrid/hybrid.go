@@ -21,7 +21,6 @@ import (
 	"github.com/projectdiscovery/katana/pkg/engine/parser"
 	"github.com/projectdiscovery/katana/pkg/engine/parser/files"
 	"github.com/projectdiscovery/katana/pkg/navigation"
-	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/katana/pkg/utils/queue"
@@ -39,6 +38,8 @@ type Crawler struct {
 	knownFiles   *files.KnownFiles
 	previousPIDs map[int32]struct{} // track already running PIDs
 	tempDir      string
+	// outputErr is the fatal output error stopping the crawls
+	outputErr *common.OutputError
 }
 
 // New returns a new standard crawler instance
@@ -111,6 +112,7 @@ func New(options *types.CrawlerOptions) (*Crawler, error) {
 		options:      options,
 		browser:      browser,
 		previousPIDs: previousPIDs,
+		outputErr:    &common.OutputError{},
 		tempDir:      dataStore,
 	}
 	if options.Options.KnownFiles != "" {
@@ -180,7 +182,11 @@ func (c *Crawler) Crawl(rootURL string) error {
 	wg := sizedwaitgroup.New(c.options.Options.Concurrency)
 	running := int32(0)
 	for {
+		if err := c.outputErr.Get(); err != nil {
+			return errors.Wrap(err, "could not write output")
+		}
 		if ctxErr := ctx.Err(); ctxErr != nil {
+			c.outputErr.Set(common.WriteQueuedResults(c.options, queue))
 			return ctxErr
 		}
 		// Quit the crawling for zero items or context timeout
@@ -208,11 +214,13 @@ func (c *Crawler) Crawl(rootURL string) error {
 			if c.options.Options.Delay > 0 {
 				time.Sleep(time.Duration(c.options.Options.Delay) * time.Second)
 			}
-			resp, err := c.navigateRequest(ctx, httpclient, queue, parseResponseCallback, incognitoBrowser, req, hostname)
+			resp, document, err := c.navigateRequest(ctx, httpclient, queue, parseResponseCallback, incognitoBrowser, req, hostname)
 			if err != nil {
 				gologger.Warning().Msgf("Could not request seed URL: %s\n", err)
+				c.outputErr.Set(common.WriteResult(c.options, req, nil, 0, err))
 				return
 			}
+			c.outputErr.Set(common.WriteResult(c.options, req, document.resp, document.latency, nil))
 			if resp == nil || resp.Resp == nil && resp.Reader == nil {
 				return
 			}
@@ -240,30 +248,23 @@ func (c *Crawler) makeParseResponseCallback(queue *queue.VarietyQueue) func(nr n
 			return
 		}
 
-		// Write the found result to output
-		result := &output.Result{
-			Timestamp: time.Now(),
-			Body:      nr.Body,
-			URL:       nr.URL,
-			Source:    nr.Source,
-			Tag:       nr.Tag,
-			Attribute: nr.Attribute,
-		}
-		if nr.Method != http.MethodGet {
-			result.Method = nr.Method
-		}
 		scopeValidated, err := c.options.ScopeManager.Validate(parsed, nr.RootHostname)
 		if err != nil {
 			return
 		}
-		if scopeValidated || c.options.Options.DisplayOutScope {
-			_ = c.options.OutputWriter.Write(result, nil)
+		// Results for the requests which are going to be crawled are
+		// written to output along with their response once navigated.
+		crawlable := nr.Depth < c.options.Options.MaxDepth && scopeValidated
+
+		result := common.NewResult(nr)
+		if !crawlable && (scopeValidated || c.options.Options.DisplayOutScope) {
+			c.outputErr.Set(c.options.OutputWriter.Write(result, nil))
 		}
 		if c.options.Options.OnResult != nil {
 			c.options.Options.OnResult(*result)
 		}
 		// Do not add to crawl queue if max items are reached
-		if nr.Depth >= c.options.Options.MaxDepth || !scopeValidated {
+		if !crawlable {
 			return
 		}
 		queue.Push(nr, nr.Depth)
@@ -4,9 +4,11 @@ import (
 	"mime/multipart"
 	"net/url"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"golang.org/x/net/html"
 )
@@ -505,6 +507,13 @@ func bodyFormTagParser(resp navigation.Response, callback func(navigation.Reques
 			formInputs = append(formInputs, utils.ConvertGoquerySelectionToFormInput(item))
 		})
 
+		form := &output.Form{Action: actionURL, Method: method}
+		for _, input := range formInputs {
+			if input.Name != "" {
+				form.Inputs = append(form.Inputs, input.Name)
+			}
+		}
+
 		dataMap := utils.FormInputFillSuggestions(formInputs)
 		for key, value := range dataMap {
 			if key == "" || value == "" {
@@ -534,6 +543,7 @@ func bodyFormTagParser(resp navigation.Response, callback func(navigation.Reques
 			Tag:          "form",
 			Attribute:    "action",
 			Source:       resp.Resp.Request.URL.String(),
+			Form:         form,
 		}
 		switch method {
 		case "GET":
@@ -586,7 +596,11 @@ func scriptContentRegexParser(resp navigation.Response, callback func(navigation
 		}
 		endpoints := utils.ExtractRelativeEndpoints(text)
 		for _, item := range endpoints {
-			callback(navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "script", "text", resp))
+			request := navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "script", "text", resp)
+			if resp.Options.Options.IncludeSnippet {
+				request.Snippet = getSourceSnippet(text, item)
+			}
+			callback(request)
 		}
 	})
 }
@@ -604,9 +618,14 @@ func scriptJSFileRegexParser(resp navigation.Response, callback func(navigation.
 		return
 	}
 
-	endpoints := utils.ExtractRelativeEndpoints(string(resp.Body))
+	body := string(resp.Body)
+	endpoints := utils.ExtractRelativeEndpoints(body)
 	for _, item := range endpoints {
-		callback(navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "js", "regex", resp))
+		request := navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "js", "regex", resp)
+		if resp.Options.Options.IncludeSnippet {
+			request.Snippet = getSourceSnippet(body, item)
+		}
+		callback(request)
 	}
 }
 
@@ -616,8 +635,64 @@ func bodyScrapeEndpointsParser(resp navigation.Response, callback func(navigatio
 		return
 	}
 
-	endpoints := utils.ExtractBodyEndpoints(string(resp.Body))
+	body := string(resp.Body)
+	endpoints := utils.ExtractBodyEndpoints(body)
 	for _, item := range endpoints {
-		callback(navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "html", "regex", resp))
+		request := navigation.NewNavigationRequestURLFromResponse(item, resp.Resp.Request.URL.String(), "html", "regex", resp)
+		if resp.Options.Options.IncludeSnippet {
+			request.Snippet = getSourceSnippet(body, item)
+		}
+		callback(request)
+	}
+}
+
+const (
+	// snippetContext is the number of bytes of source text included
+	// before and after the match in a snippet
+	snippetContext = 80
+	// maxSnippetLength is the maximum length of a snippet, longer
+	// snippets are truncated with an ellipsis
+	maxSnippetLength = 200
+)
+
+// getSourceSnippet returns the source text around the first occurrence of
+// the match with the whitespace collapsed, marking the truncated ends of
+// the snippet with an ellipsis.
+func getSourceSnippet(text, match string) string {
+	index := strings.Index(text, match)
+	if index == -1 {
+		return ""
+	}
+	start := index - snippetContext
+	if start < 0 {
+		start = 0
+	}
+	end := index + len(match) + snippetContext
+	if end > len(text) {
+		end = len(text)
+	}
+	start, end = alignRuneStart(text, start), alignRuneStart(text, end)
+
+	snippet := strings.Join(strings.Fields(text[start:end]), " ")
+	truncated := end < len(text)
+	if len(snippet) > maxSnippetLength {
+		snippet = snippet[:alignRuneStart(snippet, maxSnippetLength)]
+		truncated = true
+	}
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if truncated {
+		snippet += "..."
+	}
+	return snippet
+}
+
+// alignRuneStart moves the index back to the start of the utf-8 encoded
+// rune it is in
+func alignRuneStart(text string, index int) int {
+	for index > 0 && index < len(text) && !utf8.RuneStart(text[index]) {
+		index--
 	}
+	return index
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/projectdiscovery/katana/pkg/navigation"
+	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/stretchr/testify/require"
 )
@@ -466,12 +467,19 @@ func TestBodyParsers(t *testing.T) {
 			var method string
 			documentReader, _ := goquery.NewDocumentFromReader(strings.NewReader("<form action=\"/test/html/body/form/action-post.found\" method=\"POST\" enctype=\"multipart/form-data\"><input type=\"text\" name=\"test1\" value=\"test\"><input type=\"text\" name=\"test2\" value=\"test\"></form>"))
 			resp := navigation.Response{Resp: &http.Response{Request: &http.Request{URL: parsed}}, Reader: documentReader, Options: opts}
+			var form *output.Form
 			bodyFormTagParser(resp, func(resp navigation.Request) {
 				gotURL = resp.URL
 				method = resp.Method
+				form = resp.Form
 			})
 			require.Equal(t, "https://security-crawl-maze.app/test/html/body/form/action-post.found", gotURL, "could not get correct url")
 			require.Equal(t, "POST", method, "could not get correct method")
+			require.Equal(t, &output.Form{
+				Action: "https://security-crawl-maze.app/test/html/body/form/action-post.found",
+				Method: "POST",
+				Inputs: []string{"test1", "test2"},
+			}, form, "could not get correct form")
 		})
 	})
 
@@ -539,3 +547,28 @@ func TestScriptParsers(t *testing.T) {
 
 	})
 }
+
+func TestSourceSnippet(t *testing.T) {
+	parsed, _ := url.Parse("https://security-crawl-maze.app/html/script/xyz/data.js")
+	body := strings.Repeat("a", 100) + "\nvar endpoint = '/test/html/script/snippet.do';\n" + strings.Repeat("b", 100)
+
+	var gotSnippet string
+	resp := navigation.Response{Options: &types.CrawlerOptions{Options: &types.Options{ScrapeJSResponses: true, IncludeSnippet: true}}, Resp: &http.Response{Request: &http.Request{URL: parsed}}, Body: []byte(body)}
+	scriptJSFileRegexParser(resp, func(resp navigation.Request) {
+		gotSnippet = resp.Snippet
+	})
+	require.True(t, strings.HasPrefix(gotSnippet, "..."), "could not mark truncated snippet start")
+	require.True(t, strings.HasSuffix(gotSnippet, "..."), "could not mark truncated snippet end")
+	require.Contains(t, gotSnippet, " var endpoint = '/test/html/script/snippet.do'; ", "could not get snippet around match")
+
+	require.Equal(t, "fetch('/api/v1')", getSourceSnippet("fetch('/api/v1')", "/api/v1"), "could not get untruncated snippet")
+	require.Equal(t, "", getSourceSnippet("fetch('/api/v1')", "/missing"), "could not get empty snippet")
+	require.Len(t, getSourceSnippet("x"+strings.Repeat("/api", 100)+"x", strings.Repeat("/api", 100)), maxSnippetLength+3, "could not limit snippet length")
+
+	gotSnippet = ""
+	resp.Options.Options.IncludeSnippet = false
+	scriptJSFileRegexParser(resp, func(resp navigation.Request) {
+		gotSnippet = resp.Snippet
+	})
+	require.Empty(t, gotSnippet, "could not disable snippets")
+}
@@ -6,9 +6,11 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/pkg/errors"
+	"github.com/projectdiscovery/katana/pkg/engine/common"
 	"github.com/projectdiscovery/katana/pkg/navigation"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/retryablehttp-go"
@@ -24,6 +26,7 @@ func (c *Crawler) makeRequest(ctx context.Context, request navigation.Request, r
 	ctx = context.WithValue(ctx, navigation.Depth{}, depth)
 	httpReq, err := http.NewRequestWithContext(ctx, request.Method, request.URL, nil)
 	if err != nil {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, 0, err))
 		return response, err
 	}
 	if request.Body != "" && request.Method != "GET" {
@@ -31,6 +34,7 @@ func (c *Crawler) makeRequest(ctx context.Context, request navigation.Request, r
 	}
 	req, err := retryablehttp.FromRequest(httpReq)
 	if err != nil {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, 0, err))
 		return response, err
 	}
 	req.Header.Set("User-Agent", utils.WebUserAgent())
@@ -43,7 +47,9 @@ func (c *Crawler) makeRequest(ctx context.Context, request navigation.Request, r
 		req.Header.Set(k, v)
 	}
 
+	start := time.Now()
 	resp, err := httpclient.Do(req)
+	latency := time.Since(start)
 	if resp != nil {
 		defer func() {
 			if resp.Body != nil && resp.StatusCode != http.StatusSwitchingProtocols {
@@ -53,22 +59,28 @@ func (c *Crawler) makeRequest(ctx context.Context, request navigation.Request, r
 		}()
 	}
 	if err != nil {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, latency, err))
 		return response, err
 	}
 	if resp.StatusCode == http.StatusSwitchingProtocols {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, latency, nil))
 		return response, nil
 	}
 	limitReader := io.LimitReader(resp.Body, int64(c.options.Options.BodyReadSize))
 	data, err := io.ReadAll(limitReader)
 	if err != nil {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, latency, err))
 		return response, err
 	}
+
+	// the responses with duplicate content are neither stored nor
+	// parsed, only their result is written
 	if !c.options.UniqueFilter.UniqueContent(data) {
+		c.outputErr.Set(common.WriteResult(c.options, request, nil, latency, nil))
 		return navigation.Response{}, nil
 	}
-
 	resp.Body = io.NopCloser(strings.NewReader(string(data)))
-	_ = c.options.OutputWriter.Write(nil, resp)
+	c.outputErr.Set(common.WriteResult(c.options, request, resp, latency, nil))
 
 	response.Body = data
 	response.Resp = resp
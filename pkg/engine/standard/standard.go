@@ -16,7 +16,6 @@ import (
 	"github.com/projectdiscovery/katana/pkg/engine/parser"
 	"github.com/projectdiscovery/katana/pkg/engine/parser/files"
 	"github.com/projectdiscovery/katana/pkg/navigation"
-	"github.com/projectdiscovery/katana/pkg/output"
 	"github.com/projectdiscovery/katana/pkg/types"
 	"github.com/projectdiscovery/katana/pkg/utils"
 	"github.com/projectdiscovery/katana/pkg/utils/queue"
@@ -28,13 +27,16 @@ type Crawler struct {
 	headers    map[string]string
 	knownFiles *files.KnownFiles
 	options    *types.CrawlerOptions
+	// outputErr is the fatal output error stopping the crawls
+	outputErr *common.OutputError
 }
 
 // New returns a new standard crawler instance
 func New(options *types.CrawlerOptions) (*Crawler, error) {
 	crawler := &Crawler{
-		headers: options.Options.ParseCustomHeaders(),
-		options: options,
+		headers:   options.Options.ParseCustomHeaders(),
+		options:   options,
+		outputErr: &common.OutputError{},
 	}
 	if options.Options.KnownFiles != "" {
 		httpclient, _, err := common.BuildClient(options.Dialer, options.Options, nil)
@@ -88,7 +90,11 @@ func (c *Crawler) Crawl(rootURL string) error {
 	wg := sizedwaitgroup.New(c.options.Options.Concurrency)
 	running := int32(0)
 	for {
+		if err := c.outputErr.Get(); err != nil {
+			return errors.Wrap(err, "could not write output")
+		}
 		if ctxErr := ctx.Err(); ctxErr != nil {
+			c.outputErr.Set(common.WriteQueuedResults(c.options, queue))
 			return ctxErr
 		}
 		// Quit the crawling for zero items or context timeout
@@ -147,30 +153,23 @@ func (c *Crawler) makeParseResponseCallback(queue *queue.VarietyQueue) func(nr n
 			return
 		}
 
-		// Write the found result to output
-		result := &output.Result{
-			Timestamp: time.Now(),
-			Body:      nr.Body,
-			URL:       nr.URL,
-			Source:    nr.Source,
-			Tag:       nr.Tag,
-			Attribute: nr.Attribute,
-		}
-		if nr.Method != http.MethodGet {
-			result.Method = nr.Method
-		}
 		scopeValidated, err := c.options.ScopeManager.Validate(parsed, nr.RootHostname)
 		if err != nil {
 			return
 		}
-		if scopeValidated || c.options.Options.DisplayOutScope {
-			_ = c.options.OutputWriter.Write(result, nil)
+		// Results for the requests which are going to be crawled are
+		// written to output along with their response once requested.
+		crawlable := nr.Depth < c.options.Options.MaxDepth && scopeValidated
+
+		result := common.NewResult(nr)
+		if !crawlable && (scopeValidated || c.options.Options.DisplayOutScope) {
+			c.outputErr.Set(c.options.OutputWriter.Write(result, nil))
 		}
 		if c.options.Options.OnResult != nil {
 			c.options.Options.OnResult(*result)
 		}
 		// Do not add to crawl queue if max items are reached
-		if nr.Depth >= c.options.Options.MaxDepth || !scopeValidated {
+		if !crawlable {
 			return
 		}
 		queue.Push(nr, nr.Depth)
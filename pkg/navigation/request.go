@@ -2,6 +2,8 @@ package navigation
 
 import (
 	"strings"
+
+	"github.com/projectdiscovery/katana/pkg/output"
 )
 
 // Depth is the depth of a navigation
@@ -17,7 +19,9 @@ type Request struct {
 	Tag          string
 	Attribute    string
 	RootHostname string
-	Source       string // source is the source of the request
+	Source       string       // source is the source of the request
+	Form         *output.Form // form is the discovered form for form requests
+	Snippet      string       // snippet is the source text around the extracted URL
 }
 
 // RequestURL returns the request URL for the navigation
@@ -0,0 +1,118 @@
+package output
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// baseline is a set of known URLs which are dropped from output, with the
+// new URLs written to output added to the baseline file on close.
+type baseline struct {
+	path   string
+	update bool
+	// known are the URLs of the baseline file, which are only read after
+	// the baseline is loaded
+	known map[string]struct{}
+
+	mutex *sync.Mutex
+	added map[string]struct{}
+	order []string
+}
+
+// newBaseline loads the known URLs of the baseline file, a missing file
+// is an empty baseline which is created on close if updated
+func newBaseline(path string, update bool) (*baseline, error) {
+	known, err := readBaselineURLs(path)
+	if err != nil {
+		return nil, err
+	}
+	return &baseline{path: path, update: update, known: known, mutex: &sync.Mutex{}, added: make(map[string]struct{})}, nil
+}
+
+// readBaselineURLs returns the URLs of the baseline file, which has a URL
+// per line or the json results of a previous crawl
+func readBaselineURLs(path string) (map[string]struct{}, error) {
+	urls := make(map[string]struct{})
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return urls, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open baseline file")
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "{") {
+			var result Result
+			if err := jsoniter.UnmarshalFromString(line, &result); err != nil {
+				return nil, errors.Wrap(err, "could not parse baseline result")
+			}
+			line = result.URL
+		}
+		if line != "" {
+			urls[line] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "could not read baseline file")
+	}
+	return urls, nil
+}
+
+// contains returns true if the URL is in the baseline file
+func (b *baseline) contains(URL string) bool {
+	_, ok := b.known[URL]
+	return ok
+}
+
+// add adds the URL written to output to the URLs appended to the baseline
+func (b *baseline) add(URL string) {
+	if !b.update {
+		return
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.added[URL]; ok {
+		return
+	}
+	b.added[URL] = struct{}{}
+	b.order = append(b.order, URL)
+}
+
+// write appends the new URLs to the baseline file, which then has the
+// union of the known and new URLs
+func (b *baseline) write() error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if !b.update || len(b.order) == 0 {
+		return nil
+	}
+	file, err := os.OpenFile(b.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return errors.Wrap(err, "could not open baseline file")
+	}
+	writer := bufio.NewWriter(file)
+	for _, URL := range b.order {
+		_, _ = writer.WriteString(URL + "\n")
+	}
+	err = writer.Flush()
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not update baseline file")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.txt")
+	require.Nil(t, os.WriteFile(path, []byte("https://example.com/\n\n{\"endpoint\":\"https://example.com/a\"}\n"), 0644), "could not write baseline")
+
+	var results []string
+	writer, err := NewWithOptions(&Options{Silent: true, BaselineFile: path, UpdateBaseline: true, OnResult: func(result *Result) {
+		results = append(results, result.URL)
+	}})
+	require.Nil(t, err, "could not create writer")
+	for _, URL := range []string{"https://example.com/", "https://example.com/a", "https://example.com/b", "https://example.com/b", "https://example.com/c"} {
+		require.Nil(t, writer.Write(&Result{URL: URL}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Equal(t, []string{"https://example.com/b", "https://example.com/b", "https://example.com/c"}, results, "could not drop baseline urls")
+
+	known, err := readBaselineURLs(path)
+	require.Nil(t, err, "could not read updated baseline")
+	require.Len(t, known, 4, "could not update baseline with the union")
+	data, err := os.ReadFile(path)
+	require.Nil(t, err, "could not read updated baseline")
+	require.Contains(t, string(data), "\nhttps://example.com/b\nhttps://example.com/c\n", "could not append new urls once")
+}
+
+func TestBaselineMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.txt")
+
+	writer, err := NewWithOptions(&Options{Silent: true, BaselineFile: path})
+	require.Nil(t, err, "could not create writer with missing baseline")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err), "could create baseline without update")
+
+	writer, err = NewWithOptions(&Options{Silent: true, BaselineFile: path, UpdateBaseline: true})
+	require.Nil(t, err, "could not create writer with missing baseline")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+	data, err := os.ReadFile(path)
+	require.Nil(t, err, "could not create baseline")
+	require.Equal(t, "https://example.com/\n", string(data), "could not create baseline")
+}
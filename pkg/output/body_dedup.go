@@ -0,0 +1,44 @@
+package output
+
+import (
+	"context"
+)
+
+// bufferBodyResult buffers the result to write it on Close, counting it as
+// a duplicate of the first buffered result with the same body hash.
+func (w *StandardWriter) bufferBodyResult(event *Result) {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	if first, ok := w.bodyResults[event.BodyHash]; ok {
+		first.DuplicateCount++
+		return
+	}
+	w.bodyResults[event.BodyHash] = event
+	w.bodyResultsOrder = append(w.bodyResultsOrder, event)
+}
+
+// writeBodyResults writes the first result for every unique body in the
+// write order, with the number of duplicates of the body.
+func (w *StandardWriter) writeBodyResults() error {
+	w.outputMutex.Lock()
+	results := w.bodyResultsOrder
+	w.bodyResultsOrder = nil
+	w.bodyResults = make(map[string]*Result)
+	w.outputMutex.Unlock()
+
+	for _, result := range results {
+		if w.sortOutput {
+			w.bufferSortedResult(result)
+		} else if err := w.writeResult(context.Background(), result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isBodyDedupResult returns true if the result has a non-empty response
+// body to deduplicate by
+func isBodyDedupResult(event *Result) bool {
+	return event.BodyHash != "" && event.ContentLength != 0
+}
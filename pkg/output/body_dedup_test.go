@@ -0,0 +1,43 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupByBody(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.txt")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: file, DedupByBody: true, Fields: "url,duplicate_count", Silent: true, Concurrency: 4})
+	require.Nil(t, err, "could not create writer")
+	for _, page := range []struct {
+		url  string
+		body string
+	}{
+		{"https://example.com/a", "template"},
+		{"https://example.com/b", "unique"},
+		{"https://example.com/c", "template"},
+		{"https://example.com/d", "template"},
+		{"https://example.com/e", ""},
+		{"https://example.com/f", ""},
+	} {
+		resp := &http.Response{StatusCode: 200, ContentLength: -1, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(page.body))}
+		require.Nil(t, standardWriter.Write(&Result{URL: page.url}, resp), "could not write result")
+	}
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, []string{
+		"https://example.com/e,0",
+		"https://example.com/f,0",
+		"https://example.com/a,2",
+		"https://example.com/b,0",
+	}, strings.Fields(string(data)), "could not dedup results by body")
+}
@@ -0,0 +1,193 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// Archive formats for bundling the output on close
+const (
+	bundleFormatZip   = "zip"
+	bundleFormatTarGz = "tar.gz"
+)
+
+// getBundleFormat returns the archive format from the bundle file extension
+func getBundleFormat(file string) (string, error) {
+	lower := strings.ToLower(file)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return bundleFormatZip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return bundleFormatTarGz, nil
+	default:
+		return "", errors.Errorf("unsupported bundle file %s, expected a .zip or .tar.gz file", file)
+	}
+}
+
+// getBundlePaths returns the paths of the output artifacts to bundle,
+// which are the output files, the summary and graph files and the stored
+// responses directory including the index.
+func (w *StandardWriter) getBundlePaths() []string {
+	var paths []string
+	if w.outputFile != nil {
+		paths = append(paths, w.outputFile.getPaths()...)
+	}
+	if w.split != nil {
+		paths = append(paths, w.split.getPaths()...)
+	}
+	if w.jsOutput != nil {
+		paths = append(paths, w.jsOutput.getPaths()...)
+	}
+	if w.summary != nil && w.summaryFile != "" {
+		paths = append(paths, w.summaryFile)
+	}
+	if w.graph != nil {
+		paths = append(paths, w.graphFile)
+	}
+	if w.storeResponse {
+		paths = append(paths, w.storeResponseDir)
+	}
+	return paths
+}
+
+// writeBundle packages the output artifacts into the bundle archive,
+// removing the originals afterwards if requested.
+//
+// It must be called after the output files are closed.
+func (w *StandardWriter) writeBundle() error {
+	paths := w.getBundlePaths()
+
+	file, err := os.Create(w.bundleFile)
+	if err != nil {
+		return errors.Wrap(err, "could not create bundle file")
+	}
+	if w.bundleFormat == bundleFormatZip {
+		err = writeZipBundle(file, w.bundleFile, paths)
+	} else {
+		err = writeTarGzBundle(file, w.bundleFile, paths)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(w.bundleFile)
+		return errors.Wrap(err, "could not write bundle file")
+	}
+	if w.bundleRemove {
+		for _, path := range paths {
+			err = multierr.Append(err, os.RemoveAll(path))
+		}
+	}
+	return err
+}
+
+// bundleEntry is a file to write to the bundle archive
+type bundleEntry struct {
+	// name is the slash separated name of the file in the archive
+	name string
+	path string
+	info os.FileInfo
+}
+
+// walkBundlePaths calls the callback for every file in the paths, naming
+// them relative to the parent directory of each path and skipping the
+// bundle file itself. Missing paths are skipped.
+func walkBundlePaths(bundleFile string, paths []string, callback func(entry bundleEntry) error) error {
+	bundlePath, _ := filepath.Abs(bundleFile)
+
+	for _, root := range paths {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			continue
+		}
+		base := filepath.Dir(root)
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			if absPath, _ := filepath.Abs(path); absPath == bundlePath {
+				return nil
+			}
+			name, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+			return callback(bundleEntry{name: filepath.ToSlash(name), path: path, info: info})
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeZipBundle writes the paths to a zip archive
+func writeZipBundle(output io.Writer, bundleFile string, paths []string) error {
+	archive := zip.NewWriter(output)
+	err := walkBundlePaths(bundleFile, paths, func(entry bundleEntry) error {
+		header, err := zip.FileInfoHeader(entry.info)
+		if err != nil {
+			return err
+		}
+		header.Name = entry.name
+		header.Method = zip.Deflate
+
+		writer, err := archive.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		return copyBundleFile(writer, entry.path)
+	})
+	if err != nil {
+		archive.Close()
+		return err
+	}
+	return archive.Close()
+}
+
+// writeTarGzBundle writes the paths to a gzip compressed tar archive
+func writeTarGzBundle(output io.Writer, bundleFile string, paths []string) error {
+	compressed := gzip.NewWriter(output)
+	archive := tar.NewWriter(compressed)
+	err := walkBundlePaths(bundleFile, paths, func(entry bundleEntry) error {
+		header, err := tar.FileInfoHeader(entry.info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = entry.name
+
+		if err := archive.WriteHeader(header); err != nil {
+			return err
+		}
+		return copyBundleFile(archive, entry.path)
+	})
+	if err == nil {
+		err = archive.Close()
+	}
+	if closeErr := compressed.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// copyBundleFile copies the contents of the file to the archive writer
+func copyBundleFile(writer io.Writer, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	return err
+}
@@ -0,0 +1,95 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleOnClose(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "bundle.zip")
+
+	writer, err := NewWithOptions(&Options{
+		OutputFile:            filepath.Join(dir, "output.txt"),
+		StoreResponse:         true,
+		StoreResponseDir:      filepath.Join(dir, "responses"),
+		BundleOnClose:         bundle,
+		BundleRemoveOriginals: true,
+	})
+	require.Nil(t, err, "could not create writer")
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("body")),
+		Request:    httptest.NewRequest(http.MethodGet, "https://example.com/", nil),
+	}
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, resp), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	archive, err := zip.OpenReader(bundle)
+	require.Nil(t, err, "could not open bundle")
+	defer archive.Close()
+
+	var names []string
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	require.Contains(t, names, "output.txt", "could not bundle output file")
+	require.Contains(t, names, "responses/index.txt", "could not bundle index file")
+	require.Len(t, names, 3, "could not bundle stored response")
+
+	_, err = os.Stat(filepath.Join(dir, "output.txt"))
+	require.True(t, os.IsNotExist(err), "could not remove output file")
+	_, err = os.Stat(filepath.Join(dir, "responses"))
+	require.True(t, os.IsNotExist(err), "could not remove responses directory")
+}
+
+func TestBundleOnCloseTarGz(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "bundle.tar.gz")
+
+	writer, err := NewWithOptions(&Options{
+		OutputFile:    filepath.Join(dir, "output.txt"),
+		BundleOnClose: bundle,
+	})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	file, err := os.Open(bundle)
+	require.Nil(t, err, "could not open bundle")
+	defer file.Close()
+	compressed, err := gzip.NewReader(file)
+	require.Nil(t, err, "could not read gzip bundle")
+	archive := tar.NewReader(compressed)
+
+	header, err := archive.Next()
+	require.Nil(t, err, "could not read tar entry")
+	require.Equal(t, "output.txt", header.Name, "could not bundle output file")
+	data, err := io.ReadAll(archive)
+	require.Nil(t, err, "could not read bundled output")
+	require.Equal(t, "https://example.com/\n", string(data), "could not get bundled output")
+
+	_, err = archive.Next()
+	require.Equal(t, io.EOF, err, "got unexpected bundle entries")
+	_, err = os.Stat(filepath.Join(dir, "output.txt"))
+	require.Nil(t, err, "could not keep output file")
+}
+
+func TestBundleFormat(t *testing.T) {
+	_, err := NewWithOptions(&Options{BundleOnClose: "bundle.rar"})
+	require.Error(t, err, "got no error for unsupported bundle format")
+}
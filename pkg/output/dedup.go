@@ -0,0 +1,62 @@
+package output
+
+import (
+	"crypto/sha1"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Keys for the deduplication of results
+const (
+	DedupKeyURL        = "url"
+	DedupKeyMethodURL  = "method-url"
+	DedupKeyURLNoQuery = "url-no-query"
+)
+
+// deduper is an in-memory deduplicator for the written results.
+//
+// Only the sha1 hash of the key is kept per result, so the memory usage
+// grows by roughly 40 bytes (hash and map overhead) for every unique
+// result. Once the maximum entries are reached, new keys are no longer
+// tracked and are always written.
+type deduper struct {
+	key        string
+	maxEntries int
+	mutex      *sync.Mutex
+	seen       map[[sha1.Size]byte]struct{}
+}
+
+// newDeduper creates a new deduplicator for the key and maximum entries.
+// A non-positive maximum entries value tracks all the keys.
+func newDeduper(key string, maxEntries int) (*deduper, error) {
+	switch key {
+	case "":
+		key = DedupKeyMethodURL
+	case DedupKeyURL, DedupKeyMethodURL, DedupKeyURLNoQuery:
+	default:
+		return nil, errors.Errorf("invalid dedup key %s specified", key)
+	}
+	return &deduper{
+		key:        key,
+		maxEntries: maxEntries,
+		mutex:      &sync.Mutex{},
+		seen:       make(map[[sha1.Size]byte]struct{}),
+	}, nil
+}
+
+// isUnique returns true if the result has not been seen before
+func (d *deduper) isUnique(event *Result) bool {
+	hash := sha1.Sum([]byte(event.Key(d.key)))
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, ok := d.seen[hash]; ok {
+		return false
+	}
+	if d.maxEntries <= 0 || len(d.seen) < d.maxEntries {
+		d.seen[hash] = struct{}{}
+	}
+	return true
+}
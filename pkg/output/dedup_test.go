@@ -0,0 +1,37 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduper(t *testing.T) {
+	d, err := newDeduper("", 0)
+	require.Nil(t, err, "could not create deduper")
+	require.True(t, d.isUnique(&Result{URL: "https://example.com/"}), "could not get unique result")
+	require.False(t, d.isUnique(&Result{Method: "get", URL: "https://example.com/"}), "could not dedup same method and url")
+	require.True(t, d.isUnique(&Result{Method: "POST", URL: "https://example.com/"}), "could dedup different method")
+
+	d, err = newDeduper(DedupKeyURL, 0)
+	require.Nil(t, err, "could not create deduper")
+	require.True(t, d.isUnique(&Result{Method: "GET", URL: "https://example.com/"}), "could not get unique result")
+	require.False(t, d.isUnique(&Result{Method: "POST", URL: "https://example.com/"}), "could not dedup same url")
+
+	d, err = newDeduper(DedupKeyURLNoQuery, 0)
+	require.Nil(t, err, "could not create deduper")
+	require.True(t, d.isUnique(&Result{URL: "https://example.com/a?b=1"}), "could not get unique result")
+	require.False(t, d.isUnique(&Result{URL: "https://example.com/a?c=2"}), "could not dedup same url without query")
+
+	_, err = newDeduper("body", 0)
+	require.Error(t, err, "got no error for invalid dedup key")
+}
+
+func TestDeduperMaxEntries(t *testing.T) {
+	d, err := newDeduper(DedupKeyURL, 1)
+	require.Nil(t, err, "could not create deduper")
+	require.True(t, d.isUnique(&Result{URL: "https://example.com/a"}), "could not get unique result")
+	require.True(t, d.isUnique(&Result{URL: "https://example.com/b"}), "could not get unique result")
+	require.True(t, d.isUnique(&Result{URL: "https://example.com/b"}), "could track key over max entries")
+	require.False(t, d.isUnique(&Result{URL: "https://example.com/a"}), "could not dedup tracked key")
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"strconv"
+	"time"
+)
+
+// Duration is a time.Duration which is serialized as a
+// fractional number of milliseconds, eg. 123.456
+type Duration time.Duration
+
+// Milliseconds returns the duration as fractional milliseconds
+func (d Duration) Milliseconds() float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// String returns the duration formatted as milliseconds
+func (d Duration) String() string {
+	return strconv.FormatFloat(d.Milliseconds(), 'f', -1, 64)
+}
+
+// MarshalJSON marshals the duration as milliseconds
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON unmarshals the duration from milliseconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*d = Duration(value * float64(time.Millisecond))
+	return nil
+}
@@ -0,0 +1,342 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	// DefaultElasticsearchIndex is the default index of the result documents
+	DefaultElasticsearchIndex = "katana"
+	// DefaultElasticsearchBatchSize is the default number of buffered
+	// documents after which a bulk request is sent
+	DefaultElasticsearchBatchSize = 500
+	// DefaultElasticsearchMaxRetries is the default number of retries of
+	// the failed documents of a bulk request
+	DefaultElasticsearchMaxRetries = 3
+	// DefaultElasticsearchFlushInterval is the default interval at which
+	// the buffered documents are sent
+	DefaultElasticsearchFlushInterval = 5 * time.Second
+)
+
+// ElasticsearchOptions contains the options of the elasticsearch writer
+type ElasticsearchOptions struct {
+	// URL is the base URL of the elasticsearch or opensearch cluster,
+	// the documents are sent to its _bulk endpoint.
+	URL string
+	// Index is the index of the result documents
+	Index string
+	// AuthHeader is the optional authentication header sent with the
+	// bulk requests, like "Authorization: ApiKey <key>". A value without
+	// a header name is sent as the Authorization header.
+	AuthHeader string
+	// BatchSize is the number of buffered documents after which a bulk
+	// request is sent, the default is used if it is not positive.
+	BatchSize int
+	// MaxRetries is the number of times the failed documents of a bulk
+	// request are retried, the default is used if it is not positive.
+	MaxRetries int
+	// FlushInterval is the interval at which the buffered documents are
+	// sent, the default is used if it is not positive.
+	FlushInterval time.Duration
+	// Client is the http client to send the bulk requests with, the
+	// default client is used if it is nil.
+	Client *http.Client
+}
+
+// elasticsearchWriter is a writer which queues the results and sends them
+// as documents in bulk requests to an elasticsearch cluster from a
+// background goroutine.
+type elasticsearchWriter struct {
+	options     ElasticsearchOptions
+	endpoint    string
+	headerName  string
+	headerValue string
+	// retryBackoff is the wait before the first retry, it is doubled
+	// after each retry.
+	retryBackoff time.Duration
+
+	queue  chan elasticsearchItem
+	done   chan struct{}
+	closed bool
+	mutex  *sync.RWMutex
+	// err is the first error sending the documents since the last flush
+	err error
+}
+
+// elasticsearchItem is a queued document, or a flush request if flushed
+// is set
+type elasticsearchItem struct {
+	document []byte
+	flushed  chan error
+}
+
+// elasticsearchBulkResponse is the response of a bulk request
+type elasticsearchBulkResponse struct {
+	Errors bool                               `json:"errors"`
+	Items  []map[string]elasticsearchBulkItem `json:"items"`
+}
+
+// elasticsearchBulkItem is the result of a single bulk request action
+type elasticsearchBulkItem struct {
+	Status int `json:"status"`
+	Error  struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// NewElasticsearchWriter returns a writer which sends the results as
+// documents to the _bulk endpoint of an elasticsearch or opensearch cluster.
+//
+// The documents are sent from a bounded in-memory queue once the batch
+// size is reached, at the flush interval and on Flush and Close. Documents
+// rejected with a retryable status are retried with exponential backoff,
+// the other rejected documents are logged and dropped.
+func NewElasticsearchWriter(options ElasticsearchOptions) (Writer, error) {
+	if options.URL == "" {
+		return nil, errors.New("no elasticsearch url specified")
+	}
+	if options.Index == "" {
+		options.Index = DefaultElasticsearchIndex
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = DefaultElasticsearchBatchSize
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = DefaultElasticsearchMaxRetries
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultElasticsearchFlushInterval
+	}
+	if options.Client == nil {
+		options.Client = http.DefaultClient
+	}
+	writer := &elasticsearchWriter{
+		options:      options,
+		endpoint:     strings.TrimSuffix(options.URL, "/") + "/_bulk",
+		retryBackoff: 500 * time.Millisecond,
+		queue:        make(chan elasticsearchItem, options.BatchSize),
+		done:         make(chan struct{}),
+		mutex:        &sync.RWMutex{},
+	}
+	if options.AuthHeader != "" {
+		writer.headerName, writer.headerValue = parseAuthHeader(options.AuthHeader)
+	}
+	go writer.run()
+	return writer, nil
+}
+
+// parseAuthHeader returns the name and value of a "Name: value" header,
+// defaulting the name to Authorization.
+func parseAuthHeader(header string) (string, string) {
+	parts := strings.SplitN(header, ":", 2)
+	name := strings.TrimSpace(parts[0])
+	if len(parts) == 2 && name != "" && !strings.Contains(name, " ") {
+		return name, strings.TrimSpace(parts[1])
+	}
+	return "Authorization", strings.TrimSpace(header)
+}
+
+// Write queues the event as a document, blocking while the queue is full
+func (w *elasticsearchWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	document, err := jsoniter.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal elasticsearch document")
+	}
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return errors.New("elasticsearch writer is closed")
+	}
+	w.queue <- elasticsearchItem{document: document}
+	return nil
+}
+
+// run sends the queued documents until the queue is closed, once the
+// batch size is reached and at the flush interval.
+func (w *elasticsearchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+
+	var documents [][]byte
+	for {
+		select {
+		case <-ticker.C:
+			documents = w.sendDocuments(documents)
+		case item, ok := <-w.queue:
+			if !ok {
+				w.sendDocuments(documents)
+				return
+			}
+			if item.flushed != nil {
+				documents = w.sendDocuments(documents)
+				item.flushed <- w.err
+				w.err = nil
+				continue
+			}
+			documents = append(documents, item.document)
+			if len(documents) >= w.options.BatchSize {
+				documents = w.sendDocuments(documents)
+			}
+		}
+	}
+}
+
+// sendDocuments sends the documents, keeping the first error for the
+// next flush, and returns the empty documents for reuse
+func (w *elasticsearchWriter) sendDocuments(documents [][]byte) [][]byte {
+	if len(documents) == 0 {
+		return documents
+	}
+	if err := w.send(documents); err != nil && w.err == nil {
+		w.err = err
+	}
+	return documents[:0]
+}
+
+// send sends the documents in a bulk request, retrying the failed
+// documents with backoff
+func (w *elasticsearchWriter) send(documents [][]byte) error {
+	backoff := w.retryBackoff
+	for attempt := 0; len(documents) > 0; attempt++ {
+		failed, retryable, err := w.sendBulk(documents)
+		if err == nil && len(failed) == 0 {
+			return nil
+		}
+		if err != nil && !retryable {
+			return errors.Wrapf(err, "could not send %d elasticsearch documents", len(documents))
+		}
+		if attempt >= w.options.MaxRetries {
+			if err != nil {
+				return errors.Wrapf(err, "could not send %d elasticsearch documents", len(documents))
+			}
+			return errors.Errorf("could not send %d elasticsearch documents after %d retries", len(failed), attempt)
+		}
+		if err == nil {
+			documents = failed
+		}
+		gologger.Warning().Msgf("Retrying %d elasticsearch documents in %s\n", len(documents), backoff)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return nil
+}
+
+// sendBulk sends the documents in a bulk request, returning the documents
+// rejected with a retryable status.
+//
+// If an error is returned, it also returns whether the whole request can
+// be retried.
+func (w *elasticsearchWriter) sendBulk(documents [][]byte) ([][]byte, bool, error) {
+	action := []byte(`{"index":{"_index":` + jsonString(w.options.Index) + "}}\n")
+	body := &bytes.Buffer{}
+	for _, document := range documents {
+		body.Write(action)
+		body.Write(document)
+		body.WriteByte('\n')
+	}
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, body)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "could not create elasticsearch request")
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if w.headerName != "" {
+		req.Header.Set(w.headerName, w.headerValue)
+	}
+	resp, err := w.options.Client.Do(req)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "could not send elasticsearch request")
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, true, errors.Wrap(err, "could not read elasticsearch response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, isRetryableStatus(resp.StatusCode), errors.Errorf("elasticsearch returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	response := elasticsearchBulkResponse{}
+	if err := jsoniter.Unmarshal(data, &response); err != nil {
+		return nil, false, errors.Wrap(err, "could not parse elasticsearch response")
+	}
+	if !response.Errors {
+		return nil, false, nil
+	}
+
+	var failed [][]byte
+	for i, actions := range response.Items {
+		if i >= len(documents) {
+			break
+		}
+		for _, item := range actions {
+			if item.Status < 300 {
+				continue
+			}
+			if isRetryableStatus(item.Status) {
+				failed = append(failed, documents[i])
+				continue
+			}
+			gologger.Warning().Msgf("Could not index elasticsearch document (%d %s): %s\n", item.Status, item.Error.Type, item.Error.Reason)
+		}
+	}
+	return failed, false, nil
+}
+
+// isRetryableStatus returns true if a document rejected with the status
+// can be retried
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// jsonString returns the value as a quoted json string
+func jsonString(value string) string {
+	data, _ := jsoniter.Marshal(value)
+	return string(data)
+}
+
+// Flush waits until the queued documents are sent, returning the first
+// error sending the documents since the last flush
+func (w *elasticsearchWriter) Flush() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return nil
+	}
+	flushed := make(chan error, 1)
+	w.queue <- elasticsearchItem{flushed: flushed}
+	return <-flushed
+}
+
+// Close sends the queued documents and stops the writer, returning the
+// first error sending the documents since the last flush
+func (w *elasticsearchWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mutex.Unlock()
+
+	<-w.done
+	return w.err
+}
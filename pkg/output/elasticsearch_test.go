@@ -0,0 +1,124 @@
+package output
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestElasticsearchWriter(t *testing.T) {
+	var mutex sync.Mutex
+	var requests [][]string
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/_bulk", r.URL.Path, "could not use bulk endpoint")
+		require.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"), "could not set content type")
+
+		var lines []string
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		mutex.Lock()
+		requests = append(requests, lines)
+		authHeader = r.Header.Get("Authorization")
+		attempt := len(requests)
+		mutex.Unlock()
+
+		// reject the second document of the first request with a
+		// retryable status and the third with a mapping error
+		if attempt == 1 {
+			_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	writer, err := NewElasticsearchWriter(ElasticsearchOptions{URL: server.URL + "/", Index: "crawl", AuthHeader: "ApiKey secret", BatchSize: 3})
+	require.Nil(t, err, "could not create elasticsearch writer")
+	writer.(*elasticsearchWriter).retryBackoff = 0
+
+	for _, URL := range []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"} {
+		require.Nil(t, writer.Write(&Result{URL: URL, StatusCode: 200}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Flush(), "could not flush elasticsearch writer")
+	require.Len(t, requests, 2, "could not send batch and retry")
+	require.Len(t, requests[0], 6, "could not send bulk documents")
+	require.Equal(t, `{"index":{"_index":"crawl"}}`, requests[0][0], "could not send index action")
+	require.Equal(t, "ApiKey secret", authHeader, "could not send auth header")
+
+	document := Result{}
+	require.Nil(t, jsoniter.Unmarshal([]byte(requests[0][1]), &document), "could not parse document")
+	require.Equal(t, "https://example.com/a", document.URL, "could not send result fields")
+	require.Len(t, requests[1], 2, "could not retry only the rejected document")
+	require.True(t, strings.Contains(requests[1][1], "https://example.com/b"), "could not retry the rejected document")
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/d"}, nil), "could not write result")
+	require.Len(t, requests, 2, "sent documents before batch size")
+	require.Nil(t, writer.Close(), "could not close elasticsearch writer")
+	require.Len(t, requests, 3, "could not send buffered documents on close")
+}
+
+func TestElasticsearchWriterRetries(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	writer, err := NewElasticsearchWriter(ElasticsearchOptions{URL: server.URL, MaxRetries: 2})
+	require.Nil(t, err, "could not create elasticsearch writer")
+	writer.(*elasticsearchWriter).retryBackoff = 0
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.NotNil(t, writer.Flush(), "could not get error after retries")
+	require.Equal(t, 3, attempts, "could not retry failed request")
+	require.Nil(t, writer.Flush(), "could not reset error after flush")
+	require.Nil(t, writer.Close(), "could not close elasticsearch writer")
+	require.NotNil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not fail write after close")
+}
+
+func TestElasticsearchWriterFlushInterval(t *testing.T) {
+	var mutex sync.Mutex
+	var documents int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		mutex.Lock()
+		for scanner.Scan() {
+			documents++
+		}
+		mutex.Unlock()
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	writer, err := NewElasticsearchWriter(ElasticsearchOptions{URL: server.URL, FlushInterval: 10 * time.Millisecond})
+	require.Nil(t, err, "could not create elasticsearch writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return documents == 2
+	}, 5*time.Second, 10*time.Millisecond, "could not send documents at flush interval")
+}
+
+func TestParseAuthHeader(t *testing.T) {
+	name, value := parseAuthHeader("X-Api-Key: secret")
+	require.Equal(t, "X-Api-Key", name, "could not parse header name")
+	require.Equal(t, "secret", value, "could not parse header value")
+
+	name, value = parseAuthHeader("Basic dXNlcjpwYXNz")
+	require.Equal(t, "Authorization", name, "could not default header name")
+	require.Equal(t, "Basic dXNlcjpwYXNz", value, "could not parse header value")
+}
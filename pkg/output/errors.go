@@ -0,0 +1,27 @@
+package output
+
+import "github.com/pkg/errors"
+
+// FatalError is a write error after which the results can't be written
+// anymore, like a result the kafka brokers did not acknowledge when the
+// acknowledgements are required. The crawls are stopped on fatal errors.
+type FatalError struct {
+	Err error
+}
+
+// Error returns the message of the error
+func (e *FatalError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// IsFatalError returns true if the error, or one of the combined errors,
+// is a fatal write error
+func IsFatalError(err error) bool {
+	var fatal *FatalError
+	return errors.As(err, &fatal)
+}
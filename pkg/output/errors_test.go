@@ -0,0 +1,19 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+)
+
+func TestIsFatalError(t *testing.T) {
+	fatal := &FatalError{Err: errors.New("could not produce result")}
+	require.True(t, IsFatalError(fatal), "could not get fatal error")
+	require.True(t, IsFatalError(errors.Wrap(fatal, "could not write")), "could not get wrapped fatal error")
+	require.True(t, IsFatalError(multierr.Combine(errors.New("could not store response"), fatal)), "could not get combined fatal error")
+	require.False(t, IsFatalError(errors.New("could not store response")), "got fatal error for write error")
+	require.False(t, IsFatalError(nil), "got fatal error for nil error")
+	require.Equal(t, "could not produce result", fatal.Error(), "could not get fatal error message")
+}
@@ -0,0 +1,84 @@
+package output
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/pkg/errors"
+)
+
+// extractSelector is a compiled selector of a value extracted from the
+// HTML responses
+type extractSelector struct {
+	name    string
+	matcher cascadia.Selector
+	// attribute is the attribute extracted from the first matched element,
+	// the text of the element is extracted if it is empty.
+	attribute string
+}
+
+// compileExtractSelectors compiles the name to CSS selector pairs, a
+// selector can be followed by @attribute to extract an attribute of the
+// element instead of its text, like meta[name=description]@content.
+func compileExtractSelectors(selectors map[string]string) ([]extractSelector, error) {
+	names := make([]string, 0, len(selectors))
+	for name := range selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	compiled := make([]extractSelector, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			return nil, errors.New("empty extract selector name specified")
+		}
+		selector := selectors[name]
+		var attribute string
+		if index := strings.LastIndex(selector, "@"); index != -1 {
+			selector, attribute = selector[:index], strings.TrimSpace(selector[index+1:])
+		}
+		matcher, err := cascadia.Compile(strings.TrimSpace(selector))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid extract selector %s specified for %s", selectors[name], name)
+		}
+		compiled = append(compiled, extractSelector{name: name, matcher: matcher, attribute: attribute})
+	}
+	return compiled, nil
+}
+
+// extractValues returns the values of the first elements matched by the
+// selectors in the HTML body, skipping the selectors without a value.
+func extractValues(selectors []extractSelector, body []byte) map[string]string {
+	if len(body) == 0 {
+		return nil
+	}
+	document, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil
+	}
+
+	var extracted map[string]string
+	for _, selector := range selectors {
+		selection := document.FindMatcher(selector.matcher).First()
+		if selection.Length() == 0 {
+			continue
+		}
+		var value string
+		if selector.attribute != "" {
+			value, _ = selection.Attr(selector.attribute)
+		} else {
+			value = selection.Text()
+		}
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if extracted == nil {
+			extracted = make(map[string]string)
+		}
+		extracted[selector.name] = value
+	}
+	return extracted
+}
@@ -0,0 +1,65 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractValues(t *testing.T) {
+	selectors, err := compileExtractSelectors(map[string]string{
+		"description": "meta[name=description]@content",
+		"state":       "script#state",
+		"heading":     "h1, h2",
+		"missing":     ".missing",
+	})
+	require.Nil(t, err, "could not compile extract selectors")
+
+	body := []byte(`<html><head><meta name="description" content="A page"><script id="state">{"user": "a"}</script></head>
+<body><h2> Second </h2><h1>First</h1></body></html>`)
+	extracted := extractValues(selectors, body)
+	require.Equal(t, map[string]string{
+		"description": "A page",
+		"state":       `{"user": "a"}`,
+		"heading":     "Second",
+	}, extracted, "could not extract values")
+
+	require.Nil(t, extractValues(selectors, []byte("<html></html>")), "got values without matches")
+}
+
+func TestExtractSelectorsInvalid(t *testing.T) {
+	_, err := NewWithOptions(&Options{ExtractSelectors: map[string]string{"a": "a["}})
+	require.Error(t, err, "could not get invalid selector error")
+}
+
+func TestExtractSelectorsWriter(t *testing.T) {
+	var results []*Result
+	writer, err := NewWithOptions(&Options{
+		ExtractSelectors: map[string]string{"canonical": "link[rel=canonical]@href"},
+		Fields:           "extracted.canonical",
+		OnResult: func(result *Result) {
+			results = append(results, result)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	newResponse := func(contentType, body string) *http.Response {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+		}
+	}
+	page := `<link rel="canonical" href="https://example.com/page">`
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/page?a=1"}, newResponse("text/html", page)), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/page.txt"}, newResponse("text/plain", page)), "could not write result")
+
+	require.Len(t, results, 2, "could not write results")
+	require.Equal(t, map[string]string{"canonical": "https://example.com/page"}, results[0].Extracted, "could not extract value from html response")
+	require.Nil(t, results[1].Extracted, "got extracted values for non-html response")
+	require.Equal(t, "https://example.com/page", formatField(results[0], "extracted.canonical"), "could not project extracted value")
+}
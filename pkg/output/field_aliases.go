@@ -0,0 +1,95 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// validateFieldAliases validates that the aliased keys are json keys of
+// the results and that the renamed keys don't collide with each other or
+// with the keys which are not renamed.
+func validateFieldAliases(aliases map[string]string) error {
+	keys := make([]string, 0, len(aliases))
+	for key := range aliases {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	renamed := make(map[string]string)
+	for _, key := range keys {
+		alias := aliases[key]
+		if _, ok := resultSchema.Properties[key]; !ok {
+			return errors.Errorf("invalid field alias for %s, it is not a result field", key)
+		}
+		if alias == "" {
+			return errors.Errorf("empty field alias for %s", key)
+		}
+		if other, ok := renamed[alias]; ok {
+			return errors.Errorf("field alias %s of %s collides with %s", alias, key, other)
+		}
+		if _, ok := resultSchema.Properties[alias]; ok && alias != key {
+			if _, ok := aliases[alias]; !ok {
+				return errors.Errorf("field alias %s of %s collides with the %s field", alias, key, alias)
+			}
+		}
+		renamed[alias] = key
+	}
+	return nil
+}
+
+// renameJSONKeys renames the keys of the json object by the aliases,
+// keeping the order of the keys and the values as they are.
+func renameJSONKeys(data []byte, aliases map[string]string) ([]byte, error) {
+	iterator := jsoniter.ConfigDefault.BorrowIterator(data)
+	defer jsoniter.ConfigDefault.ReturnIterator(iterator)
+
+	buffer := bytes.NewBuffer(make([]byte, 0, len(data)))
+	buffer.WriteByte('{')
+	iterator.ReadObjectCB(func(iterator *jsoniter.Iterator, key string) bool {
+		if buffer.Len() > 1 {
+			buffer.WriteByte(',')
+		}
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		buffer.WriteString(jsonString(key))
+		buffer.WriteByte(':')
+		buffer.Write(iterator.SkipAndReturnBytes())
+		return iterator.Error == nil
+	})
+	if iterator.Error != nil {
+		return nil, errors.Wrap(iterator.Error, "could not rename json keys")
+	}
+	buffer.WriteByte('}')
+	return buffer.Bytes(), nil
+}
+
+// indentJSON returns the compact json indented for the pretty json output
+func indentJSON(data []byte) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	if err := json.Indent(buffer, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// withAliases returns a copy of the object schema with the properties
+// renamed by the aliases
+func (s *jsonSchema) withAliases(aliases map[string]string) *jsonSchema {
+	if len(aliases) == 0 {
+		return s
+	}
+	schema := *s
+	schema.Properties = make(map[string]*jsonSchema, len(s.Properties))
+	for name, property := range s.Properties {
+		if alias, ok := aliases[name]; ok {
+			name = alias
+		}
+		schema.Properties[name] = property
+	}
+	return &schema
+}
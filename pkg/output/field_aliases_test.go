@@ -0,0 +1,40 @@
+package output
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldAliases(t *testing.T) {
+	aliases := map[string]string{"endpoint": "loc", "status_code": "status"}
+	standardWriter := &StandardWriter{fieldAliases: aliases, prettyJSON: true, outputSchema: resultSchema.withAliases(aliases)}
+
+	result := &Result{URL: "https://example.com/", Tag: "a", StatusCode: 200, ResponseHeaders: map[string]string{"endpoint": "kept"}}
+	data, err := standardWriter.formatJSONL(result)
+	require.Nil(t, err, "could not format jsonl")
+	require.Equal(t, `{"loc":"https://example.com/","tag":"a","status":200,"response_headers":{"endpoint":"kept"}}`, string(data), "could not rename jsonl keys")
+	require.Nil(t, standardWriter.outputSchema.validateJSON(data), "could not validate renamed keys")
+	require.NotNil(t, validateResultJSON(data), "could not reject renamed keys with the result schema")
+
+	data, err = standardWriter.formatJSON(result)
+	require.Nil(t, err, "could not format json")
+	require.True(t, strings.HasPrefix(string(data), "{\n  \"loc\": \"https://example.com/\",\n  \"tag\": \"a\","), "could not rename indented json keys")
+
+	data, err = standardWriter.formatYAML(result)
+	require.Nil(t, err, "could not format yaml")
+	require.Contains(t, string(data), "loc: https://example.com/", "could not rename yaml keys")
+}
+
+func TestValidateFieldAliases(t *testing.T) {
+	require.Nil(t, validateFieldAliases(nil), "could not validate empty aliases")
+	require.Nil(t, validateFieldAliases(map[string]string{"endpoint": "source", "source": "endpoint"}), "could not validate swapped aliases")
+	require.NotNil(t, validateFieldAliases(map[string]string{"url": "loc"}), "could not reject unknown field")
+	require.NotNil(t, validateFieldAliases(map[string]string{"endpoint": "tag"}), "could not reject collision with field")
+	require.NotNil(t, validateFieldAliases(map[string]string{"endpoint": "loc", "source": "loc"}), "could not reject colliding aliases")
+	require.NotNil(t, validateFieldAliases(map[string]string{"endpoint": ""}), "could not reject empty alias")
+
+	_, err := NewWithOptions(&Options{JSONL: true, FieldAliases: map[string]string{"endpoint": "tag"}})
+	require.NotNil(t, err, "could not reject invalid aliases")
+}
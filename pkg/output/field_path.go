@@ -0,0 +1,162 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// isFieldPath returns true if the field is a dotted path to a nested
+// value of the result like headers.Content-Type or redirects.0
+func isFieldPath(field string) bool {
+	return strings.Contains(field, ".")
+}
+
+// validateFieldPath validates a dotted field path against the structure
+// of the result.
+//
+// Map keys can't be known beforehand, so any key is accepted for maps
+// while slice indexes must be numbers and struct fields must exist.
+func validateFieldPath(fieldPath string) error {
+	return validateTypePath(reflect.TypeOf(Result{}), fieldPath)
+}
+
+// validateTypePath validates a dotted field path against a type
+func validateTypePath(fieldType reflect.Type, fieldPath string) error {
+	parts := strings.Split(fieldPath, ".")
+	for i, part := range parts {
+		if part == "" {
+			return errors.Errorf("empty segment in field path %s", fieldPath)
+		}
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			field, ok := getStructFieldByJSONName(fieldType, part)
+			if !ok {
+				return errors.Errorf("unknown field %s in field path %s", part, fieldPath)
+			}
+			fieldType = field.Type
+		case reflect.Map:
+			if fieldType.Key().Kind() != reflect.String {
+				return errors.Errorf("unsupported map key for %s in field path %s", part, fieldPath)
+			}
+			fieldType = fieldType.Elem()
+		case reflect.Slice, reflect.Array:
+			if _, err := strconv.Atoi(part); err != nil {
+				return errors.Errorf("invalid index %s in field path %s", part, fieldPath)
+			}
+			fieldType = fieldType.Elem()
+		default:
+			return errors.Errorf("field %s is not nested in field path %s", strings.Join(parts[:i], "."), fieldPath)
+		}
+	}
+	return nil
+}
+
+// resolveFieldPath returns the string value of a dotted field path for
+// the result, or an empty string if the value doesn't exist.
+func resolveFieldPath(output *Result, fieldPath string) string {
+	return resolveValuePath(reflect.ValueOf(*output), fieldPath)
+}
+
+// resolveValuePath returns the string value of a dotted field path for a value
+func resolveValuePath(value reflect.Value, fieldPath string) string {
+	for _, part := range strings.Split(fieldPath, ".") {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		switch value.Kind() {
+		case reflect.Struct:
+			field, ok := getStructFieldByJSONName(value.Type(), part)
+			if !ok {
+				return ""
+			}
+			value = value.FieldByIndex(field.Index)
+		case reflect.Map:
+			value = getMapValue(value, part)
+		case reflect.Slice, reflect.Array:
+			index, err := strconv.Atoi(part)
+			if err != nil || index < 0 || index >= value.Len() {
+				return ""
+			}
+			value = value.Index(index)
+		default:
+			return ""
+		}
+		if !value.IsValid() {
+			return ""
+		}
+	}
+	return formatFieldPathValue(value)
+}
+
+// getStructFieldByJSONName returns the struct field with the json name
+func getStructFieldByJSONName(structType reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.PkgPath == "" && getJSONFieldName(field) == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// getMapValue returns the value for a key falling back to a case-insensitive
+// match, so header names can be used in any case.
+func getMapValue(value reflect.Value, key string) reflect.Value {
+	mapKey := reflect.ValueOf(key).Convert(value.Type().Key())
+	if item := value.MapIndex(mapKey); item.IsValid() {
+		return item
+	}
+	iter := value.MapRange()
+	for iter.Next() {
+		if strings.EqualFold(iter.Key().String(), key) {
+			return iter.Value()
+		}
+	}
+	return reflect.Value{}
+}
+
+// formatFieldPathValue returns the string representation of a nested value
+func formatFieldPathValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.String:
+		return value.String()
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String {
+			values := make([]string, 0, value.Len())
+			for i := 0; i < value.Len(); i++ {
+				values = append(values, value.Index(i).String())
+			}
+			return strings.Join(values, "\n")
+		}
+	case reflect.Map, reflect.Struct:
+	default:
+		if value.IsZero() {
+			return ""
+		}
+		return fmt.Sprint(value.Interface())
+	}
+	data, _ := jsoniter.Marshal(value.Interface())
+	return string(data)
+}
+
+// getFieldPaths returns the dotted field paths from field names
+func getFieldPaths(fields string) []string {
+	var paths []string
+	for _, field := range strings.Split(fields, ",") {
+		if isFieldPath(field) {
+			paths = append(paths, field)
+		}
+	}
+	return paths
+}
@@ -1,11 +1,16 @@
 package output
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net/url"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"golang.org/x/net/publicsuffix"
@@ -26,6 +31,80 @@ var FieldNames = []string{
 	"kv",
 	"dir",
 	"udir",
+	"status_code",
+	"content_length",
+	"content_type",
+	"latency",
+	"title",
+	"body_hash",
+	"depth",
+	"parent",
+	"method",
+	"error",
+	"technologies",
+	"source_snippet",
+	"params",
+	"proto",
+	"duplicate_count",
+	"seq",
+	"raw_url",
+}
+
+// Field is a field of the results for the field projection
+type Field int
+
+// Fields of the results for the field projection
+const (
+	FieldURL Field = iota
+	FieldPath
+	FieldFQDN
+	FieldRDN
+	FieldRURL
+	FieldQURL
+	FieldQPath
+	FieldFile
+	FieldKey
+	FieldValue
+	FieldKV
+	FieldDir
+	FieldUDir
+	FieldStatusCode
+	FieldContentLength
+	FieldContentType
+	FieldLatency
+	FieldTitle
+	FieldBodyHash
+	FieldDepth
+	FieldParent
+	FieldMethod
+	FieldError
+	FieldTechnologies
+	FieldSourceSnippet
+	FieldParams
+	FieldProto
+	FieldDuplicateCount
+	FieldSeq
+	FieldRawURL
+)
+
+// String returns the name of the field as used in the field names
+func (f Field) String() string {
+	if f < 0 || int(f) >= len(FieldNames) {
+		return "Field(" + strconv.Itoa(int(f)) + ")"
+	}
+	return FieldNames[f]
+}
+
+// joinFields returns the comma separated names of the fields
+func joinFields(fields []Field) (string, error) {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field < 0 || int(field) >= len(FieldNames) {
+			return "", errors.Errorf("invalid field %d specified", int(field))
+		}
+		names = append(names, field.String())
+	}
+	return strings.Join(names, ","), nil
 }
 
 // validateFieldNames validates provided field names
@@ -39,16 +118,45 @@ func validateFieldNames(names string) error {
 		uniqueFields[field] = struct{}{}
 	}
 	for _, part := range parts {
+		if isFieldPath(part) {
+			if err := validateFieldPath(part); err != nil {
+				return errors.Wrapf(err, "invalid field %s specified", part)
+			}
+			continue
+		}
 		if _, ok := uniqueFields[part]; !ok {
-			return errors.Errorf("invalid field %s specified: %s", part, names)
+			return errors.Errorf("invalid field %q specified in %q, valid fields are %s and dotted paths of the result fields %s", part, names, strings.Join(FieldNames, ","), strings.Join(csvColumns, ","))
 		}
 	}
 	return nil
 }
 
+// createStoreFieldsDir creates the subdirectory of the run in the store
+// fields directory and returns its path.
+//
+// A generated run id is a UTC timestamp with a random suffix, so that the
+// runs starting in the same second get different directories.
+func createStoreFieldsDir(directory, runID string) (string, error) {
+	if runID == "" {
+		suffix := make([]byte, 4)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		runID = time.Now().UTC().Format("20060102T150405Z") + "_" + hex.EncodeToString(suffix)
+	}
+	if runID != filepath.Base(runID) || runID == "." || runID == ".." {
+		return "", errors.Errorf("invalid store fields run id %s", runID)
+	}
+	directory = filepath.Join(directory, runID)
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return "", err
+	}
+	return directory, nil
+}
+
 // storeFields stores fields for a result into individual files
 // based on name.
-func storeFields(output *Result, storeFields []string) {
+func storeFields(output *Result, storeFields []string, directory string) {
 	parsed, err := url.Parse(output.URL)
 	if err != nil {
 		return
@@ -59,13 +167,13 @@ func storeFields(output *Result, storeFields []string) {
 	rootURL := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
 	for _, field := range storeFields {
 		if result := getValueForField(output, parsed, hostname, etld, rootURL, field); result != "" {
-			appendToFileField(parsed, field, result)
+			appendToFileField(directory, parsed, field, result)
 		}
 	}
 }
 
-func appendToFileField(parsed *url.URL, field, data string) {
-	file, err := os.OpenFile(path.Join(storeFieldsDirectory, fmt.Sprintf("%s_%s_%s.txt", parsed.Scheme, parsed.Hostname(), field)), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+func appendToFileField(directory string, parsed *url.URL, field, data string) {
+	file, err := os.OpenFile(filepath.Join(directory, fmt.Sprintf("%s_%s_%s.txt", parsed.Scheme, parsed.Hostname(), field)), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 	if err != nil {
 		return
 	}
@@ -98,13 +206,36 @@ func formatField(output *Result, fields string) string {
 	hostname := parsed.Hostname()
 	etld, _ := publicsuffix.EffectiveTLDPlusOne(hostname)
 	rootURL := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
-	values := []string{
+	// dotted field paths come first so that they take precedence over
+	// the flat field names contained in them.
+	var values []string
+	for _, fieldPath := range getFieldPaths(fields) {
+		values = append(values, fieldPath, resolveFieldPath(output, fieldPath))
+	}
+	values = append(values,
+		"status_code", formatStatusCode(output.StatusCode),
+		"content_length", formatContentLength(output.ContentLength),
+		"content_type", output.ContentType,
+		"latency", formatLatency(output.Latency),
+		"title", output.Title,
+		"body_hash", output.BodyHash,
+		"depth", strconv.Itoa(output.Depth),
+		"parent", output.Parent,
+		"method", getMethod(output),
+		"error", output.Error,
+		"technologies", strings.Join(output.Technologies, ","),
+		"source_snippet", output.SourceSnippet,
+		"params", strings.Join(output.Params, ","),
+		"proto", output.Proto,
+		"duplicate_count", strconv.Itoa(output.DuplicateCount),
+		"seq", strconv.FormatInt(output.Seq, 10),
+		"raw_url", output.RawURL,
 		"url", output.URL,
 		"rurl", rootURL,
 		"rdn", etld,
 		"path", parsed.Path,
 		"fqdn", hostname,
-	}
+	)
 	if len(queryKeys) > 0 {
 		values = append(values, "qurl", output.URL)
 		values = append(values, "qpath", fmt.Sprintf("%s?%s", parsed.Path, parsed.Query().Encode()))
@@ -138,7 +269,44 @@ func formatField(output *Result, fields string) string {
 
 // getValueForField returns value for a field
 func getValueForField(output *Result, parsed *url.URL, hostname, rdn, rurl, field string) string {
+	if isFieldPath(field) {
+		return resolveFieldPath(output, field)
+	}
 	switch field {
+	case "status_code":
+		return formatStatusCode(output.StatusCode)
+	case "content_length":
+		return formatContentLength(output.ContentLength)
+	case "content_type":
+		return output.ContentType
+	case "latency":
+		return formatLatency(output.Latency)
+	case "title":
+		return output.Title
+	case "body_hash":
+		return output.BodyHash
+	case "depth":
+		return strconv.Itoa(output.Depth)
+	case "parent":
+		return output.Parent
+	case "method":
+		return getMethod(output)
+	case "error":
+		return output.Error
+	case "technologies":
+		return strings.Join(output.Technologies, ",")
+	case "source_snippet":
+		return output.SourceSnippet
+	case "params":
+		return strings.Join(output.Params, ",")
+	case "proto":
+		return output.Proto
+	case "duplicate_count":
+		return strconv.Itoa(output.DuplicateCount)
+	case "seq":
+		return strconv.FormatInt(output.Seq, 10)
+	case "raw_url":
+		return output.RawURL
 	case "url":
 		return output.URL
 	case "path":
@@ -193,3 +361,36 @@ func getValueForField(output *Result, parsed *url.URL, hostname, rdn, rurl, fiel
 	}
 	return ""
 }
+
+// formatStatusCode returns the string value of a status code
+func formatStatusCode(statusCode int) string {
+	if statusCode == 0 {
+		return ""
+	}
+	return strconv.Itoa(statusCode)
+}
+
+// formatContentLength returns the string value of a content length
+func formatContentLength(contentLength int64) string {
+	if contentLength == 0 {
+		return ""
+	}
+	return strconv.FormatInt(contentLength, 10)
+}
+
+// formatLatency returns the string value of a latency in milliseconds
+func formatLatency(latency Duration) string {
+	if latency == 0 {
+		return ""
+	}
+	return latency.String()
+}
+
+// getMethod returns the method of the result, results without a method
+// are GET requests.
+func getMethod(output *Result) string {
+	if output.Method == "" {
+		return "GET"
+	}
+	return output.Method
+}
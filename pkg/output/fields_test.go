@@ -1,8 +1,14 @@
 package output
 
 import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -16,6 +22,12 @@ func TestValidateFieldNames(t *testing.T) {
 
 	err = validateFieldNames("invalid")
 	require.Error(t, err, "got no error with invalid field")
+
+	err = validateFieldNames("url,urls")
+	require.Error(t, err, "got no error with mistyped field")
+	require.Contains(t, err.Error(), `invalid field "urls"`, "could not get invalid field in error")
+	require.Contains(t, err.Error(), strings.Join(FieldNames, ","), "could not get valid fields in error")
+	require.Contains(t, err.Error(), "response_headers", "could not get result fields in error")
 }
 
 func TestFormatField(t *testing.T) {
@@ -43,3 +55,202 @@ func TestFormatField(t *testing.T) {
 		require.ElementsMatch(t, test.result, strings.Split(result, "\n"), "could not equal value")
 	}
 }
+
+func TestFormatFieldValues(t *testing.T) {
+	result := &Result{
+		URL:            "https://example.com/dir/file.php?a=1",
+		Method:         "POST",
+		StatusCode:     200,
+		ContentLength:  42,
+		ContentType:    "text/html",
+		Latency:        Duration(1500 * time.Millisecond),
+		Title:          "Home",
+		BodyHash:       "0123abcd",
+		Depth:          1,
+		Parent:         "https://example.com/",
+		Error:          "context deadline exceeded",
+		Technologies:   []string{"nginx", "PHP"},
+		SourceSnippet:  `<a href="/a">`,
+		Params:         []string{"a", "b"},
+		Proto:          "HTTP/1.1",
+		DuplicateCount: 2,
+		Seq:            3,
+		RawURL:         "/dir/file.php?a=1",
+	}
+	tests := map[string]string{
+		"url":             "https://example.com/dir/file.php?a=1",
+		"path":            "/dir/file.php",
+		"fqdn":            "example.com",
+		"rdn":             "example.com",
+		"rurl":            "https://example.com",
+		"qurl":            "https://example.com/dir/file.php?a=1",
+		"qpath":           "/dir/file.php?a=1",
+		"file":            "file.php",
+		"key":             "a",
+		"value":           "1",
+		"kv":              "a=1",
+		"dir":             "/dir/",
+		"udir":            "https://example.com/dir/",
+		"status_code":     "200",
+		"content_length":  "42",
+		"content_type":    "text/html",
+		"latency":         formatLatency(result.Latency),
+		"title":           "Home",
+		"body_hash":       "0123abcd",
+		"depth":           "1",
+		"parent":          "https://example.com/",
+		"method":          "POST",
+		"error":           "context deadline exceeded",
+		"technologies":    "nginx,PHP",
+		"source_snippet":  `<a href="/a">`,
+		"params":          "a,b",
+		"proto":           "HTTP/1.1",
+		"duplicate_count": "2",
+		"seq":             "3",
+		"raw_url":         "/dir/file.php?a=1",
+	}
+	parsed, err := url.Parse(result.URL)
+	require.Nil(t, err, "could not parse url")
+
+	for _, field := range FieldNames {
+		want, ok := tests[field]
+		require.True(t, ok, "no test value for field %s", field)
+		require.NotEmpty(t, want, "empty test value for field %s", field)
+		require.Equal(t, want, formatField(result, field), "could not format field %s", field)
+		require.Equal(t, "https://example.com/dir/file.php?a=1,"+want, formatField(result, "url,"+field), "could not format field %s with url", field)
+		require.Equal(t, want, getValueForField(result, parsed, "example.com", "example.com", "https://example.com", field), "could not get value of field %s", field)
+	}
+	require.Len(t, tests, len(FieldNames), "got test values for unknown fields")
+}
+
+func TestFieldPath(t *testing.T) {
+	type redirect struct {
+		URL        string `json:"url"`
+		StatusCode int    `json:"status_code"`
+	}
+	type nested struct {
+		Headers   map[string]string `json:"headers"`
+		Redirects []redirect        `json:"redirects"`
+		Values    []string          `json:"values"`
+	}
+	nestedType := reflect.TypeOf(nested{})
+	for _, fieldPath := range []string{"headers.Content-Type", "redirects.0", "redirects.1.status_code", "values.0"} {
+		require.Nil(t, validateTypePath(nestedType, fieldPath), "could not validate field path %s", fieldPath)
+	}
+	for _, fieldPath := range []string{"unknown.a", "redirects.first", "redirects.0.unknown", "values.0.a", "headers."} {
+		require.Error(t, validateTypePath(nestedType, fieldPath), "got no error for field path %s", fieldPath)
+	}
+
+	value := reflect.ValueOf(nested{
+		Headers:   map[string]string{"Content-Type": "text/html"},
+		Redirects: []redirect{{URL: "https://example.com/", StatusCode: 301}},
+	})
+	require.Equal(t, "text/html", resolveValuePath(value, "headers.Content-Type"), "could not resolve map value")
+	require.Equal(t, "text/html", resolveValuePath(value, "headers.content-type"), "could not resolve map value case-insensitively")
+	require.Equal(t, "301", resolveValuePath(value, "redirects.0.status_code"), "could not resolve slice value")
+	require.Equal(t, `{"url":"https://example.com/","status_code":301}`, resolveValuePath(value, "redirects.0"), "could not resolve struct value")
+	require.Equal(t, "", resolveValuePath(value, "redirects.1"), "could resolve out of range index")
+	require.Equal(t, "", resolveValuePath(value, "headers.Server"), "could resolve missing key")
+
+	require.Error(t, validateFieldNames("url.path"), "got no error for path on flat field")
+}
+
+func TestFormatFieldCrawlTree(t *testing.T) {
+	result := &Result{URL: "https://example.com/a", Parent: "https://example.com/", Depth: 1}
+	require.Equal(t, "https://example.com/ -> https://example.com/a (1)", formatField(result, "parent -> url (depth)"), "could not format crawl tree fields")
+	require.Equal(t, "", formatField(&Result{URL: "https://example.com/"}, "parent"), "could not get empty seed parent")
+}
+
+func TestFieldEnum(t *testing.T) {
+	require.Len(t, FieldNames, int(FieldRawURL)+1, "could not map all field names")
+	require.Equal(t, "url", FieldURL.String(), "could not get field name")
+	require.Equal(t, "status_code", FieldStatusCode.String(), "could not get field name")
+	require.Equal(t, "Field(-1)", Field(-1).String(), "could not get invalid field name")
+
+	for i := range FieldNames {
+		require.Nil(t, validateFieldNames(Field(i).String()), "could not validate field %d", i)
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	writer, err := NewWithOptions(&Options{SelectFields: []Field{FieldMethod, FieldURL, FieldStatusCode}})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	data, err := writer.(*StandardWriter).formatScreen(&Result{URL: "https://example.com/", StatusCode: 200})
+	require.Nil(t, err, "could not format screen output")
+	require.Equal(t, "GET,https://example.com/,200", string(data), "could not format selected fields")
+
+	_, err = NewWithOptions(&Options{SelectFields: []Field{Field(100)}})
+	require.Error(t, err, "got no error for invalid select field")
+	_, err = NewWithOptions(&Options{Fields: "url", SelectFields: []Field{FieldURL}})
+	require.Error(t, err, "got no error for fields with select fields")
+}
+
+func TestStoreFieldsDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "fields")
+
+	writer, err := NewWithOptions(&Options{StoreFields: "path", StoreFieldsDir: dir, StoreFieldsRunID: "run", Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a/b"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(filepath.Join(dir, "run", "https_example.com_path.txt"))
+	require.Nil(t, err, "could not read store fields file")
+	require.Equal(t, "/a/b\n", string(data), "could not store field")
+
+	working, err := os.Getwd()
+	require.Nil(t, err, "could not get working directory")
+	empty := t.TempDir()
+	require.Nil(t, os.Chdir(empty), "could not change working directory")
+	defer func() { _ = os.Chdir(working) }()
+
+	writer, err = NewWithOptions(&Options{StoreFields: "path", Silent: true})
+	require.Nil(t, err, "could not create writer")
+	entries, err := os.ReadDir(empty)
+	require.Nil(t, err, "could not read working directory")
+	require.Empty(t, entries, "could not skip store fields directory creation")
+	require.Nil(t, writer.Close(), "could not close writer")
+}
+
+func TestStoreFieldsConcurrentRuns(t *testing.T) {
+	dir := t.TempDir()
+
+	var writers []*StandardWriter
+	for i := 0; i < 2; i++ {
+		writer, err := NewWithOptions(&Options{StoreFields: "path", StoreFieldsDir: dir, Silent: true})
+		require.Nil(t, err, "could not create writer")
+		writers = append(writers, writer.(*StandardWriter))
+	}
+	require.NotEqual(t, writers[0].storeFieldsDir, writers[1].storeFieldsDir, "could not namespace store fields of concurrent runs")
+
+	done := make(chan error)
+	for i, writer := range writers {
+		go func(i int, writer *StandardWriter) {
+			var err error
+			for j := 0; j < 50 && err == nil; j++ {
+				err = writer.Write(&Result{URL: "https://example.com/run" + strconv.Itoa(i)}, nil)
+			}
+			done <- err
+		}(i, writer)
+	}
+	for range writers {
+		require.Nil(t, <-done, "could not write result")
+	}
+
+	for i, writer := range writers {
+		require.Nil(t, writer.Close(), "could not close writer")
+		require.Equal(t, dir, filepath.Dir(writer.storeFieldsDir), "could not place run directory in store fields directory")
+
+		data, err := os.ReadFile(filepath.Join(writer.storeFieldsDir, "https_example.com_path.txt"))
+		require.Nil(t, err, "could not read store fields file")
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 50, "could not keep store fields of the run")
+		for _, line := range lines {
+			require.Equal(t, "/run"+strconv.Itoa(i), line, "got store fields of another run")
+		}
+	}
+
+	_, err := NewWithOptions(&Options{StoreFields: "path", StoreFieldsDir: dir, StoreFieldsRunID: "../escape"})
+	require.NotNil(t, err, "could not reject run id with separators")
+}
@@ -2,38 +2,323 @@ package output
 
 import (
 	"bufio"
+	"compress/gzip"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 // fileWriter is a concurrent file based output writer.
+//
+// It writes to a caller provided stream instead of a file if it is
+// created with newStreamOutputWriter, in which case the file is nil.
 type fileWriter struct {
-	file   *os.File
-	writer *bufio.Writer
+	file    *os.File
+	gzip    *gzip.Writer
+	writer  *bufio.Writer
+	mutex   *sync.Mutex
+	options fileWriterOptions
+
+	// path is the path of the first file, the rotated files are
+	// named path.1, path.2, etc. (or output.1.gz for a compressed
+	// output.gz) and are all kept in paths.
+	path     string
+	paths    []string
+	written  int64
+	openedAt time.Time
+	// writes is the number of writes since the last sync
+	writes int
+
+	stopFlush chan struct{}
+	flushDone chan struct{}
+}
+
+// fileWriterOptions contains the configuration options for a file writer
+type fileWriterOptions struct {
+	// compress specifies to gzip compress the file contents
+	compress bool
+	// bufferSize is the size of the in-memory buffer, the default
+	// buffer size is used if it is not positive.
+	bufferSize int
+	// flushInterval is the interval to periodically flush the buffer at
+	flushInterval time.Duration
+	// rotateSize is the size in bytes of the uncompressed data after
+	// which to rotate the file, disabled if it is not positive.
+	rotateSize int64
+	// rotateInterval is the interval after which to rotate the file,
+	// disabled if it is not positive. The rotation happens on the
+	// first write once the interval has passed.
+	rotateInterval time.Duration
+	// syncEvery is the number of writes after which to sync the file to
+	// disk, disabled if it is not positive.
+	syncEvery int
+	// syncInterval is the interval to periodically sync the file to
+	// disk at, disabled if it is not positive.
+	syncInterval time.Duration
 }
 
 // NewFileOutputWriter creates a new buffered writer for a file
 func newFileOutputWriter(file string) (*fileWriter, error) {
+	return newFileOutputWriterWithOptions(file, fileWriterOptions{})
+}
+
+// newCompressedFileOutputWriter creates a new buffered gzip writer for a file
+func newCompressedFileOutputWriter(file string) (*fileWriter, error) {
+	return newFileOutputWriterWithOptions(file, fileWriterOptions{compress: true})
+}
+
+// newStreamOutputWriter creates a writer for the stream. The data is
+// written through to the stream on every write, only the compressed
+// data is buffered until Flush. The stream is not closed on Close.
+func newStreamOutputWriter(stream io.Writer, options fileWriterOptions) *fileWriter {
+	writer := &fileWriter{mutex: &sync.Mutex{}, options: options, openedAt: time.Now()}
+	if options.compress {
+		writer.gzip = gzip.NewWriter(stream)
+		writer.writer = bufio.NewWriter(writer.gzip)
+	} else {
+		writer.writer = bufio.NewWriter(stream)
+	}
+	return writer
+}
+
+// newFileOutputWriterWithOptions creates a new buffered writer for a file with options
+func newFileOutputWriterWithOptions(file string, options fileWriterOptions) (*fileWriter, error) {
+	if options.bufferSize <= 0 {
+		options.bufferSize = 4096
+	}
+	writer := &fileWriter{path: file, mutex: &sync.Mutex{}, options: options}
+	if err := writer.open(file); err != nil {
+		return nil, err
+	}
+	if options.flushInterval > 0 || options.syncInterval > 0 {
+		writer.stopFlush = make(chan struct{})
+		writer.flushDone = make(chan struct{})
+		go writer.flushPeriodically(options.flushInterval, options.syncInterval)
+	}
+	return writer, nil
+}
+
+// open creates the file and the buffered writers for it
+func (w *fileWriter) open(file string) error {
 	output, err := os.Create(file)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	w.file = output
+	if w.options.compress {
+		w.gzip = gzip.NewWriter(output)
+		w.writer = bufio.NewWriterSize(w.gzip, w.options.bufferSize)
+	} else {
+		w.writer = bufio.NewWriterSize(output, w.options.bufferSize)
+	}
+	w.paths = append(w.paths, file)
+	w.written = 0
+	w.openedAt = time.Now()
+	return nil
+}
+
+// closeFile flushes the buffered data and closes the current file
+func (w *fileWriter) closeFile() error {
+	flushErr := w.writer.Flush()
+	if w.gzip != nil {
+		// closing the gzip writer writes the remaining compressed data and footer
+		if err := w.gzip.Close(); err != nil {
+			if w.file != nil {
+				w.file.Close()
+			}
+			return err
+		}
+	}
+	if w.file == nil {
+		return flushErr
+	}
+	//nolint:errcheck // we don't care whether sync failed or succeeded.
+	w.file.Sync()
+	return w.file.Close()
+}
+
+// rotate closes the current file and opens the next numbered file if
+// writing size more bytes crosses the rotation thresholds.
+//
+// It must be called with the mutex held.
+func (w *fileWriter) rotate(size int) error {
+	if w.written == 0 || w.file == nil {
+		return nil
+	}
+	sizeExceeded := w.options.rotateSize > 0 && w.written+int64(size) > w.options.rotateSize
+	intervalPassed := w.options.rotateInterval > 0 && time.Since(w.openedAt) >= w.options.rotateInterval
+	if !sizeExceeded && !intervalPassed {
+		return nil
+	}
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	return w.open(w.getRotatedPath(len(w.paths)))
+}
+
+// getRotatedPath returns the path of the nth rotated file, keeping the
+// .gz extension last for compressed files.
+func (w *fileWriter) getRotatedPath(n int) string {
+	if w.options.compress && strings.HasSuffix(w.path, ".gz") {
+		return strings.TrimSuffix(w.path, ".gz") + "." + strconv.Itoa(n) + ".gz"
+	}
+	return w.path + "." + strconv.Itoa(n)
+}
+
+// flushPeriodically flushes the buffered data and syncs the file at
+// intervals until stopped, a non-positive interval disables either.
+func (w *fileWriter) flushPeriodically(flushInterval, syncInterval time.Duration) {
+	defer close(w.flushDone)
+
+	// receiving from a nil channel blocks forever, disabling the case
+	var flushes, syncs <-chan time.Time
+	if flushInterval > 0 {
+		ticker := time.NewTicker(flushInterval)
+		defer ticker.Stop()
+		flushes = ticker.C
+	}
+	if syncInterval > 0 {
+		ticker := time.NewTicker(syncInterval)
+		defer ticker.Stop()
+		syncs = ticker.C
+	}
+
+	for {
+		select {
+		case <-flushes:
+			_ = w.Flush()
+		case <-syncs:
+			_ = w.Sync()
+		case <-w.stopFlush:
+			return
+		}
 	}
-	return &fileWriter{file: output, writer: bufio.NewWriter(output)}, nil
 }
 
 // WriteString writes an output to the underlying file
 func (w *fileWriter) Write(data []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.rotate(len(data) + 1); err != nil {
+		return err
+	}
 	_, err := w.writer.Write(data)
 	if err != nil {
 		return err
 	}
 	_, err = w.writer.WriteRune('\n')
-	return err
+	w.written += int64(len(data) + 1)
+	if err != nil {
+		return err
+	}
+	return w.afterWrite()
+}
+
+// WriteRaw writes the data to the underlying file as is without a newline
+func (w *fileWriter) WriteRaw(data []byte) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.rotate(len(data)); err != nil {
+		return err
+	}
+	_, err := w.writer.Write(data)
+	w.written += int64(len(data))
+	if err != nil {
+		return err
+	}
+	return w.afterWrite()
+}
+
+// afterWrite writes the data through to the stream for stream writers, or
+// syncs the file to disk for file writers if the sync threshold is reached.
+//
+// It must be called with the mutex held.
+func (w *fileWriter) afterWrite() error {
+	if w.file == nil {
+		return w.writer.Flush()
+	}
+	return w.syncAfterWrite()
+}
+
+// syncAfterWrite syncs the file to disk if the number of writes since
+// the last sync reached the sync threshold.
+//
+// It must be called with the mutex held.
+func (w *fileWriter) syncAfterWrite() error {
+	if w.options.syncEvery <= 0 {
+		return nil
+	}
+	w.writes++
+	if w.writes < w.options.syncEvery {
+		return nil
+	}
+	return w.sync()
+}
+
+// Flush flushes the buffered data to the underlying file
+func (w *fileWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		return w.gzip.Flush()
+	}
+	return nil
+}
+
+// Sync flushes the buffered data and commits the file contents to disk,
+// so the data written so far survives the process being killed.
+func (w *fileWriter) Sync() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.sync()
+}
+
+// sync flushes the buffered data and commits the file contents to disk.
+//
+// It must be called with the mutex held.
+func (w *fileWriter) sync() error {
+	w.writes = 0
+	if err := w.writer.Flush(); err != nil {
+		return err
+	}
+	if w.gzip != nil {
+		if err := w.gzip.Flush(); err != nil {
+			return err
+		}
+	}
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Sync()
 }
 
 // Close closes the underlying writer flushing everything to disk
 func (w *fileWriter) Close() error {
-	w.writer.Flush()
-	//nolint:errcheck // we don't care whether sync failed or succeeded.
-	w.file.Sync()
-	return w.file.Close()
+	if w.stopFlush != nil {
+		close(w.stopFlush)
+		<-w.flushDone
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.closeFile()
+}
+
+// getPaths returns the paths of all the written files including the
+// rotated ones in order.
+func (w *fileWriter) getPaths() []string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return append([]string(nil), w.paths...)
 }
@@ -0,0 +1,168 @@
+package output
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedFileWriter(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "response.txt.gz")
+
+	writer, err := newCompressedFileOutputWriter(fileName)
+	require.Nil(t, err, "could not create compressed writer")
+	require.Nil(t, writer.Write([]byte("first")), "could not write data")
+	require.Nil(t, writer.Write([]byte("second")), "could not write data")
+	require.Nil(t, writer.Close(), "could not close compressed writer")
+
+	file, err := os.Open(fileName)
+	require.Nil(t, err, "could not open compressed file")
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	require.Nil(t, err, "could not create gzip reader")
+	data, err := io.ReadAll(reader)
+	require.Nil(t, err, "could not read compressed data")
+	require.Equal(t, "first\nsecond\n", string(data), "could not equal decompressed data")
+}
+
+func TestFileWriterFlushInterval(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{bufferSize: 64 * 1024, flushInterval: 10 * time.Millisecond})
+	require.Nil(t, err, "could not create buffered writer")
+	require.Nil(t, writer.Write([]byte("data")), "could not write data")
+
+	require.Eventually(t, func() bool {
+		data, _ := os.ReadFile(fileName)
+		return string(data) == "data\n"
+	}, time.Second, 10*time.Millisecond, "could not flush data periodically")
+	require.Nil(t, writer.Close(), "could not close buffered writer")
+}
+
+func BenchmarkFileWriterUnbuffered(b *testing.B) {
+	writer, err := newFileOutputWriter(filepath.Join(b.TempDir(), "output.txt"))
+	require.Nil(b, err, "could not create writer")
+	defer writer.Close()
+
+	data := []byte(`{"timestamp":"2022-12-01T00:00:00Z","endpoint":"https://example.com/path?query=value","source":"https://example.com/","tag":"a","attribute":"href"}`)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = writer.Write(data)
+		_ = writer.Flush()
+	}
+}
+
+func BenchmarkFileWriterBuffered(b *testing.B) {
+	writer, err := newFileOutputWriterWithOptions(filepath.Join(b.TempDir(), "output.txt"), fileWriterOptions{bufferSize: 64 * 1024, flushInterval: time.Second})
+	require.Nil(b, err, "could not create writer")
+	defer writer.Close()
+
+	data := []byte(`{"timestamp":"2022-12-01T00:00:00Z","endpoint":"https://example.com/path?query=value","source":"https://example.com/","tag":"a","attribute":"href"}`)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = writer.Write(data)
+	}
+}
+
+func TestFileWriterRotateSize(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{rotateSize: 10})
+	require.Nil(t, err, "could not create rotating writer")
+	for _, data := range []string{"first", "second", "third", "a", "b"} {
+		require.Nil(t, writer.Write([]byte(data)), "could not write data")
+	}
+	require.Nil(t, writer.Close(), "could not close rotating writer")
+	require.Equal(t, []string{fileName, fileName + ".1", fileName + ".2"}, writer.getPaths(), "could not get rotated files")
+
+	for i, expected := range []string{"first\n", "second\n", "third\na\nb\n"} {
+		data, err := os.ReadFile(writer.getPaths()[i])
+		require.Nil(t, err, "could not read rotated file")
+		require.Equal(t, expected, string(data), "could not equal rotated file data")
+	}
+}
+
+func TestCompressedFileWriterRotate(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt.gz")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{compress: true, rotateSize: 5})
+	require.Nil(t, err, "could not create rotating writer")
+	require.Nil(t, writer.Write([]byte("first")), "could not write data")
+	require.Nil(t, writer.Write([]byte("second")), "could not write data")
+	require.Nil(t, writer.Close(), "could not close rotating writer")
+
+	rotated := filepath.Join(filepath.Dir(fileName), "output.txt.1.gz")
+	require.Equal(t, []string{fileName, rotated}, writer.getPaths(), "could not keep gz extension of rotated files")
+}
+
+func TestFileWriterRotateInterval(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{rotateInterval: 10 * time.Millisecond})
+	require.Nil(t, err, "could not create rotating writer")
+	require.Nil(t, writer.Write([]byte("first")), "could not write data")
+	require.Nil(t, writer.Write([]byte("second")), "could not write data")
+	time.Sleep(20 * time.Millisecond)
+	require.Nil(t, writer.Write([]byte("third")), "could not write data")
+	require.Nil(t, writer.Close(), "could not close rotating writer")
+
+	data, err := os.ReadFile(fileName + ".1")
+	require.Nil(t, err, "could not read rotated file")
+	require.Equal(t, "third\n", string(data), "could not equal rotated file data")
+}
+
+func TestRotateUnsupportedFormats(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output")
+
+	for _, options := range []*Options{
+		{OutputFile: fileName, CSV: true, RotateSize: 1024},
+		{OutputFile: fileName, TSV: true, RotateInterval: time.Minute},
+		{OutputFile: fileName, JSONArray: true, RotateSize: 1024},
+		{OutputFile: fileName, JSONArray: true, RotateInterval: time.Minute},
+	} {
+		_, err := NewWithOptions(options)
+		require.NotNil(t, err, "could not reject rotation of output without standalone files")
+	}
+
+	writer, err := NewWithOptions(&Options{OutputFile: fileName, JSONL: true, Silent: true, RotateSize: 1024})
+	require.Nil(t, err, "could not create rotating jsonl writer")
+	require.Nil(t, writer.Close(), "could not close rotating jsonl writer")
+}
+
+func TestFileWriterSyncEvery(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{bufferSize: 64 * 1024, syncEvery: 2})
+	require.Nil(t, err, "could not create syncing writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write([]byte("first")), "could not write data")
+	data, err := os.ReadFile(fileName)
+	require.Nil(t, err, "could not read file")
+	require.Empty(t, data, "got synced data before threshold")
+
+	require.Nil(t, writer.Write([]byte("second")), "could not write data")
+	data, err = os.ReadFile(fileName)
+	require.Nil(t, err, "could not read file")
+	require.Equal(t, "first\nsecond\n", string(data), "could not get synced data")
+}
+
+func TestFileWriterSyncInterval(t *testing.T) {
+	fileName := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := newFileOutputWriterWithOptions(fileName, fileWriterOptions{bufferSize: 64 * 1024, syncInterval: 10 * time.Millisecond})
+	require.Nil(t, err, "could not create syncing writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write([]byte("data")), "could not write data")
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(fileName)
+		return err == nil && string(data) == "data\n"
+	}, time.Second, 10*time.Millisecond, "could not get periodically synced data")
+}
@@ -0,0 +1,255 @@
+package output
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+// getFilterReason returns the reason the result is dropped by the
+// configured filters, or an empty string if it matches them.
+func (w *StandardWriter) getFilterReason(event *Result) string {
+	if w.maxOutputDepth > 0 && event.Depth > w.maxOutputDepth {
+		return "depth " + strconv.Itoa(event.Depth) + " is above the maximum output depth"
+	}
+	if !w.matchBodySize(event) {
+		return "body size " + strconv.FormatInt(event.ContentLength, 10) + " is out of the body size range"
+	}
+	if w.matchExtensions != nil || w.filterExtensions != nil {
+		extension := getURLExtension(event.URL)
+		if w.matchExtensions != nil {
+			if _, ok := w.matchExtensions[extension]; !ok {
+				return "extension " + strconv.Quote(extension) + " is not matched"
+			}
+		}
+		if w.filterExtensions != nil {
+			if _, ok := w.filterExtensions[extension]; ok {
+				return "extension " + strconv.Quote(extension) + " is filtered"
+			}
+		}
+	}
+	if w.matchContentTypes != nil {
+		if _, ok := w.matchContentTypes[event.ContentType]; !ok {
+			return "content-type " + strconv.Quote(event.ContentType) + " is not matched"
+		}
+	}
+	if w.filterContentTypes != nil {
+		if _, ok := w.filterContentTypes[event.ContentType]; ok {
+			return "content-type " + strconv.Quote(event.ContentType) + " is filtered"
+		}
+	}
+	if w.matchStatusCodes != nil {
+		if _, ok := w.matchStatusCodes[event.StatusCode]; !ok {
+			return "status code " + strconv.Itoa(event.StatusCode) + " is not matched"
+		}
+	}
+	if w.filterStatusCodes != nil {
+		if _, ok := w.filterStatusCodes[event.StatusCode]; ok {
+			return "status code " + strconv.Itoa(event.StatusCode) + " is filtered"
+		}
+	}
+	if len(w.matchRegex) > 0 && !matchAnyRegex(w.matchRegex, event.URL) {
+		return "url is not matched by the match regexes"
+	}
+	if len(w.filterRegex) > 0 && matchAnyRegex(w.filterRegex, event.URL) {
+		return "url is matched by the filter regexes"
+	}
+	if w.baseline != nil && w.baseline.contains(event.URL) {
+		return "url is in the baseline"
+	}
+	return ""
+}
+
+// matchBodySize returns true if the response body size of the result is
+// within the configured limits.
+//
+// The content length is measured from the body when it is unknown, so
+// only the results without a response have no size, which are matched.
+func (w *StandardWriter) matchBodySize(event *Result) bool {
+	if (w.minBodySize <= 0 && w.maxBodySize <= 0) || event.StatusCode == 0 {
+		return true
+	}
+	if w.minBodySize > 0 && event.ContentLength < w.minBodySize {
+		return false
+	}
+	if w.maxBodySize > 0 && event.ContentLength > w.maxBodySize {
+		return false
+	}
+	return true
+}
+
+// matchStoredResponse returns true if the response should be stored based
+// on the configured store filters.
+func (w *StandardWriter) matchStoredResponse(resp *http.Response) bool {
+	if w.storeStatusCodes != nil {
+		if _, ok := w.storeStatusCodes[resp.StatusCode]; !ok {
+			return false
+		}
+	}
+	if w.storeContentTypes != nil {
+		if _, ok := w.storeContentTypes[getMediaType(resp.Header.Get("Content-Type"))]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// newStatusCodeSet returns a lookup set of the status codes parsing
+// ranges like 2xx to all the codes in the range.
+func newStatusCodeSet(statusCodes []string) (map[int]struct{}, error) {
+	if len(statusCodes) == 0 {
+		return nil, nil
+	}
+	set := make(map[int]struct{})
+	for _, value := range statusCodes {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if len(value) == 3 && strings.HasSuffix(value, "xx") {
+			class := int(value[0] - '0')
+			if class < 1 || class > 5 {
+				return nil, errors.Errorf("invalid status code range %s", value)
+			}
+			for code := class * 100; code < (class+1)*100; code++ {
+				set[code] = struct{}{}
+			}
+			continue
+		}
+		code, err := strconv.Atoi(value)
+		if err != nil || code < 100 || code > 599 {
+			return nil, errors.Errorf("invalid status code %s", value)
+		}
+		set[code] = struct{}{}
+	}
+	return set, nil
+}
+
+// newExtensionSet returns a lookup set of normalized extensions
+func newExtensionSet(extensions []string) map[string]struct{} {
+	if len(extensions) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(extensions))
+	for _, extension := range extensions {
+		extension = strings.ToLower(strings.TrimSpace(extension))
+		if !strings.HasPrefix(extension, ".") {
+			extension = "." + extension
+		}
+		set[extension] = struct{}{}
+	}
+	return set
+}
+
+// getURLExtension returns the lowercase extension of the URL path
+// ignoring the query string and fragment.
+func getURLExtension(URL string) string {
+	if index := strings.IndexAny(URL, "?#"); index != -1 {
+		URL = URL[:index]
+	}
+	if parsed, err := url.Parse(URL); err == nil {
+		URL = parsed.Path
+	}
+	return strings.ToLower(path.Ext(URL))
+}
+
+// compileRegexes compiles the list of regex patterns
+func compileRegexes(patterns []string) ([]*regexp.Regexp, error) {
+	regexes := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid regex %s", pattern)
+		}
+		regexes = append(regexes, regex)
+	}
+	return regexes, nil
+}
+
+// matchAnyRegex returns true if any of the regexes matches the value
+func matchAnyRegex(regexes []*regexp.Regexp, value string) bool {
+	for _, regex := range regexes {
+		if regex.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFilteredResult returns true if the result is dropped by the
+// configured filters, logging the reason at the debug level if enabled.
+func (w *StandardWriter) isFilteredResult(event *Result) bool {
+	reason := w.getFilterReason(event)
+	if reason == "" {
+		return false
+	}
+	w.logFilteredResult(event, reason)
+	return true
+}
+
+// isWrittenResult returns true if the result which passed the filters
+// passes the deduplication and the sampling, logging the reason the
+// result is dropped at the debug level if enabled.
+func (w *StandardWriter) isWrittenResult(event *Result) bool {
+	var reason string
+	if !w.isUniqueResult(event) {
+		reason = "duplicate of a written result"
+	} else if !w.isSampledResult(event) {
+		reason = "not selected by sampling"
+	}
+	if reason == "" {
+		return true
+	}
+	w.logFilteredResult(event, reason)
+	return false
+}
+
+// logFilteredResult logs the reason the result is dropped at the debug
+// level if enabled
+func (w *StandardWriter) logFilteredResult(event *Result, reason string) {
+	if w.logFiltered {
+		gologger.Debug().Msgf("Filtered %s: %s\n", event.URL, reason)
+	}
+}
+
+// isUniqueResult returns true if the result has not been written before
+// when deduplication is enabled.
+func (w *StandardWriter) isUniqueResult(event *Result) bool {
+	if w.deduper == nil {
+		return true
+	}
+	return w.deduper.isUnique(event)
+}
+
+// isSampledResult returns true if the result is selected for writing
+// when sampling is enabled.
+func (w *StandardWriter) isSampledResult(_ *Result) bool {
+	if w.sampler == nil {
+		return true
+	}
+	return w.sampler.sample()
+}
+
+// newContentTypeSet returns a lookup set of normalized content-types
+func newContentTypeSet(contentTypes []string) map[string]struct{} {
+	if len(contentTypes) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(contentTypes))
+	for _, contentType := range contentTypes {
+		set[getMediaType(contentType)] = struct{}{}
+	}
+	return set
+}
+
+// getMediaType returns the lowercase media type of a content-type
+// value stripping any parameters like charset.
+func getMediaType(contentType string) string {
+	if index := strings.IndexByte(contentType, ';'); index != -1 {
+		contentType = contentType[:index]
+	}
+	return strings.ToLower(strings.TrimSpace(contentType))
+}
@@ -0,0 +1,184 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/levels"
+	"github.com/projectdiscovery/gologger/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMediaType(t *testing.T) {
+	require.Equal(t, "text/html", getMediaType("text/html; charset=UTF-8"), "could not strip charset")
+	require.Equal(t, "application/json", getMediaType(" Application/JSON "), "could not normalize media type")
+	require.Equal(t, "", getMediaType(""), "could not get blank media type")
+}
+
+func TestGetFilterReasonContentType(t *testing.T) {
+	w := &StandardWriter{matchContentTypes: newContentTypeSet([]string{"text/html", ""})}
+	require.Equal(t, "", w.getFilterReason(&Result{ContentType: "text/html"}), "could not match content-type")
+	require.Equal(t, "", w.getFilterReason(&Result{}), "could not match blank content-type")
+	require.Equal(t, "content-type \"image/png\" is not matched", w.getFilterReason(&Result{ContentType: "image/png"}), "could match unknown content-type")
+
+	w = &StandardWriter{filterContentTypes: newContentTypeSet([]string{"Image/PNG"})}
+	require.Equal(t, "content-type \"image/png\" is filtered", w.getFilterReason(&Result{ContentType: "image/png"}), "could not filter content-type")
+	require.Equal(t, "", w.getFilterReason(&Result{ContentType: "text/html"}), "could filter other content-type")
+}
+
+func TestGetFilterReasonRegex(t *testing.T) {
+	writer, err := NewWithOptions(&Options{MatchRegex: []string{`/api/`}, FilterRegex: []string{`(?i)logout`}})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	w := writer.(*StandardWriter)
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/api/users"}), "could not match regex")
+	require.Equal(t, "url is not matched by the match regexes", w.getFilterReason(&Result{URL: "https://example.com/about"}), "could match unmatched url")
+	require.Equal(t, "url is matched by the filter regexes", w.getFilterReason(&Result{URL: "https://example.com/api/Logout"}), "could not filter regex")
+
+	_, err = NewWithOptions(&Options{FilterRegex: []string{`(`}})
+	require.Error(t, err, "got no error for invalid regex")
+}
+
+func TestGetFilterReasonExtensions(t *testing.T) {
+	require.Equal(t, ".js", getURLExtension("https://example.com/static/App.JS?v=1.2#main.css"), "could not get url extension")
+	require.Equal(t, "", getURLExtension("https://example.com.br/"), "could get host extension")
+
+	w := &StandardWriter{matchExtensions: newExtensionSet([]string{"js", ".PHP"})}
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/app.js?v=1"}), "could not match extension")
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/index.php"}), "could not match extension")
+	require.Equal(t, "extension \"\" is not matched", w.getFilterReason(&Result{URL: "https://example.com/"}), "could match url without extension")
+
+	w = &StandardWriter{filterExtensions: newExtensionSet([]string{"css", "png"})}
+	require.Equal(t, "extension \".css\" is filtered", w.getFilterReason(&Result{URL: "https://example.com/style.CSS#top"}), "could not filter extension")
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/app.js"}), "could filter other extension")
+}
+
+func TestNewStatusCodeSet(t *testing.T) {
+	set, err := newStatusCodeSet([]string{"200", "3XX"})
+	require.Nil(t, err, "could not parse status codes")
+	require.Len(t, set, 101, "could not parse status code range")
+	require.Contains(t, set, 200, "could not get status code")
+	require.Contains(t, set, 399, "could not get status code from range")
+
+	for _, value := range []string{"9xx", "0xx", "abc", "99", "600", "2x"} {
+		_, err = newStatusCodeSet([]string{value})
+		require.Error(t, err, "got no error for invalid status code %s", value)
+	}
+}
+
+func TestGetFilterReasonStatusCode(t *testing.T) {
+	writer, err := NewWithOptions(&Options{MatchStatusCodes: []string{"2xx", "301"}, FilterStatusCodes: []string{"204"}})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	w := writer.(*StandardWriter)
+	require.Equal(t, "", w.getFilterReason(&Result{StatusCode: 200}), "could not match status code range")
+	require.Equal(t, "", w.getFilterReason(&Result{StatusCode: 301}), "could not match status code")
+	require.Equal(t, "status code 204 is filtered", w.getFilterReason(&Result{StatusCode: 204}), "could not filter status code")
+	require.Equal(t, "status code 404 is not matched", w.getFilterReason(&Result{StatusCode: 404}), "could match unmatched status code")
+	require.Equal(t, "status code 0 is not matched", w.getFilterReason(&Result{}), "could match result without response")
+
+	_, err = NewWithOptions(&Options{FilterStatusCodes: []string{"9xx"}})
+	require.Error(t, err, "got no error for invalid status code range")
+}
+
+func TestGetFilterReasonMaxOutputDepth(t *testing.T) {
+	w := &StandardWriter{maxOutputDepth: 1}
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/"}), "could not match seed result")
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/a", Depth: 1}), "could not match result within depth")
+	require.Equal(t, "depth 2 is above the maximum output depth", w.getFilterReason(&Result{URL: "https://example.com/a/b", Depth: 2}), "could match result beyond depth")
+
+	w.maxOutputDepth = 0
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/a/b", Depth: 2}), "could filter depth without maximum")
+}
+
+func TestGetFilterReasonBodySize(t *testing.T) {
+	w := &StandardWriter{minBodySize: 10, maxBodySize: 100}
+	require.Equal(t, "body size 5 is out of the body size range", w.getFilterReason(&Result{URL: "https://example.com/a", StatusCode: 200, ContentLength: 5}), "could match result below minimum size")
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/b", StatusCode: 200, ContentLength: 50}), "could not match result within size")
+	require.Equal(t, "body size 500 is out of the body size range", w.getFilterReason(&Result{URL: "https://example.com/c", StatusCode: 200, ContentLength: 500}), "could match result above maximum size")
+	require.Equal(t, "", w.getFilterReason(&Result{URL: "https://example.com/d"}), "could filter result without response")
+
+	_, err := NewWithOptions(&Options{MinBodySize: 100, MaxBodySize: 10})
+	require.NotNil(t, err, "could not get invalid body size range error")
+}
+
+func TestWriteBodySizeUnknownLength(t *testing.T) {
+	var written []string
+	writer, err := NewWithOptions(&Options{MinBodySize: 5, Silent: true, OnResult: func(result *Result) {
+		written = append(written, result.URL)
+	}})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	for _, body := range []string{"tiny", "long enough body"} {
+		resp := &http.Response{
+			StatusCode:    200,
+			ContentLength: -1,
+			Header:        http.Header{},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			Request:       &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/" + strconv.Itoa(len(body))}},
+		}
+		require.Nil(t, writer.Write(&Result{URL: resp.Request.URL.String()}, resp), "could not write result")
+	}
+	require.Equal(t, []string{"https://example.com/16"}, written, "could not filter by measured body size")
+}
+
+func TestLogFiltered(t *testing.T) {
+	screen := &screenWriter{}
+	gologger.DefaultLogger.SetWriter(screen)
+	gologger.DefaultLogger.SetMaxLevel(levels.LevelDebug)
+	defer gologger.DefaultLogger.SetWriter(writer.NewCLI())
+	defer gologger.DefaultLogger.SetMaxLevel(levels.LevelInfo)
+
+	standardWriter, err := NewWithOptions(&Options{Silent: true, Dedup: true, FilterExtensions: []string{"png"}, FilterStatusCodes: []string{"404"}, LogFiltered: true})
+	require.Nil(t, err, "could not create writer")
+	defer standardWriter.Close()
+
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/a.png"}, nil), "could not filter result")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/missing", StatusCode: 404}, nil), "could not filter result")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/"}, nil), "could not filter duplicate result")
+	require.Len(t, screen.data, 3, "could not log filtered results")
+	require.Contains(t, screen.data[0], `Filtered https://example.com/a.png: extension ".png" is filtered`, "could not log filter reason")
+	require.Contains(t, screen.data[1], "Filtered https://example.com/missing: status code 404 is filtered", "could not log filter reason")
+	require.Contains(t, screen.data[2], "Filtered https://example.com/: duplicate of a written result", "could not log duplicate reason")
+
+	standardWriter, err = NewWithOptions(&Options{Silent: true, FilterExtensions: []string{"png"}})
+	require.Nil(t, err, "could not create writer")
+	defer standardWriter.Close()
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/a.png"}, nil), "could not filter result")
+	require.Len(t, screen.data, 3, "could log filtered results without log filtered")
+}
+
+func TestFilterBeforeEnrichment(t *testing.T) {
+	writer, err := NewWithOptions(&Options{Silent: true, FilterStatusCodes: []string{"404"}, DetectTechnologies: true, ExtractSelectors: map[string]string{"title": "title"}})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	for _, statusCode := range []int{200, 404} {
+		resp := &http.Response{
+			StatusCode: statusCode,
+			Header:     http.Header{"Content-Type": []string{"text/html"}, "Server": []string{"nginx"}},
+			Body:       io.NopCloser(strings.NewReader("<html><title>page</title></html>")),
+			Request:    &http.Request{Method: http.MethodGet, URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/" + strconv.Itoa(statusCode)}},
+		}
+		result := &Result{URL: resp.Request.URL.String()}
+		require.Nil(t, writer.Write(result, resp), "could not write result")
+		require.Equal(t, statusCode, result.StatusCode, "could not set status code before filtering")
+		if statusCode == 404 {
+			require.Empty(t, result.BodyHash, "could hash body of filtered result")
+			require.Empty(t, result.Extracted, "could extract selectors of filtered result")
+			require.Empty(t, result.Technologies, "could detect technologies of filtered result")
+		} else {
+			require.NotEmpty(t, result.BodyHash, "could not hash body of written result")
+			require.Equal(t, "page", result.Extracted["title"], "could not extract selectors of written result")
+		}
+	}
+}
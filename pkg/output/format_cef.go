@@ -0,0 +1,105 @@
+package output
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults of the CEF header device fields
+const (
+	DefaultCEFDeviceVendor  = "ProjectDiscovery"
+	DefaultCEFDeviceProduct = "katana"
+	DefaultCEFDeviceVersion = "1.0"
+)
+
+var (
+	// cefHeaderEscaper escapes the CEF header fields
+	cefHeaderEscaper = strings.NewReplacer(`\`, `\\`, "|", `\|`, "\n", " ", "\r", " ")
+	// cefExtensionEscaper escapes the CEF extension values
+	cefExtensionEscaper = strings.NewReplacer(`\`, `\\`, "=", `\=`, "\n", `\n`, "\r", `\r`)
+)
+
+// getCEFPrefix returns the CEF header up to the signature id for the
+// device fields, using the defaults for the empty ones.
+func getCEFPrefix(vendor, product, version string) string {
+	if vendor == "" {
+		vendor = DefaultCEFDeviceVendor
+	}
+	if product == "" {
+		product = DefaultCEFDeviceProduct
+	}
+	if version == "" {
+		version = DefaultCEFDeviceVersion
+	}
+	return "CEF:0|" + cefHeaderEscaper.Replace(vendor) + "|" + cefHeaderEscaper.Replace(product) + "|" + cefHeaderEscaper.Replace(version) + "|"
+}
+
+// formatCEF formats the output as a Common Event Format line.
+//
+// The result fields are mapped to the CEF extension keys, using the
+// custom string and number keys with labels for the fields without a
+// dedicated key.
+func (w *StandardWriter) formatCEF(output *Result) ([]byte, error) {
+	builder := &strings.Builder{}
+	builder.WriteString(w.cefPrefix)
+	if output.Error != "" {
+		builder.WriteString("request-error|Request failed|3|")
+	} else {
+		builder.WriteString("endpoint|Endpoint discovered|1|")
+	}
+
+	extension := &cefExtension{builder: builder}
+	if !output.Timestamp.IsZero() {
+		extension.add("rt", strconv.FormatInt(output.Timestamp.UnixNano()/int64(time.Millisecond), 10))
+	}
+	extension.add("request", output.URL)
+	extension.add("requestMethod", getMethod(output))
+	extension.add("requestContext", output.Source)
+	extension.addLabeled("cs1", "tag", output.Tag)
+	extension.addLabeled("cs2", "attribute", output.Attribute)
+	extension.addLabeled("cs3", "contentType", output.ContentType)
+	extension.addLabeled("cs4", "title", output.Title)
+	if output.StatusCode != 0 {
+		extension.addLabeled("cn1", "statusCode", strconv.Itoa(output.StatusCode))
+	}
+	if output.ContentLength > 0 {
+		extension.add("in", strconv.FormatInt(output.ContentLength, 10))
+	}
+	if output.Depth > 0 {
+		extension.addLabeled("cn2", "depth", strconv.Itoa(output.Depth))
+	}
+	extension.add("msg", output.Error)
+	return []byte(builder.String()), nil
+}
+
+// cefExtension writes the space separated key=value pairs of a CEF
+// extension
+type cefExtension struct {
+	builder *strings.Builder
+	written bool
+}
+
+// add writes the escaped key value pair unless the value is empty
+func (e *cefExtension) add(key, value string) {
+	if value == "" {
+		return
+	}
+	if e.written {
+		e.builder.WriteByte(' ')
+	}
+	e.written = true
+	e.builder.WriteString(key)
+	e.builder.WriteByte('=')
+	e.builder.WriteString(cefExtensionEscaper.Replace(value))
+}
+
+// addLabeled writes a custom key value pair with its label unless the
+// value is empty
+func (e *cefExtension) addLabeled(key, label, value string) {
+	if value == "" {
+		return
+	}
+	e.add(key+"Label", label)
+	e.add(key, value)
+}
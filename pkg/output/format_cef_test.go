@@ -0,0 +1,34 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCEF(t *testing.T) {
+	standardWriter := &StandardWriter{cefPrefix: getCEFPrefix("", "", "")}
+
+	data, err := standardWriter.formatCEF(&Result{
+		Timestamp:   time.Unix(1700000000, 0),
+		URL:         "https://example.com/search?q=a=b",
+		Source:      "https://example.com/",
+		Tag:         "a",
+		Attribute:   "href",
+		StatusCode:  200,
+		ContentType: "text/html",
+		Title:       "C:\\path\nline",
+		Depth:       2,
+	})
+	require.Nil(t, err, "could not format cef")
+	require.Equal(t, `CEF:0|ProjectDiscovery|katana|1.0|endpoint|Endpoint discovered|1|rt=1700000000000 request=https://example.com/search?q\=a\=b requestMethod=GET requestContext=https://example.com/ cs1Label=tag cs1=a cs2Label=attribute cs2=href cs3Label=contentType cs3=text/html cs4Label=title cs4=C:\\path\nline cn1Label=statusCode cn1=200 cn2Label=depth cn2=2`, string(data), "could not get cef line")
+
+	data, err = standardWriter.formatCEF(&Result{URL: "https://example.com/missing", Error: "connection refused"})
+	require.Nil(t, err, "could not format cef error")
+	require.Equal(t, `CEF:0|ProjectDiscovery|katana|1.0|request-error|Request failed|3|request=https://example.com/missing requestMethod=GET msg=connection refused`, string(data), "could not get cef error line")
+}
+
+func TestCEFPrefix(t *testing.T) {
+	require.Equal(t, `CEF:0|Acme\|Corp|Crawler\\X|2.0|`, getCEFPrefix("Acme|Corp", `Crawler\X`, "2.0"), "could not escape cef header")
+}
@@ -0,0 +1,121 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// csvColumns is the list of column names for csv output, derived
+// from the json tags of the Result structure.
+var csvColumns = getCSVColumns()
+
+func getCSVColumns() []string {
+	resultType := reflect.TypeOf(Result{})
+	columns := make([]string, 0, resultType.NumField())
+	for i := 0; i < resultType.NumField(); i++ {
+		columns = append(columns, getJSONFieldName(resultType.Field(i)))
+	}
+	return columns
+}
+
+// getJSONFieldName returns the json name of a struct field
+func getJSONFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if name := strings.Split(tag, ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+// formatCSVHeader formats the header row for csv based formatting
+func (w *StandardWriter) formatCSVHeader() ([]byte, error) {
+	return writeCSVRecord(csvColumns)
+}
+
+// formatCSV formats the output for csv based formatting
+func (w *StandardWriter) formatCSV(output *Result) ([]byte, error) {
+	value := reflect.ValueOf(*output)
+	record := make([]string, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		record = append(record, formatCSVValue(value.Field(i)))
+	}
+	return writeCSVRecord(record)
+}
+
+// tsvEscaper escapes the tabs and newlines in tsv values
+var tsvEscaper = strings.NewReplacer(`\`, `\\`, "\t", `\t`, "\n", `\n`, "\r", `\r`)
+
+// formatTSVHeader formats the header row for tsv based formatting
+func (w *StandardWriter) formatTSVHeader() ([]byte, error) {
+	return []byte(strings.Join(csvColumns, "\t")), nil
+}
+
+// formatCSVFileHeader formats the header row for the csv or tsv output
+func (w *StandardWriter) formatCSVFileHeader() ([]byte, error) {
+	if w.tsv {
+		return w.formatTSVHeader()
+	}
+	return w.formatCSVHeader()
+}
+
+// formatTSV formats the output for tsv based formatting.
+//
+// Values are not quoted, the backslashes, tabs and newlines in values
+// are escaped as \\, \t, \n and \r literals instead.
+func (w *StandardWriter) formatTSV(output *Result) ([]byte, error) {
+	value := reflect.ValueOf(*output)
+	record := make([]string, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		record = append(record, tsvEscaper.Replace(formatCSVValue(value.Field(i))))
+	}
+	return []byte(strings.Join(record, "\t")), nil
+}
+
+// writeCSVRecord writes a RFC 4180 quoted record without the trailing newline
+func writeCSVRecord(record []string) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+	if err := writer.Write(record); err != nil {
+		return nil, err
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
+// formatCSVValue returns the string representation of a result field
+func formatCSVValue(value reflect.Value) string {
+	switch v := value.Interface().(type) {
+	case string:
+		return v
+	case Duration:
+		return formatLatency(v)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	}
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Int() == 0 {
+			return ""
+		}
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool())
+	}
+	if value.IsZero() {
+		return ""
+	}
+	data, _ := jsoniter.Marshal(value.Interface())
+	return string(data)
+}
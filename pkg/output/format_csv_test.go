@@ -0,0 +1,71 @@
+package output
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCSV(t *testing.T) {
+	w := StandardWriter{}
+
+	header, err := w.formatCSVHeader()
+	require.Nil(t, err, "could not format csv header")
+	columns, err := csv.NewReader(strings.NewReader(string(header))).Read()
+	require.Nil(t, err, "could not parse csv header")
+	require.Subset(t, columns, []string{"timestamp", "method", "body", "endpoint", "source", "tag", "attribute"}, "could not get csv header")
+
+	result := &Result{
+		Method: "POST",
+		Body:   "a=1,b=\"2\"\nc=3",
+		URL:    "https://example.com/login",
+		Tag:    "form",
+	}
+	data, err := w.formatCSV(result)
+	require.Nil(t, err, "could not format csv")
+
+	record, err := csv.NewReader(strings.NewReader(string(data))).Read()
+	require.Nil(t, err, "could not parse csv record")
+	require.Len(t, record, len(columns), "could not equal csv record length")
+
+	values := make(map[string]string)
+	for i, column := range columns {
+		values[column] = record[i]
+	}
+	require.Equal(t, "POST", values["method"], "could not equal csv method")
+	require.Equal(t, result.Body, values["body"], "could not equal quoted csv body")
+	require.Equal(t, result.URL, values["endpoint"], "could not equal csv url")
+	require.Equal(t, "", values["timestamp"], "could not equal csv zero timestamp")
+}
+
+func TestFormatTSV(t *testing.T) {
+	w := StandardWriter{}
+
+	header, err := w.formatTSVHeader()
+	require.Nil(t, err, "could not format tsv header")
+	columns := strings.Split(string(header), "\t")
+	require.Subset(t, columns, []string{"timestamp", "method", "body", "endpoint", "source", "tag", "attribute"}, "could not get tsv header")
+
+	result := &Result{
+		Method: "POST",
+		Body:   "a=1\tb=\"2\"\nc=3\\d",
+		URL:    "https://example.com/login",
+		Tag:    "form",
+	}
+	data, err := w.formatTSV(result)
+	require.Nil(t, err, "could not format tsv")
+	require.NotContains(t, string(data), "\n", "could not escape tsv newline")
+
+	record := strings.Split(string(data), "\t")
+	require.Len(t, record, len(columns), "could not equal tsv record length")
+
+	values := make(map[string]string)
+	for i, column := range columns {
+		values[column] = record[i]
+	}
+	require.Equal(t, "POST", values["method"], "could not equal tsv method")
+	require.Equal(t, `a=1\tb="2"\nc=3\\d`, values["body"], "could not equal escaped tsv body")
+	require.Equal(t, result.URL, values["endpoint"], "could not equal tsv url")
+}
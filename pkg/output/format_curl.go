@@ -0,0 +1,68 @@
+package output
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// curlSkippedHeaders are the request headers which curl sets by itself
+var curlSkippedHeaders = map[string]struct{}{
+	"Content-Length": {},
+}
+
+// formatCurl formats the output as a curl command replaying the request
+// of the result with its method, captured request headers and body.
+func (w *StandardWriter) formatCurl(output *Result) ([]byte, error) {
+	builder := &strings.Builder{}
+	builder.WriteString("curl")
+	method := getMethod(output)
+	if method != http.MethodGet {
+		builder.WriteString(" -X ")
+		builder.WriteString(shellQuote(method))
+	}
+	builder.WriteByte(' ')
+	builder.WriteString(shellQuote(output.URL))
+
+	names := make([]string, 0, len(output.RequestHeaders))
+	for name, value := range output.RequestHeaders {
+		if _, ok := curlSkippedHeaders[http.CanonicalHeaderKey(name)]; ok {
+			continue
+		}
+		// the host header is only kept when it differs from the url host
+		if strings.EqualFold(name, "Host") {
+			if parsed, err := url.Parse(output.URL); err == nil && parsed.Host == value {
+				continue
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		builder.WriteString(" -H ")
+		builder.WriteString(shellQuote(name + ": " + output.RequestHeaders[name]))
+	}
+	if output.Body != "" {
+		builder.WriteString(" --data-raw ")
+		builder.WriteString(shellQuote(output.Body))
+	}
+	return []byte(builder.String()), nil
+}
+
+// shellQuote quotes the value as a single POSIX shell word, leaving the
+// values without shell-special characters unquoted.
+func shellQuote(value string) string {
+	if value != "" && strings.IndexFunc(value, isShellSpecial) == -1 {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// isShellSpecial returns true if the character needs quoting in a shell
+func isShellSpecial(r rune) bool {
+	if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+		return false
+	}
+	return !strings.ContainsRune("-_./:@%+,", r)
+}
@@ -0,0 +1,36 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatCurl(t *testing.T) {
+	standardWriter := &StandardWriter{}
+
+	data, err := standardWriter.formatCurl(&Result{URL: "https://example.com/path?a=1&b=2"})
+	require.Nil(t, err, "could not format curl")
+	require.Equal(t, `curl 'https://example.com/path?a=1&b=2'`, string(data), "could not get get curl")
+
+	data, err = standardWriter.formatCurl(&Result{
+		Method:         "POST",
+		URL:            "https://example.com/login",
+		Body:           "user=it's&pass=$(id)",
+		RequestHeaders: map[string]string{"User-Agent": "katana", "Content-Length": "20", "Cookie": "a=b; c=d", "Host": "example.com"},
+	})
+	require.Nil(t, err, "could not format curl")
+	require.Equal(t, `curl -X POST https://example.com/login -H 'Cookie: a=b; c=d' -H 'User-Agent: katana' --data-raw 'user=it'\''s&pass=$(id)'`, string(data), "could not get post curl")
+
+	data, err = standardWriter.formatCurl(&Result{URL: "https://127.0.0.1/", RequestHeaders: map[string]string{"Host": "internal.example.com"}})
+	require.Nil(t, err, "could not format curl")
+	require.Equal(t, `curl https://127.0.0.1/ -H 'Host: internal.example.com'`, string(data), "could not keep virtual host header")
+}
+
+func TestShellQuote(t *testing.T) {
+	require.Equal(t, "https://example.com/a", shellQuote("https://example.com/a"), "could not keep safe word")
+	require.Equal(t, "''", shellQuote(""), "could not quote empty word")
+	require.Equal(t, `'a b'`, shellQuote("a b"), "could not quote space")
+	require.Equal(t, `'it'\''s'`, shellQuote("it's"), "could not escape single quote")
+	require.Equal(t, "'`id`;!x'", shellQuote("`id`;!x"), "could not quote special characters")
+}
@@ -0,0 +1,126 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+// DefaultGraphMaxNodes is the default maximum number of nodes in the graph
+const DefaultGraphMaxNodes = 10000
+
+// linkGraph is the link structure of a crawl with the URLs as nodes and
+// the parent to child relationships as edges.
+type linkGraph struct {
+	maxNodes int
+	// nodes maps the URLs to their node ids, which are the indexes
+	// of the URLs in the urls list.
+	nodes map[string]int
+	urls  []string
+	edges map[[2]int]struct{}
+	// edgeList contains the edges in insertion order for stable output
+	edgeList  [][2]int
+	truncated bool
+}
+
+// newLinkGraph creates a new empty link graph with a maximum number of nodes
+func newLinkGraph(maxNodes int) *linkGraph {
+	if maxNodes <= 0 {
+		maxNodes = DefaultGraphMaxNodes
+	}
+	return &linkGraph{
+		maxNodes: maxNodes,
+		nodes:    make(map[string]int),
+		edges:    make(map[[2]int]struct{}),
+	}
+}
+
+// addEdge adds a deduplicated edge from the parent to the child URL.
+//
+// Once the maximum number of nodes is reached, the edges to new nodes
+// are dropped and a warning is shown once.
+func (g *linkGraph) addEdge(parent, child string) {
+	from, ok := g.getNode(parent)
+	if !ok {
+		return
+	}
+	to, ok := g.getNode(child)
+	if !ok {
+		return
+	}
+	edge := [2]int{from, to}
+	if _, ok := g.edges[edge]; ok {
+		return
+	}
+	g.edges[edge] = struct{}{}
+	g.edgeList = append(g.edgeList, edge)
+}
+
+// getNode returns the node id of the URL, adding the node if it does not
+// exist and the maximum number of nodes is not reached.
+func (g *linkGraph) getNode(URL string) (int, bool) {
+	if id, ok := g.nodes[URL]; ok {
+		return id, true
+	}
+	if len(g.urls) >= g.maxNodes {
+		if !g.truncated {
+			g.truncated = true
+			gologger.Warning().Msgf("Graph output reached the maximum of %d nodes, new URLs will not be added\n", g.maxNodes)
+		}
+		return 0, false
+	}
+	id := len(g.urls)
+	g.nodes[URL] = id
+	g.urls = append(g.urls, URL)
+	return id, true
+}
+
+// dotEscaper escapes the characters of quoted DOT strings
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatDOT formats the link graph as a Graphviz DOT document
+func (g *linkGraph) formatDOT() []byte {
+	builder := &bytes.Buffer{}
+	builder.WriteString("digraph katana {\n")
+	for id, URL := range g.urls {
+		builder.WriteString("  n")
+		builder.WriteString(strconv.Itoa(id))
+		builder.WriteString(` [label="`)
+		builder.WriteString(dotEscaper.Replace(URL))
+		builder.WriteString("\"];\n")
+	}
+	for _, edge := range g.edgeList {
+		builder.WriteString("  n")
+		builder.WriteString(strconv.Itoa(edge[0]))
+		builder.WriteString(" -> n")
+		builder.WriteString(strconv.Itoa(edge[1]))
+		builder.WriteString(";\n")
+	}
+	builder.WriteString("}\n")
+	return builder.Bytes()
+}
+
+// updateGraph adds the edge from the parent of a written result
+func (w *StandardWriter) updateGraph(event *Result) {
+	if event.Parent == "" {
+		return
+	}
+	w.outputMutex.Lock()
+	w.graph.addEdge(event.Parent, event.URL)
+	w.outputMutex.Unlock()
+}
+
+// writeGraph writes the link graph to the graph output file
+func (w *StandardWriter) writeGraph() error {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	if err := os.WriteFile(w.graphFile, w.graph.formatDOT(), 0644); err != nil {
+		return errors.Wrap(err, "could not write graph output")
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "graph.dot")
+
+	writer, err := NewWithOptions(&Options{GraphOutput: file, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write seed result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a", Parent: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/\"b\"", Parent: "https://example.com/a"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a", Parent: "https://example.com/", Method: "POST"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read graph output")
+	require.Equal(t, `digraph katana {
+  n0 [label="https://example.com/"];
+  n1 [label="https://example.com/a"];
+  n2 [label="https://example.com/\"b\""];
+  n0 -> n1;
+  n1 -> n2;
+}
+`, string(data), "could not get deduplicated graph")
+}
+
+func TestLinkGraphMaxNodes(t *testing.T) {
+	graph := newLinkGraph(2)
+	graph.addEdge("https://example.com/", "https://example.com/a")
+	graph.addEdge("https://example.com/", "https://example.com/b")
+	graph.addEdge("https://example.com/a", "https://example.com/")
+
+	require.Len(t, graph.urls, 2, "could not cap graph nodes")
+	require.Equal(t, [][2]int{{0, 1}, {1, 0}}, graph.edgeList, "could not keep edges of existing nodes")
+	require.True(t, graph.truncated, "could not mark truncated graph")
+}
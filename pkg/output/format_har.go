@@ -0,0 +1,194 @@
+package output
+
+import (
+	"encoding/base64"
+	"net/http"
+	"sort"
+	"time"
+	"unicode/utf8"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const harVersion = "1.2"
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// newHAREntry creates a HAR entry for the request/response pair of the
+// response. The event is optional and provides the timing of the entry.
+func newHAREntry(event *Result, resp *http.Response) harEntry {
+	started := time.Now()
+	var latency float64
+	if event != nil {
+		if !event.Timestamp.IsZero() {
+			started = event.Timestamp
+		}
+		latency = float64(time.Duration(event.Latency)) / float64(time.Millisecond)
+	}
+	req := resp.Request
+
+	request := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     getHARHeaders(req.Header),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	if request.HTTPVersion == "" {
+		request.HTTPVersion = "HTTP/1.1"
+	}
+	for _, cookie := range req.Cookies() {
+		request.Cookies = append(request.Cookies, harNameValue{Name: cookie.Name, Value: cookie.Value})
+	}
+	query := req.URL.Query()
+	for _, name := range getSortedKeys(query) {
+		for _, value := range query[name] {
+			request.QueryString = append(request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if body := readRequestBody(req); len(body) > 0 {
+		request.BodySize = len(body)
+		request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: string(body)}
+	}
+
+	body := readResponseBody(resp)
+	response := harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     getHARHeaders(resp.Header),
+		Content: harContent{
+			Size:     len(body),
+			MimeType: resp.Header.Get("Content-Type"),
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for _, cookie := range resp.Cookies() {
+		response.Cookies = append(response.Cookies, harNameValue{Name: cookie.Name, Value: cookie.Value})
+	}
+	if utf8.Valid(body) {
+		response.Content.Text = string(body)
+	} else {
+		response.Content.Text = base64.StdEncoding.EncodeToString(body)
+		response.Content.Encoding = "base64"
+	}
+
+	return harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            latency,
+		Request:         request,
+		Response:        response,
+		Timings:         harTimings{Send: 0, Wait: latency, Receive: 0},
+	}
+}
+
+// getHARHeaders returns the headers as HAR name/value pairs sorted by name
+func getHARHeaders(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range getSortedKeys(header) {
+		for _, value := range header[name] {
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+func getSortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatHAR formats the buffered entries as a HAR document
+func (w *StandardWriter) formatHAR() ([]byte, error) {
+	entries := w.harEntries
+	if entries == nil {
+		entries = []harEntry{}
+	}
+	document := &harDocument{
+		Log: harLog{
+			Version: harVersion,
+			Creator: harCreator{Name: "katana"},
+			Entries: entries,
+		},
+	}
+	return jsoniter.MarshalIndent(document, "", "  ")
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.har")
+
+	writer, err := NewWithOptions(&Options{HAR: true, OutputFile: file})
+	require.Nil(t, err, "could not create har writer")
+
+	newResponse := func(path string, body []byte) *http.Response {
+		return &http.Response{
+			StatusCode: 200,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Content-Type": []string{"text/html"}},
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Request: &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: path, RawQuery: "b=2&a=1"},
+				Proto:  "HTTP/1.1",
+				Header: http.Header{"User-Agent": []string{"katana"}},
+			},
+		}
+	}
+	result := &Result{URL: "https://example.com/a?b=2&a=1", Timestamp: time.Unix(0, 0).UTC(), Latency: Duration(2 * time.Millisecond)}
+	require.Nil(t, writer.Write(nil, newResponse("/", []byte("<html></html>"))), "could not write seed response")
+	require.Nil(t, writer.Write(result, newResponse("/a", []byte{0xff, 0xfe})), "could not write response")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/b"}, nil), "could not write result without response")
+	require.Nil(t, writer.Close(), "could not close har writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read har output")
+
+	var document harDocument
+	require.Nil(t, jsoniter.Unmarshal(data, &document), "could not decode har output")
+	require.Equal(t, harVersion, document.Log.Version, "could not get har version")
+	require.Len(t, document.Log.Entries, 2, "could not get har entries")
+
+	seed := document.Log.Entries[0]
+	require.Equal(t, "https://example.com/?b=2&a=1", seed.Request.URL, "could not get request url")
+	require.Equal(t, []harNameValue{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}}, seed.Request.QueryString, "could not get query string")
+	require.Equal(t, []harNameValue{{Name: "User-Agent", Value: "katana"}}, seed.Request.Headers, "could not get request headers")
+	require.Equal(t, "<html></html>", seed.Response.Content.Text, "could not get response content")
+
+	entry := document.Log.Entries[1]
+	require.Equal(t, "1970-01-01T00:00:00Z", entry.StartedDateTime, "could not get entry start time")
+	require.Equal(t, float64(2), entry.Time, "could not get entry time")
+	require.Equal(t, 200, entry.Response.Status, "could not get response status")
+	require.Equal(t, "base64", entry.Response.Content.Encoding, "could not encode binary content")
+	require.Equal(t, "//4=", entry.Response.Content.Text, "could not get binary content")
+}
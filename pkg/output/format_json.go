@@ -1,8 +1,73 @@
 package output
 
-import jsoniter "github.com/json-iterator/go"
+import (
+	"time"
 
-// formatJSON formats the output for json based formatting
+	jsoniter "github.com/json-iterator/go"
+)
+
+// jsonResult is the JSON representation of a result with the timestamp
+// formatted in the configured timestamp format
+type jsonResult struct {
+	// Timestamp shadows the timestamp of the result, it is declared first
+	// to keep the field order of the result.
+	Timestamp interface{} `json:"timestamp,omitempty"`
+	*Result
+}
+
+// getJSONResult returns the JSON representation of the result
+func (w *StandardWriter) getJSONResult(output *Result) *jsonResult {
+	return &jsonResult{Timestamp: w.formatTimestamp(output.Timestamp), Result: output}
+}
+
+// formatTimestamp returns the timestamp in the configured timestamp format,
+// or nil for the zero timestamp so that it is omitted.
+func (w *StandardWriter) formatTimestamp(timestamp time.Time) interface{} {
+	if timestamp.IsZero() {
+		return nil
+	}
+	switch w.timestampFormat {
+	case TimestampFormatEpoch:
+		return timestamp.Unix()
+	case TimestampFormatEpochMillis:
+		return timestamp.UnixNano() / int64(time.Millisecond)
+	default:
+		return timestamp.Format(time.RFC3339Nano)
+	}
+}
+
+// formatJSON formats the output for json based formatting.
+//
+// The returned data is a single compact JSON object like the jsonl
+// output, unless the pretty json output is enabled.
 func (w *StandardWriter) formatJSON(output *Result) ([]byte, error) {
-	return jsoniter.Marshal(output)
+	data, err := w.marshalJSONResult(output)
+	if err != nil || !w.prettyJSON {
+		return data, err
+	}
+	return indentJSON(data)
+}
+
+// formatJSONL formats the output for jsonl based formatting.
+//
+// The returned data is always a single compact JSON object without
+// any newlines, the line terminator is added by the output writers.
+func (w *StandardWriter) formatJSONL(output *Result) ([]byte, error) {
+	return w.marshalJSONResult(output)
+}
+
+// marshalJSONResult returns the compact json of the result, or of its
+// selected fields, with the keys renamed by the field aliases
+func (w *StandardWriter) marshalJSONResult(output *Result) ([]byte, error) {
+	var data []byte
+	var err error
+	if len(w.jsonFields) > 0 {
+		data, err = w.marshalJSONFields(output)
+	} else {
+		data, err = jsoniter.Marshal(w.getJSONResult(output))
+	}
+	if err != nil || len(w.fieldAliases) == 0 {
+		return data, err
+	}
+	return renameJSONKeys(data, w.fieldAliases)
 }
@@ -0,0 +1,110 @@
+package output
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatJSONL(t *testing.T) {
+	w := StandardWriter{}
+	result := &Result{Method: "POST", Body: "a=1\nb=2\r\n", URL: "https://example.com/"}
+
+	data, err := w.formatJSONL(result)
+	require.Nil(t, err, "could not format jsonl")
+	require.False(t, bytes.ContainsAny(data, "\r\n"), "got newline in jsonl output")
+
+	var decoded Result
+	require.Nil(t, jsoniter.Unmarshal(data, &decoded), "could not decode jsonl")
+	require.Equal(t, *result, decoded, "could not equal decoded result")
+}
+
+func TestFormatJSON(t *testing.T) {
+	result := &Result{Method: "POST", Body: "a=1\nb=2\r\n", URL: "https://example.com/"}
+
+	w := StandardWriter{}
+	data, err := w.formatJSON(result)
+	require.Nil(t, err, "could not format json")
+	require.False(t, bytes.ContainsAny(data, "\r\n"), "got newline in json output")
+
+	w = StandardWriter{prettyJSON: true}
+	data, err = w.formatJSON(result)
+	require.Nil(t, err, "could not format pretty json")
+	require.True(t, bytes.HasPrefix(data, []byte("{\n  \"method\": \"POST\",")), "could not indent pretty json")
+}
+
+func TestFormatJSONLatency(t *testing.T) {
+	w := StandardWriter{}
+
+	data, err := w.formatJSONL(&Result{URL: "https://example.com/", Latency: Duration(1500 * time.Microsecond)})
+	require.Nil(t, err, "could not format jsonl")
+	require.Contains(t, string(data), `"latency":1.5`, "could not serialize latency as milliseconds")
+
+	data, err = w.formatJSONL(&Result{URL: "https://example.com/"})
+	require.Nil(t, err, "could not format jsonl")
+	require.NotContains(t, string(data), "latency", "could not omit zero latency")
+}
+
+func TestFormatJSONTimestamp(t *testing.T) {
+	timestamp := time.Date(2022, 11, 7, 10, 30, 0, 250*int(time.Millisecond), time.FixedZone("", 3600))
+	result := &Result{Timestamp: timestamp, URL: "https://example.com/"}
+
+	for format, expected := range map[string]string{
+		"":                         `"timestamp":"2022-11-07T10:30:00.25+01:00"`,
+		TimestampFormatRFC3339:     `"timestamp":"2022-11-07T10:30:00.25+01:00"`,
+		TimestampFormatEpoch:       `"timestamp":1667813400`,
+		TimestampFormatEpochMillis: `"timestamp":1667813400250`,
+	} {
+		w := StandardWriter{timestampFormat: format}
+		data, err := w.formatJSONL(result)
+		require.Nil(t, err, "could not format jsonl")
+		require.Contains(t, string(data), expected, "could not format timestamp as %s", format)
+	}
+
+	w := StandardWriter{timestampFormat: TimestampFormatEpoch}
+	data, err := w.formatJSONL(&Result{URL: "https://example.com/"})
+	require.Nil(t, err, "could not format jsonl")
+	require.NotContains(t, string(data), "timestamp", "could not omit zero timestamp")
+
+	_, err = NewWithOptions(&Options{TimestampFormat: "unix"})
+	require.NotNil(t, err, "could not get invalid timestamp format error")
+}
+
+func TestFormatYAML(t *testing.T) {
+	w := StandardWriter{}
+	result := &Result{Timestamp: time.Unix(0, 0).UTC(), Method: "GET", URL: "https://example.com/", StatusCode: 200, Latency: Duration(1500 * time.Microsecond)}
+
+	data, err := w.formatYAML(result)
+	require.Nil(t, err, "could not format yaml")
+	require.Equal(t, "---\ntimestamp: \"1970-01-01T00:00:00Z\"\nmethod: GET\nendpoint: https://example.com/\nstatus_code: 200\nlatency: 1.5", string(data), "could not get yaml output")
+}
+
+func TestJSONArrayOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.json")
+
+	writer, err := NewWithOptions(&Options{JSONArray: true, OutputFile: file, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/b"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	var results []Result
+	require.Nil(t, jsoniter.Unmarshal(data, &results), "could not unmarshal json array")
+	require.Len(t, results, 2, "could not get json array results")
+	require.Equal(t, "https://example.com/b", results[1].URL, "could not get json array result")
+
+	writer, err = NewWithOptions(&Options{JSONArray: true, OutputFile: file, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err = os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "[]\n", string(data), "could not get empty json array")
+}
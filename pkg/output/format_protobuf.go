@@ -0,0 +1,62 @@
+package output
+
+import (
+	"time"
+
+	"github.com/projectdiscovery/katana/pkg/output/pb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// formatProtobuf formats the output as a varint length-prefixed protobuf
+// message, which is the same framing as the delimited protobuf encoders.
+func (w *StandardWriter) formatProtobuf(output *Result) ([]byte, error) {
+	message := &pb.Result{
+		Method:          output.Method,
+		Body:            output.Body,
+		Endpoint:        output.URL,
+		Source:          output.Source,
+		Tag:             output.Tag,
+		Attribute:       output.Attribute,
+		StatusCode:      int32(output.StatusCode),
+		ContentLength:   output.ContentLength,
+		ContentType:     output.ContentType,
+		Latency:         int64(time.Duration(output.Latency)),
+		ResponseHeaders: output.ResponseHeaders,
+		RequestHeaders:  output.RequestHeaders,
+		Redirects:       output.Redirects,
+		RedirectLoop:    output.RedirectLoop,
+		Title:           output.Title,
+		BodyHash:        output.BodyHash,
+		Depth:           int32(output.Depth),
+		Parent:          output.Parent,
+		Error:           output.Error,
+		Technologies:    output.Technologies,
+		SourceSnippet:   output.SourceSnippet,
+		Params:          output.Params,
+		Proto:           output.Proto,
+		DuplicateCount:  int32(output.DuplicateCount),
+		Seq:             output.Seq,
+		Extracted:       output.Extracted,
+		RawUrl:          output.RawURL,
+	}
+	if output.Form != nil {
+		message.FormAction = output.Form.Action
+		message.FormMethod = output.Form.Method
+		message.FormInputs = output.Form.Inputs
+	}
+	if output.TLS != nil {
+		message.TlsSubjectCn = output.TLS.SubjectCN
+		message.TlsSubjectOrg = output.TLS.SubjectOrg
+		message.TlsIssuer = output.TLS.Issuer
+		message.TlsNotBefore = output.TLS.NotBefore.UnixNano()
+		message.TlsNotAfter = output.TLS.NotAfter.UnixNano()
+		message.TlsSans = output.TLS.SANs
+	}
+	if !output.Timestamp.IsZero() {
+		message.Timestamp = output.Timestamp.UnixNano()
+	}
+	size := proto.Size(message)
+	data := protowire.AppendVarint(make([]byte, 0, protowire.SizeVarint(uint64(size))+size), uint64(size))
+	return proto.MarshalOptions{}.MarshalAppend(data, message)
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/katana/pkg/output/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+var benchmarkResult = &Result{
+	Timestamp:     time.Now(),
+	Method:        "GET",
+	URL:           "https://example.com/api/v1/users?id=1",
+	Source:        "https://example.com/",
+	Tag:           "a",
+	Attribute:     "href",
+	StatusCode:    200,
+	ContentLength: 1024,
+	ContentType:   "text/html",
+	Latency:       Duration(150 * time.Millisecond),
+}
+
+func TestProtobufWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.bin")
+
+	writer, err := NewWithOptions(&Options{Protobuf: true, OutputFile: file})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(benchmarkResult, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/b"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+
+	var messages []*pb.Result
+	for len(data) > 0 {
+		size, n := protowire.ConsumeVarint(data)
+		require.Greater(t, n, 0, "could not read message length")
+		data = data[n:]
+
+		message := &pb.Result{}
+		require.Nil(t, proto.Unmarshal(data[:size], message), "could not decode message")
+		messages = append(messages, message)
+		data = data[size:]
+	}
+	require.Len(t, messages, 2, "could not get messages")
+	require.Equal(t, benchmarkResult.URL, messages[0].Endpoint, "could not get endpoint")
+	require.Equal(t, int32(200), messages[0].StatusCode, "could not get status code")
+	require.Equal(t, benchmarkResult.Timestamp.UnixNano(), messages[0].Timestamp, "could not get timestamp")
+	require.Equal(t, int64(150*time.Millisecond), messages[0].Latency, "could not get latency")
+	require.Equal(t, "https://example.com/b", messages[1].Endpoint, "could not get endpoint")
+}
+
+func BenchmarkFormatProtobuf(b *testing.B) {
+	w := &StandardWriter{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = w.formatProtobuf(benchmarkResult)
+	}
+}
+
+func BenchmarkFormatJSON(b *testing.B) {
+	w := &StandardWriter{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = w.formatJSON(benchmarkResult)
+	}
+}
+
+func BenchmarkFormatJSONL(b *testing.B) {
+	w := &StandardWriter{}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = w.formatJSONL(benchmarkResult)
+	}
+}
@@ -0,0 +1,175 @@
+package output
+
+import (
+	"net/http"
+	"os"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// sarifDefaultRuleID is the rule id for results without a source
+	sarifDefaultRuleID = "katana"
+)
+
+// sarifWriter is a writer which buffers the results in memory and writes
+// them as a SARIF 2.1.0 document on close.
+type sarifWriter struct {
+	file    string
+	mutex   *sync.Mutex
+	rules   []sarifRule
+	ruleIDs map[string]struct{}
+	results []sarifResult
+}
+
+type sarifDocument struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// NewSARIFWriter returns a writer which writes the results as a SARIF
+// document to the file on close.
+//
+// SARIF requires the complete document at once, so the results are
+// buffered in memory until the writer is closed.
+func NewSARIFWriter(file string) (Writer, error) {
+	if file == "" {
+		return nil, errors.New("no sarif output file specified")
+	}
+	return &sarifWriter{
+		file:    file,
+		mutex:   &sync.Mutex{},
+		ruleIDs: make(map[string]struct{}),
+	}, nil
+}
+
+// Write buffers the event for the SARIF document
+func (w *sarifWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	ruleID := event.Source
+	if ruleID == "" {
+		ruleID = sarifDefaultRuleID
+	}
+	properties := make(map[string]string)
+	if event.Method != "" {
+		properties["method"] = event.Method
+	}
+	if event.Tag != "" {
+		properties["tag"] = event.Tag
+	}
+	if event.Attribute != "" {
+		properties["attribute"] = event.Attribute
+	}
+	result := sarifResult{
+		RuleID:     ruleID,
+		Level:      "note",
+		Message:    sarifMessage{Text: event.URL},
+		Locations:  []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: event.URL}}}},
+		Properties: properties,
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if _, ok := w.ruleIDs[ruleID]; !ok {
+		w.ruleIDs[ruleID] = struct{}{}
+		w.rules = append(w.rules, sarifRule{ID: ruleID})
+	}
+	w.results = append(w.results, result)
+	return nil
+}
+
+// Flush is a no-op as the SARIF document can only be written on close
+func (w *sarifWriter) Flush() error {
+	return nil
+}
+
+// Close writes the SARIF document with the buffered results to the file
+func (w *sarifWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	data, err := w.formatSARIF()
+	if err != nil {
+		return errors.Wrap(err, "could not format sarif output")
+	}
+	if err := os.WriteFile(w.file, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write sarif output")
+	}
+	return nil
+}
+
+// formatSARIF formats the buffered results as a SARIF document
+func (w *sarifWriter) formatSARIF() ([]byte, error) {
+	rules := w.rules
+	if rules == nil {
+		rules = []sarifRule{}
+	}
+	results := w.results
+	if results == nil {
+		results = []sarifResult{}
+	}
+	document := &sarifDocument{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "katana",
+				InformationURI: "https://github.com/projectdiscovery/katana",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}
+	return jsoniter.MarshalIndent(document, "", "  ")
+}
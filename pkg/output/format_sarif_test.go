@@ -0,0 +1,38 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSARIFWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.sarif")
+
+	writer, err := NewSARIFWriter(file)
+	require.Nil(t, err, "could not create sarif writer")
+
+	require.Nil(t, writer.Write(&Result{Method: "GET", URL: "https://example.com/a", Source: "https://example.com/", Tag: "a", Attribute: "href"}, nil))
+	require.Nil(t, writer.Write(&Result{Method: "GET", URL: "https://example.com/b", Source: "https://example.com/", Tag: "script"}, nil))
+	require.Nil(t, writer.Write(nil, nil))
+	_, err = os.Stat(file)
+	require.True(t, os.IsNotExist(err), "got sarif output before close")
+	require.Nil(t, writer.Close(), "could not close sarif writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read sarif output")
+
+	var document sarifDocument
+	require.Nil(t, jsoniter.Unmarshal(data, &document), "could not decode sarif output")
+	require.Equal(t, sarifVersion, document.Version, "could not get sarif version")
+	require.Len(t, document.Runs, 1, "could not get sarif run")
+
+	run := document.Runs[0]
+	require.Equal(t, []sarifRule{{ID: "https://example.com/"}}, run.Tool.Driver.Rules, "could not get deduplicated rules")
+	require.Len(t, run.Results, 2, "could not get sarif results")
+	require.Equal(t, "https://example.com/a", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI, "could not get result location")
+	require.Equal(t, map[string]string{"method": "GET", "tag": "a", "attribute": "href"}, run.Results[0].Properties, "could not get result properties")
+}
@@ -2,6 +2,14 @@ package output
 
 import (
 	"bytes"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/logrusorgru/aurora"
 )
 
 // formatScreen formats the output for showing on screen.
@@ -13,9 +21,15 @@ func (w *StandardWriter) formatScreen(output *Result) ([]byte, error) {
 	}
 	builder := &bytes.Buffer{}
 
+	if !output.Timestamp.IsZero() && w.verbose {
+		builder.WriteRune('[')
+		builder.WriteString(w.aurora.Faint(fmt.Sprint(w.formatTimestamp(output.Timestamp))).String())
+		builder.WriteRune(']')
+		builder.WriteRune(' ')
+	}
 	if w.verbose {
 		builder.WriteRune('[')
-		builder.WriteString(w.aurora.Blue(output.Tag).String())
+		builder.WriteString(w.colorizeTag(output))
 		builder.WriteRune(']')
 		builder.WriteRune(' ')
 	}
@@ -25,7 +39,76 @@ func (w *StandardWriter) formatScreen(output *Result) ([]byte, error) {
 		builder.WriteRune(']')
 		builder.WriteRune(' ')
 	}
-	builder.WriteString(output.URL)
+	if output.Error != "" {
+		builder.WriteString(w.aurora.Red(output.URL).String())
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.Red(output.Error).String())
+		builder.WriteRune(']')
+		return builder.Bytes(), nil
+	}
+	builder.WriteString(w.colorizeURL(output))
+
+	if len(output.Redirects) > 0 && w.verbose {
+		for _, redirect := range output.Redirects {
+			builder.WriteString(" -> ")
+			builder.WriteString(redirect)
+		}
+		if output.RedirectLoop {
+			builder.WriteString(" [")
+			builder.WriteString(w.aurora.Red("redirect loop").String())
+			builder.WriteRune(']')
+		}
+	}
+
+	if output.StatusCode != 0 && w.verbose {
+		builder.WriteRune(' ')
+		builder.WriteRune('[')
+		builder.WriteString(w.colorizeStatusCode(output.StatusCode))
+		builder.WriteRune(']')
+	}
+	if output.Latency != 0 && w.verbose {
+		builder.WriteRune(' ')
+		builder.WriteRune('[')
+		builder.WriteString(strconv.FormatInt(time.Duration(output.Latency).Milliseconds(), 10))
+		builder.WriteString("ms")
+		builder.WriteRune(']')
+	}
+	if output.Title != "" && w.verbose {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.Cyan(output.Title).String())
+		builder.WriteRune(']')
+	}
+	if len(output.Technologies) > 0 && w.verbose {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.Magenta(strings.Join(output.Technologies, ",")).String())
+		builder.WriteRune(']')
+	}
+	if output.Form != nil && w.verbose {
+		builder.WriteString(" [")
+		builder.WriteString(w.aurora.Red("form").String())
+		builder.WriteRune(' ')
+		builder.WriteString(output.Form.Method)
+		builder.WriteRune(' ')
+		builder.WriteString(output.Form.Action)
+		if len(output.Form.Inputs) > 0 {
+			builder.WriteRune(' ')
+			builder.WriteString(strings.Join(output.Form.Inputs, ","))
+		}
+		builder.WriteRune(']')
+	}
+	if len(output.ResponseHeaders) > 0 && w.verbose {
+		names := make([]string, 0, len(output.ResponseHeaders))
+		for name := range output.ResponseHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			builder.WriteString(" [")
+			builder.WriteString(w.aurora.Faint(name + ": ").String())
+			builder.WriteString(output.ResponseHeaders[name])
+			builder.WriteRune(']')
+		}
+	}
 
 	if output.Body != "" && w.verbose {
 		builder.WriteRune(' ')
@@ -35,3 +118,69 @@ func (w *StandardWriter) formatScreen(output *Result) ([]byte, error) {
 	}
 	return builder.Bytes(), nil
 }
+
+// tagColors is a list of colors for the tags of the source color scheme
+var tagColors = map[string]aurora.Color{
+	"a":      aurora.GreenFg,
+	"link":   aurora.GreenFg,
+	"header": aurora.MagentaFg,
+	"script": aurora.YellowFg,
+	"js":     aurora.YellowFg,
+	"form":   aurora.RedFg,
+}
+
+// tagColorPalette is the palette to pick colors for other tags from
+var tagColorPalette = []aurora.Color{
+	aurora.BlueFg,
+	aurora.CyanFg,
+	aurora.GreenFg | aurora.BrightFg,
+	aurora.MagentaFg | aurora.BrightFg,
+	aurora.YellowFg | aurora.BrightFg,
+	aurora.CyanFg | aurora.BrightFg,
+}
+
+// getTagColor returns a consistent color for a tag
+func getTagColor(tag string) aurora.Color {
+	if color, ok := tagColors[tag]; ok {
+		return color
+	}
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(tag))
+	return tagColorPalette[hash.Sum32()%uint32(len(tagColorPalette))]
+}
+
+// colorizeTag returns the tag of the result colored by the color scheme
+func (w *StandardWriter) colorizeTag(output *Result) string {
+	if w.colorScheme != ColorSchemeSource {
+		return w.aurora.Blue(output.Tag).String()
+	}
+	tag := output.Tag
+	if output.Attribute != "" {
+		tag = tag + ":" + output.Attribute
+	}
+	return w.aurora.Faint(tag).String()
+}
+
+// colorizeURL returns the URL of the result colored by the color scheme
+func (w *StandardWriter) colorizeURL(output *Result) string {
+	if w.colorScheme != ColorSchemeSource {
+		return output.URL
+	}
+	return w.aurora.Colorize(output.URL, getTagColor(output.Tag)).String()
+}
+
+// colorizeStatusCode returns the status code colored by its class
+func (w *StandardWriter) colorizeStatusCode(statusCode int) string {
+	value := strconv.Itoa(statusCode)
+	switch {
+	case statusCode >= 500:
+		return w.aurora.Red(value).String()
+	case statusCode >= 400:
+		return w.aurora.Yellow(value).String()
+	case statusCode >= 300:
+		return w.aurora.Cyan(value).String()
+	case statusCode >= 200:
+		return w.aurora.Green(value).String()
+	}
+	return value
+}
@@ -0,0 +1,171 @@
+package output
+
+import (
+	"database/sql"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// sqliteBatchSize is the number of results inserted per transaction
+const sqliteBatchSize = 1000
+
+// sqliteWriter is a writer which inserts the results into the results
+// table of a sqlite database in batched transactions.
+type sqliteWriter struct {
+	db     *sql.DB
+	mutex  *sync.Mutex
+	insert string
+
+	tx      *sql.Tx
+	stmt    *sql.Stmt
+	pending int
+}
+
+// newSQLWriter returns a sqlite writer for the database/sql driver,
+// creating the results table and its indexes if they don't exist.
+//
+// The table columns mirror the json names of the Result fields, nested
+// values like the headers are stored as json text.
+func newSQLWriter(driverName, dataSource string) (*sqliteWriter, error) {
+	if dataSource == "" {
+		return nil, errors.New("no sqlite database specified")
+	}
+	db, err := sql.Open(driverName, dataSource)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not open sqlite database")
+	}
+	for _, statement := range getSQLiteSchema() {
+		if _, err := db.Exec(statement); err != nil {
+			db.Close()
+			return nil, errors.Wrap(err, "could not create sqlite schema")
+		}
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(csvColumns)), ", ")
+	return &sqliteWriter{
+		db:     db,
+		mutex:  &sync.Mutex{},
+		insert: "INSERT INTO results (" + strings.Join(csvColumns, ", ") + ") VALUES (" + placeholders + ")",
+	}, nil
+}
+
+// getSQLiteSchema returns the statements creating the results table and
+// its indexes
+func getSQLiteSchema() []string {
+	resultType := reflect.TypeOf(Result{})
+	columns := make([]string, 0, resultType.NumField())
+	for i := 0; i < resultType.NumField(); i++ {
+		columns = append(columns, csvColumns[i]+" "+getSQLiteColumnType(resultType.Field(i).Type))
+	}
+	return []string{
+		"CREATE TABLE IF NOT EXISTS results (id INTEGER PRIMARY KEY AUTOINCREMENT, " + strings.Join(columns, ", ") + ")",
+		"CREATE INDEX IF NOT EXISTS results_endpoint_idx ON results (endpoint)",
+		"CREATE INDEX IF NOT EXISTS results_status_code_idx ON results (status_code)",
+	}
+}
+
+// getSQLiteColumnType returns the sqlite column type for a field type
+func getSQLiteColumnType(fieldType reflect.Type) string {
+	if fieldType == reflect.TypeOf(Duration(0)) {
+		return "REAL"
+	}
+	switch fieldType.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "INTEGER"
+	}
+	return "TEXT"
+}
+
+// getSQLiteValues returns the column values of the result
+func getSQLiteValues(output *Result) []interface{} {
+	value := reflect.ValueOf(*output)
+	values := make([]interface{}, 0, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Field(i)
+		if latency, ok := field.Interface().(Duration); ok {
+			values = append(values, latency.Milliseconds())
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Bool:
+			values = append(values, field.Bool())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			values = append(values, field.Int())
+		default:
+			values = append(values, formatCSVValue(field))
+		}
+	}
+	return values
+}
+
+// Write inserts the event into the results table, committing the
+// transaction once the batch size is reached.
+func (w *sqliteWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	values := getSQLiteValues(event)
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.tx == nil {
+		tx, err := w.db.Begin()
+		if err != nil {
+			return errors.Wrap(err, "could not begin sqlite transaction")
+		}
+		stmt, err := tx.Prepare(w.insert)
+		if err != nil {
+			_ = tx.Rollback()
+			return errors.Wrap(err, "could not prepare sqlite insert")
+		}
+		w.tx, w.stmt = tx, stmt
+	}
+	if _, err := w.stmt.Exec(values...); err != nil {
+		return errors.Wrap(err, "could not insert sqlite result")
+	}
+	w.pending++
+	if w.pending >= sqliteBatchSize {
+		return w.commit()
+	}
+	return nil
+}
+
+// commit commits the pending transaction.
+//
+// It must be called with the mutex held.
+func (w *sqliteWriter) commit() error {
+	if w.tx == nil {
+		return nil
+	}
+	_ = w.stmt.Close()
+	err := w.tx.Commit()
+	w.tx, w.stmt, w.pending = nil, nil, 0
+	if err != nil {
+		return errors.Wrap(err, "could not commit sqlite transaction")
+	}
+	return nil
+}
+
+// Flush commits the pending transaction
+func (w *sqliteWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.commit()
+}
+
+// Close commits the pending transaction and closes the database
+func (w *sqliteWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	err := w.commit()
+	if closeErr := w.db.Close(); err == nil && closeErr != nil {
+		err = errors.Wrap(closeErr, "could not close sqlite database")
+	}
+	return err
+}
@@ -0,0 +1,12 @@
+//go:build !sqlite
+// +build !sqlite
+
+package output
+
+import "github.com/pkg/errors"
+
+// NewSQLiteWriter returns an error as the sqlite writer is only available
+// in the builds with the sqlite build tag.
+func NewSQLiteWriter(file string) (Writer, error) {
+	return nil, errors.New("sqlite output is not supported by this build, rebuild with -tags sqlite")
+}
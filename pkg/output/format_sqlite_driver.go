@@ -0,0 +1,19 @@
+//go:build sqlite
+// +build sqlite
+
+package output
+
+import (
+	// registers the pure go sqlite driver
+	_ "modernc.org/sqlite"
+)
+
+// sqliteDriverName is the database/sql driver name of the linked driver
+const sqliteDriverName = "sqlite"
+
+// NewSQLiteWriter returns a writer which inserts the results into the
+// results table of the sqlite database file, creating the table and its
+// indexes if they don't exist.
+func NewSQLiteWriter(file string) (Writer, error) {
+	return newSQLWriter(sqliteDriverName, file)
+}
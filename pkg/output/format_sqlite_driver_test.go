@@ -0,0 +1,32 @@
+//go:build sqlite
+// +build sqlite
+
+package output
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteWriterDriver(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "results.db")
+	writer, err := NewSQLiteWriter(file)
+	require.Nil(t, err, "could not create sqlite writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/", StatusCode: 200, ResponseHeaders: map[string]string{"Server": "nginx"}}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/missing", StatusCode: 404}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close sqlite writer")
+
+	db, err := sql.Open(sqliteDriverName, file)
+	require.Nil(t, err, "could not open sqlite database")
+	defer db.Close()
+
+	var count int
+	require.Nil(t, db.QueryRow("SELECT COUNT(*) FROM results").Scan(&count), "could not count results")
+	require.Equal(t, 2, count, "could not insert results")
+	var headers string
+	require.Nil(t, db.QueryRow("SELECT response_headers FROM results WHERE status_code = 200").Scan(&headers), "could not query result")
+	require.Equal(t, `{"Server":"nginx"}`, headers, "could not insert headers as json")
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver is a database/sql driver recording the executed statements
+type recordingDriver struct {
+	mutex      sync.Mutex
+	statements []string
+	args       [][]driver.Value
+	commits    int
+}
+
+func (d *recordingDriver) Open(name string) (driver.Conn, error) {
+	return &recordingConn{driver: d}, nil
+}
+
+type recordingConn struct {
+	driver *recordingDriver
+}
+
+func (c *recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordingStmt{driver: c.driver, query: query}, nil
+}
+
+func (c *recordingConn) Close() error { return nil }
+
+func (c *recordingConn) Begin() (driver.Tx, error) { return &recordingTx{driver: c.driver}, nil }
+
+type recordingStmt struct {
+	driver *recordingDriver
+	query  string
+}
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.driver.mutex.Lock()
+	defer s.driver.mutex.Unlock()
+
+	s.driver.statements = append(s.driver.statements, s.query)
+	s.driver.args = append(s.driver.args, args)
+	return driver.RowsAffected(1), nil
+}
+
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+type recordingTx struct {
+	driver *recordingDriver
+}
+
+func (t *recordingTx) Commit() error {
+	t.driver.mutex.Lock()
+	t.driver.commits++
+	t.driver.mutex.Unlock()
+	return nil
+}
+
+func (t *recordingTx) Rollback() error { return nil }
+
+var testSQLDriver = &recordingDriver{}
+
+func init() {
+	sql.Register("katana-recording", testSQLDriver)
+}
+
+func TestSQLiteWriter(t *testing.T) {
+	writer, err := newSQLWriter("katana-recording", "results.db")
+	require.Nil(t, err, "could not create sqlite writer")
+
+	require.Len(t, testSQLDriver.statements, 3, "could not create sqlite schema")
+	require.True(t, strings.HasPrefix(testSQLDriver.statements[0], "CREATE TABLE IF NOT EXISTS results (id INTEGER PRIMARY KEY AUTOINCREMENT, timestamp TEXT, method TEXT"), "could not get table schema")
+	require.Contains(t, testSQLDriver.statements[0], "status_code INTEGER", "could not get integer column")
+	require.Contains(t, testSQLDriver.statements[1], "ON results (endpoint)", "could not create endpoint index")
+	require.Contains(t, testSQLDriver.statements[2], "ON results (status_code)", "could not create status code index")
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/", StatusCode: 200, ResponseHeaders: map[string]string{"Server": "nginx"}}, nil), "could not write result")
+	require.Nil(t, writer.Write(nil, nil), "could not write empty result")
+	require.Equal(t, 0, testSQLDriver.commits, "committed before batch size")
+	require.Nil(t, writer.Close(), "could not close sqlite writer")
+	require.Equal(t, 1, testSQLDriver.commits, "could not commit on close")
+
+	require.Len(t, testSQLDriver.args, 4, "could not insert result")
+	require.True(t, strings.HasPrefix(testSQLDriver.statements[3], "INSERT INTO results (timestamp, method, body, endpoint"), "could not get insert statement")
+	values := make(map[string]driver.Value)
+	for i, column := range csvColumns {
+		values[column] = testSQLDriver.args[3][i]
+	}
+	require.Equal(t, "https://example.com/", values["endpoint"], "could not insert url")
+	require.Equal(t, int64(200), values["status_code"], "could not insert status code")
+	require.Equal(t, `{"Server":"nginx"}`, values["response_headers"], "could not insert headers as json")
+}
@@ -0,0 +1,97 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+)
+
+// tabularFlushRows is the number of buffered rows after which the tabular
+// output is aligned and written, so that it is shown while crawling.
+const tabularFlushRows = 64
+
+// tabularSeparator is the separator between the aligned columns
+const tabularSeparator = "  "
+
+// getTabularRow returns the URL, status code and content type columns of
+// the result for the tabular screen output
+func (w *StandardWriter) getTabularRow(output *Result) []string {
+	status := "-"
+	if output.StatusCode != 0 {
+		status = w.colorizeStatusCode(output.StatusCode)
+	}
+	contentType := "-"
+	if output.ContentType != "" {
+		contentType = output.ContentType
+	}
+	return []string{w.colorizeURL(output), status, contentType}
+}
+
+// writeTabularRow buffers the row of the result, writing the buffered
+// rows once the flush threshold is reached.
+func (w *StandardWriter) writeTabularRow(output *Result) error {
+	row := w.getTabularRow(output)
+
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	w.tabularRows = append(w.tabularRows, row)
+	if len(w.tabularRows) < tabularFlushRows {
+		return nil
+	}
+	return w.flushTabularRows()
+}
+
+// flushTabularRows aligns and writes the buffered rows to file and/or
+// screen.
+//
+// The column widths are computed without the color escape codes, so the
+// alignment is the same on screen and in the decolorized output file.
+// It must be called with the output mutex held.
+func (w *StandardWriter) flushTabularRows() error {
+	if len(w.tabularRows) == 0 {
+		return nil
+	}
+	var widths []int
+	for _, row := range w.tabularRows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if width := getVisibleWidth(cell); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+	rows := w.tabularRows
+	w.tabularRows = nil
+
+	builder := &bytes.Buffer{}
+	for _, row := range rows {
+		builder.Reset()
+		for i, cell := range row {
+			builder.WriteString(cell)
+			// the last column is not padded to avoid trailing spaces
+			if i < len(row)-1 {
+				builder.WriteString(strings.Repeat(" ", widths[i]-getVisibleWidth(cell)))
+				builder.WriteString(tabularSeparator)
+			}
+		}
+		data := builder.Bytes()
+		w.writeScreen(data)
+		if w.outputFile != nil {
+			if !w.keepFileColor {
+				data = decolorizerRegex.ReplaceAll(data, []byte(""))
+			}
+			if err := w.outputFile.Write(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getVisibleWidth returns the width of the text without color escape codes
+func getVisibleWidth(text string) int {
+	return utf8.RuneCountInString(decolorizerRegex.ReplaceAllString(text, ""))
+}
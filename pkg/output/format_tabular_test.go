@@ -0,0 +1,39 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTabularOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := NewWithOptions(&Options{Tabular: true, Colors: true, OutputFile: file, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/", StatusCode: 200, ContentType: "text/html"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/login", StatusCode: 302}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a.js"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "https://example.com/       200  text/html\n"+
+		"https://example.com/login  302  -\n"+
+		"https://example.com/a.js   -    -\n", string(data), "could not align tabular output")
+}
+
+func TestTabularRowsColor(t *testing.T) {
+	writer, err := NewWithOptions(&Options{Tabular: true, Colors: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	w := writer.(*StandardWriter)
+	row := w.getTabularRow(&Result{URL: "https://example.com/", StatusCode: 404})
+	if !noColorEnabled() {
+		require.True(t, decolorizerRegex.MatchString(row[1]), "could not colorize status code")
+	}
+	require.Equal(t, 3, getVisibleWidth(row[1]), "could not get visible width without escape codes")
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/publicsuffix"
+)
+
+// outputTemplate is a parsed output template with {field} placeholders
+type outputTemplate struct {
+	// literals contains the text around the fields, it always
+	// has one more item than the fields.
+	literals []string
+	fields   []string
+}
+
+// templateEscapes replaces the escape sequences supported in templates
+var templateEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n", `\\`, `\`)
+
+// parseOutputTemplate parses an output template like {url}\t{status_code}
+// validating the field names in the placeholders.
+func parseOutputTemplate(template string) (*outputTemplate, error) {
+	parsed := &outputTemplate{}
+
+	remaining := templateEscapes.Replace(template)
+	literal := &strings.Builder{}
+	for {
+		start := strings.IndexByte(remaining, '{')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(remaining[start:], '}')
+		if end == -1 {
+			break
+		}
+		field := remaining[start+1 : start+end]
+		if field == "" || strings.ContainsAny(field, "{ ,") {
+			literal.WriteString(remaining[:start+1])
+			remaining = remaining[start+1:]
+			continue
+		}
+		if err := validateFieldNames(field); err != nil {
+			return nil, err
+		}
+		literal.WriteString(remaining[:start])
+		parsed.literals = append(parsed.literals, literal.String())
+		parsed.fields = append(parsed.fields, field)
+		literal.Reset()
+		remaining = remaining[start+end+1:]
+	}
+	literal.WriteString(remaining)
+	parsed.literals = append(parsed.literals, literal.String())
+
+	if len(parsed.fields) == 0 {
+		return nil, errors.Errorf("no fields in output template %s", template)
+	}
+	return parsed, nil
+}
+
+// formatTemplate formats the output using the output template
+func (w *StandardWriter) formatTemplate(output *Result) ([]byte, error) {
+	parsed, err := url.Parse(output.URL)
+	if err != nil {
+		return nil, err
+	}
+	hostname := parsed.Hostname()
+	etld, _ := publicsuffix.EffectiveTLDPlusOne(hostname)
+	rootURL := fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+
+	builder := &bytes.Buffer{}
+	for i, field := range w.outputTemplate.fields {
+		builder.WriteString(w.outputTemplate.literals[i])
+		builder.WriteString(getValueForField(output, parsed, hostname, etld, rootURL, field))
+	}
+	builder.WriteString(w.outputTemplate.literals[len(w.outputTemplate.literals)-1])
+	return builder.Bytes(), nil
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTemplate(t *testing.T) {
+	template, err := parseOutputTemplate(`{url}\t{status_code} {"type": "{content_type}"}`)
+	require.Nil(t, err, "could not parse output template")
+	require.Equal(t, []string{"url", "status_code", "content_type"}, template.fields, "could not get template fields")
+
+	w := &StandardWriter{outputTemplate: template}
+	data, err := w.formatTemplate(&Result{URL: "https://example.com/a", StatusCode: 200, ContentType: "text/html"})
+	require.Nil(t, err, "could not format template")
+	require.Equal(t, "https://example.com/a\t200 {\"type\": \"text/html\"}", string(data), "could not get formatted template")
+
+	data, err = w.formatTemplate(&Result{URL: "https://example.com/b"})
+	require.Nil(t, err, "could not format template")
+	require.Equal(t, "https://example.com/b\t {\"type\": \"\"}", string(data), "could not get formatted template without values")
+}
+
+func TestParseOutputTemplateErrors(t *testing.T) {
+	_, err := parseOutputTemplate("{url} {invalid}")
+	require.Error(t, err, "got no error for invalid template field")
+
+	_, err = parseOutputTemplate("no fields {}")
+	require.Error(t, err, "got no error for template without fields")
+
+	_, err = NewWithOptions(&Options{OutputTemplate: "{url.path}"})
+	require.Error(t, err, "got no error for invalid template field path")
+}
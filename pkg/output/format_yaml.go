@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlDocumentSeparator is the separator written before every YAML document
+const yamlDocumentSeparator = "---\n"
+
+// formatYAML formats the output as a YAML document.
+//
+// The result is converted through its JSON representation so that the
+// YAML output contains the same fields, names and ordering as JSON.
+func (w *StandardWriter) formatYAML(output *Result) ([]byte, error) {
+	data, err := w.marshalJSONResult(output)
+	if err != nil {
+		return nil, err
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetYAMLStyle(&node)
+
+	yamlData, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, err
+	}
+	builder := &bytes.Buffer{}
+	builder.WriteString(yamlDocumentSeparator)
+	builder.Write(bytes.TrimSuffix(yamlData, []byte("\n")))
+	return builder.Bytes(), nil
+}
+
+// resetYAMLStyle resets the JSON flow and quoting styles of the nodes
+// to use the default block YAML style.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,23 @@
+package output
+
+import "github.com/pkg/errors"
+
+// isJSResult returns true if the result was extracted from javascript,
+// either from a javascript file with the js tag, in which case the source
+// is the script URL, or from an inline script of a page.
+func isJSResult(event *Result) bool {
+	return event.Tag == "js" || (event.Tag == "script" && event.Attribute == "text")
+}
+
+// writeJSResult writes the javascript result as a json line to the
+// javascript output file
+func (w *StandardWriter) writeJSResult(event *Result) error {
+	data, err := w.marshalJSONResult(event)
+	if err != nil {
+		return errors.Wrap(err, "could not format javascript output")
+	}
+	if err := w.jsOutput.Write(data); err != nil {
+		return errors.Wrap(err, "could not write to javascript output")
+	}
+	return nil
+}
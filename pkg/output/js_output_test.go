@@ -0,0 +1,38 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSOutput(t *testing.T) {
+	dir := t.TempDir()
+	jsFile := filepath.Join(dir, "js.jsonl")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: filepath.Join(dir, "output.txt"), JSOutput: jsFile, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	for _, result := range []*Result{
+		{URL: "https://example.com/app.js", Source: "https://example.com/", Tag: "script", Attribute: "src"},
+		{URL: "https://example.com/api/users", Source: "https://example.com/app.js", Tag: "js", Attribute: "regex"},
+		{URL: "https://example.com/api/inline", Source: "https://example.com/", Tag: "script", Attribute: "text"},
+		{URL: "https://example.com/about", Source: "https://example.com/", Tag: "a", Attribute: "href"},
+	} {
+		require.Nil(t, standardWriter.Write(result, nil), "could not write result")
+	}
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	data, err := os.ReadFile(jsFile)
+	require.Nil(t, err, "could not read javascript output")
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2, "could not write only javascript results")
+	require.Contains(t, lines[0], `"endpoint":"https://example.com/api/users","source":"https://example.com/app.js"`, "could not write script source")
+	require.Contains(t, lines[1], `"endpoint":"https://example.com/api/inline"`, "could not write inline script result")
+
+	data, err = os.ReadFile(filepath.Join(dir, "output.txt"))
+	require.Nil(t, err, "could not read output")
+	require.Len(t, strings.Fields(string(data)), 4, "could not write all results to output")
+}
@@ -0,0 +1,108 @@
+package output
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"golang.org/x/net/publicsuffix"
+)
+
+// jsonFieldKeys maps the field names which differ from the json names of
+// the result fields to the json names
+var jsonFieldKeys = map[string]string{
+	"url": "endpoint",
+}
+
+// jsonField is a field selected for the json output
+type jsonField struct {
+	// key is the json key of the field
+	key string
+	// index is the index of the result struct field, it is nil for the
+	// fields derived from the result like path or the dotted field paths.
+	index []int
+}
+
+// getJSONFields returns the json fields for the comma separated field names
+func getJSONFields(fields string) []jsonField {
+	resultType := reflect.TypeOf(Result{})
+
+	var jsonFields []jsonField
+	for _, name := range strings.Split(fields, ",") {
+		if key, ok := jsonFieldKeys[name]; ok {
+			name = key
+		}
+		field := jsonField{key: name}
+		if structField, ok := getStructFieldByJSONName(resultType, name); ok && !isFieldPath(name) {
+			field.index = structField.Index
+		}
+		jsonFields = append(jsonFields, field)
+	}
+	return jsonFields
+}
+
+// validateJSONFields validates that the keys of the derived fields don't
+// collide with the field aliases
+func validateJSONFields(fields []jsonField, aliases map[string]string) error {
+	for _, field := range fields {
+		if field.index != nil {
+			continue
+		}
+		for name, alias := range aliases {
+			if alias == field.key {
+				return errors.Errorf("alias %s of field %s collides with the selected field %s", alias, name, field.key)
+			}
+		}
+	}
+	return nil
+}
+
+// withJSONFields returns the schema with the string properties of the
+// derived fields selected for the json output
+func (s *jsonSchema) withJSONFields(fields []jsonField) *jsonSchema {
+	schema := *s
+	schema.Properties = make(map[string]*jsonSchema, len(s.Properties)+len(fields))
+	for name, property := range s.Properties {
+		schema.Properties[name] = property
+	}
+	for _, field := range fields {
+		if field.index == nil {
+			schema.Properties[field.key] = &jsonSchema{Type: "string"}
+		}
+	}
+	return &schema
+}
+
+// marshalJSONFields returns the compact json object of the non-empty
+// selected fields of the result, with the keys in sorted order.
+func (w *StandardWriter) marshalJSONFields(output *Result) ([]byte, error) {
+	value := reflect.ValueOf(output).Elem()
+	projected := make(map[string]interface{}, len(w.jsonFields))
+
+	var parsed *url.URL
+	var hostname, etld, rootURL string
+	for _, field := range w.jsonFields {
+		if field.index != nil {
+			if fieldValue := value.FieldByIndex(field.index); !fieldValue.IsZero() {
+				projected[field.key] = fieldValue.Interface()
+			}
+			continue
+		}
+		if parsed == nil {
+			var err error
+			if parsed, err = url.Parse(output.URL); err != nil {
+				parsed = &url.URL{}
+			}
+			hostname = parsed.Hostname()
+			etld, _ = publicsuffix.EffectiveTLDPlusOne(hostname)
+			rootURL = fmt.Sprintf("%s://%s", parsed.Scheme, parsed.Host)
+		}
+		if fieldValue := getValueForField(output, parsed, hostname, etld, rootURL, field.key); fieldValue != "" {
+			projected[field.key] = fieldValue
+		}
+	}
+	return jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(projected)
+}
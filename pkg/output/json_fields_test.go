@@ -0,0 +1,36 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONFields(t *testing.T) {
+	result := &Result{
+		Timestamp:       time.Now(),
+		URL:             "https://example.com/app/login.php?next=1",
+		StatusCode:      200,
+		ContentType:     "text/html",
+		ResponseHeaders: map[string]string{"Server": "nginx"},
+	}
+
+	writer, err := NewWithOptions(&Options{JSONL: true, Fields: "url,status_code,path,title,response_headers.Server"})
+	require.Nil(t, err, "could not create writer")
+	data, err := writer.(*StandardWriter).formatJSONL(result)
+	require.Nil(t, err, "could not format jsonl")
+	require.Equal(t, `{"endpoint":"https://example.com/app/login.php?next=1","path":"/app/login.php","response_headers.Server":"nginx","status_code":200}`, string(data), "could not project json fields")
+	require.Nil(t, writer.(*StandardWriter).outputSchema.validateJSON(data), "could not validate projected json")
+
+	writer, err = NewWithOptions(&Options{JSON: true, PrettyJSON: true, SelectFields: []Field{FieldURL, FieldStatusCode}, FieldAliases: map[string]string{"endpoint": "url"}})
+	require.Nil(t, err, "could not create writer")
+	data, err = writer.(*StandardWriter).formatJSON(result)
+	require.Nil(t, err, "could not format json")
+	require.Equal(t, "{\n  \"url\": \"https://example.com/app/login.php?next=1\",\n  \"status_code\": 200\n}", string(data), "could not project aliased json fields")
+
+	_, err = NewWithOptions(&Options{JSONL: true, Fields: "url,path", FieldAliases: map[string]string{"endpoint": "path"}})
+	require.NotNil(t, err, "could not reject alias colliding with field")
+	_, err = NewWithOptions(&Options{JSONL: true, Fields: "url,invalid"})
+	require.NotNil(t, err, "could not reject invalid field")
+}
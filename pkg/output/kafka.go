@@ -0,0 +1,45 @@
+package output
+
+import "time"
+
+const (
+	// DefaultKafkaClientID is the default client id sent to the brokers
+	DefaultKafkaClientID = "katana"
+	// DefaultKafkaQueueSize is the default number of results buffered by
+	// the kafka producer
+	DefaultKafkaQueueSize = 1000
+	// DefaultKafkaMaxRetries is the default number of retries of the
+	// messages failed with a retryable error
+	DefaultKafkaMaxRetries = 3
+	// DefaultKafkaTimeout is the default timeout of the delivery of the
+	// messages
+	DefaultKafkaTimeout = 10 * time.Second
+)
+
+// KafkaOptions contains the options of the kafka writer
+type KafkaOptions struct {
+	// Brokers are the host:port addresses of the bootstrap brokers the
+	// metadata of the topic is requested from.
+	Brokers []string
+	// Topic is the topic the results are produced to
+	Topic string
+	// ClientID is the client id sent to the brokers, the default is used
+	// if it is empty.
+	ClientID string
+	// RequireAck specifies to wait for the acknowledgement of all the
+	// in-sync replicas and to fail the writes once a message could not be
+	// produced. Otherwise only the partition leader acknowledges the
+	// messages and the failures are logged.
+	RequireAck bool
+	// QueueSize is the number of results buffered in memory by the
+	// producer, the writes block while the buffer is full. The default is
+	// used if it is not positive.
+	QueueSize int
+	// MaxRetries is the number of times the messages failed with a
+	// retryable error are retried, the default is used if it is not
+	// positive.
+	MaxRetries int
+	// Timeout is the timeout of the delivery of a message including its
+	// retries, the default is used if it is not positive.
+	Timeout time.Duration
+}
@@ -0,0 +1,12 @@
+//go:build !kafka
+// +build !kafka
+
+package output
+
+import "github.com/pkg/errors"
+
+// NewKafkaWriter returns an error as the kafka writer is only available
+// in the builds with the kafka build tag.
+func NewKafkaWriter(options KafkaOptions) (Writer, error) {
+	return nil, errors.New("kafka output is not supported by this build, rebuild with -tags kafka")
+}
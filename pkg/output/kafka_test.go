@@ -0,0 +1,223 @@
+//go:build kafka
+// +build kafka
+
+package output
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// fakeKafkaBroker is a single broker answering the requests of a producer
+// of the results topic with two partitions
+type fakeKafkaBroker struct {
+	listener  net.Listener
+	errorCode int16
+
+	mutex    *sync.Mutex
+	acks     []int16
+	messages map[int32][]string
+	keys     map[string]int32
+}
+
+func newFakeKafkaBroker(t *testing.T) *fakeKafkaBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.Nil(t, err, "could not listen")
+	broker := &fakeKafkaBroker{listener: listener, mutex: &sync.Mutex{}, messages: make(map[int32][]string), keys: make(map[string]int32)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go broker.serve(t, conn)
+		}
+	}()
+	t.Cleanup(func() { listener.Close() })
+	return broker
+}
+
+func (b *fakeKafkaBroker) serve(t *testing.T, conn net.Conn) {
+	defer conn.Close()
+	for {
+		size := make([]byte, 4)
+		if _, err := io.ReadFull(conn, size); err != nil {
+			return
+		}
+		data := make([]byte, binary.BigEndian.Uint32(size))
+		if _, err := io.ReadFull(conn, data); err != nil {
+			return
+		}
+		request := kmsg.RequestForKey(int16(binary.BigEndian.Uint16(data)))
+		if request == nil {
+			return
+		}
+		request.SetVersion(int16(binary.BigEndian.Uint16(data[2:])))
+		correlationID := data[4:8]
+		// skip the client id and the tagged fields of the header
+		body := data[10+int(int16(binary.BigEndian.Uint16(data[8:]))):]
+		if request.IsFlexible() {
+			body = body[1:]
+		}
+		require.Nil(t, request.ReadFrom(body), "could not read request")
+
+		response := b.handle(t, request)
+		if response == nil {
+			return
+		}
+		response.SetVersion(request.GetVersion())
+		header := append([]byte{0, 0, 0, 0}, correlationID...)
+		if response.IsFlexible() && request.Key() != kmsg.ApiVersions.Int16() {
+			header = append(header, 0)
+		}
+		message := response.AppendTo(header)
+		binary.BigEndian.PutUint32(message, uint32(len(message)-4))
+		if _, err := conn.Write(message); err != nil {
+			return
+		}
+	}
+}
+
+func (b *fakeKafkaBroker) handle(t *testing.T, request kmsg.Request) kmsg.Response {
+	switch request := request.(type) {
+	case *kmsg.ApiVersionsRequest:
+		response := kmsg.NewPtrApiVersionsResponse()
+		for _, key := range []kmsg.Key{kmsg.ApiVersions, kmsg.Metadata, kmsg.Produce, kmsg.InitProducerID} {
+			version := kmsg.NewApiVersionsResponseApiKey()
+			version.ApiKey = key.Int16()
+			version.MaxVersion = kmsg.RequestForKey(key.Int16()).MaxVersion()
+			response.ApiKeys = append(response.ApiKeys, version)
+		}
+		return response
+	case *kmsg.MetadataRequest:
+		host, port, _ := net.SplitHostPort(b.listener.Addr().String())
+		portNumber, _ := strconv.Atoi(port)
+		response := kmsg.NewPtrMetadataResponse()
+		broker := kmsg.NewMetadataResponseBroker()
+		broker.NodeID, broker.Host, broker.Port = 1, host, int32(portNumber)
+		response.Brokers = append(response.Brokers, broker)
+		response.ControllerID = 1
+		names := []string{"results"}
+		if request.Topics != nil {
+			names = nil
+			for _, topic := range request.Topics {
+				names = append(names, *topic.Topic)
+			}
+		}
+		for _, name := range names {
+			topic := kmsg.NewMetadataResponseTopic()
+			topic.Topic = kmsg.StringPtr(name)
+			if name != "results" {
+				topic.ErrorCode = 3 // unknown topic or partition
+			}
+			for partition := int32(0); partition < 2 && name == "results"; partition++ {
+				metadata := kmsg.NewMetadataResponseTopicPartition()
+				metadata.Partition, metadata.Leader = partition, 1
+				metadata.Replicas, metadata.ISR = []int32{1}, []int32{1}
+				topic.Partitions = append(topic.Partitions, metadata)
+			}
+			response.Topics = append(response.Topics, topic)
+		}
+		return response
+	case *kmsg.InitProducerIDRequest:
+		response := kmsg.NewPtrInitProducerIDResponse()
+		response.ProducerID = 1
+		return response
+	case *kmsg.ProduceRequest:
+		response := kmsg.NewPtrProduceResponse()
+		for _, topic := range request.Topics {
+			topicResponse := kmsg.NewProduceResponseTopic()
+			topicResponse.Topic = topic.Topic
+			for _, partition := range topic.Partitions {
+				b.readRecordBatch(t, partition.Partition, partition.Records)
+				partitionResponse := kmsg.NewProduceResponseTopicPartition()
+				partitionResponse.Partition = partition.Partition
+				partitionResponse.ErrorCode = b.errorCode
+				topicResponse.Partitions = append(topicResponse.Partitions, partitionResponse)
+			}
+			response.Topics = append(response.Topics, topicResponse)
+		}
+		b.mutex.Lock()
+		b.acks = append(b.acks, request.Acks)
+		b.mutex.Unlock()
+		return response
+	}
+	return nil
+}
+
+func (b *fakeKafkaBroker) readRecordBatch(t *testing.T, partition int32, data []byte) {
+	batch := kmsg.NewRecordBatch()
+	require.Nil(t, batch.ReadFrom(data), "could not read record batch")
+	records := batch.Records
+	switch batch.Attributes & 0x07 {
+	case 0:
+	case 2:
+		decoded, err := s2.Decode(nil, records)
+		require.Nil(t, err, "could not decompress record batch")
+		records = decoded
+	default:
+		require.Fail(t, "unexpected record batch compression")
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for i := int32(0); i < batch.NumRecords; i++ {
+		length, read := binary.Varint(records)
+		record := kmsg.NewRecord()
+		require.Nil(t, record.ReadFrom(records[:read+int(length)]), "could not read record")
+		records = records[read+int(length):]
+		b.messages[partition] = append(b.messages[partition], string(record.Value))
+		b.keys[string(record.Key)] = partition
+	}
+}
+
+func TestKafkaWriter(t *testing.T) {
+	broker := newFakeKafkaBroker(t)
+
+	writer, err := NewKafkaWriter(KafkaOptions{Brokers: []string{broker.listener.Addr().String()}, Topic: "results", RequireAck: true})
+	require.Nil(t, err, "could not create kafka writer")
+	for i := 0; i < 10; i++ {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/" + strconv.Itoa(i)}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Flush(), "could not flush kafka writer")
+	require.Nil(t, writer.Close(), "could not close kafka writer")
+	require.Nil(t, writer.Close(), "could not close kafka writer twice")
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+	var count int
+	for _, messages := range broker.messages {
+		count += len(messages)
+	}
+	require.Equal(t, 10, count, "could not produce all the results")
+	require.Len(t, broker.messages, 2, "could not partition the results")
+	require.Contains(t, broker.acks, int16(-1), "could not require all acks")
+	partitioner := kgo.StickyKeyPartitioner(nil).ForTopic("results")
+	for key, partition := range broker.keys {
+		require.Equal(t, int32(partitioner.Partition(&kgo.Record{Key: []byte(key)}, 2)), partition, "could not partition by key")
+	}
+}
+
+func TestKafkaWriterRequireAck(t *testing.T) {
+	broker := newFakeKafkaBroker(t)
+	broker.errorCode = 87 // invalid record
+
+	writer, err := NewKafkaWriter(KafkaOptions{Brokers: []string{broker.listener.Addr().String()}, Topic: "results", RequireAck: true})
+	require.Nil(t, err, "could not create kafka writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.True(t, IsFatalError(writer.Flush()), "could not fail on produce error")
+	require.NotNil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not fail write after produce error")
+	require.NotNil(t, writer.Close(), "could not fail close after produce error")
+
+	_, err = NewKafkaWriter(KafkaOptions{Brokers: []string{broker.listener.Addr().String()}, Topic: "missing"})
+	require.NotNil(t, err, "could not fail on missing topic")
+}
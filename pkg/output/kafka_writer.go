@@ -0,0 +1,195 @@
+//go:build kafka
+// +build kafka
+
+package output
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// kafkaWriter is a writer which produces the results as messages to a
+// kafka topic with the asynchronous producer of the kafka client.
+type kafkaWriter struct {
+	options KafkaOptions
+	client  *kgo.Client
+
+	closed bool
+	mutex  *sync.RWMutex
+
+	// errMutex protects the first produce error when acknowledgements are
+	// required, which is a fatal error failing the next write, flush or
+	// close
+	errMutex *sync.Mutex
+	err      error
+}
+
+// NewKafkaWriter returns a writer which produces the results as JSON
+// messages to a kafka topic, with the URL as the message key so that the
+// results of a URL are in the same partition.
+//
+// The results are produced asynchronously from a bounded in-memory buffer
+// and the buffer is flushed on Flush and Close.
+func NewKafkaWriter(options KafkaOptions) (Writer, error) {
+	if len(options.Brokers) == 0 {
+		return nil, errors.New("no kafka brokers specified")
+	}
+	if options.Topic == "" {
+		return nil, errors.New("no kafka topic specified")
+	}
+	if options.ClientID == "" {
+		options.ClientID = DefaultKafkaClientID
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultKafkaQueueSize
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = DefaultKafkaMaxRetries
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultKafkaTimeout
+	}
+
+	clientOptions := []kgo.Opt{
+		kgo.SeedBrokers(options.Brokers...),
+		kgo.ClientID(options.ClientID),
+		kgo.DefaultProduceTopic(options.Topic),
+		kgo.MaxBufferedRecords(options.QueueSize),
+		kgo.RecordRetries(options.MaxRetries),
+		kgo.RecordDeliveryTimeout(options.Timeout),
+		kgo.DialTimeout(options.Timeout),
+	}
+	if options.RequireAck {
+		clientOptions = append(clientOptions, kgo.RequiredAcks(kgo.AllISRAcks()))
+	} else {
+		// the idempotent writes require the acknowledgement of all the
+		// in-sync replicas
+		clientOptions = append(clientOptions, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	}
+	client, err := kgo.NewClient(clientOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create kafka client")
+	}
+	if err := checkKafkaTopic(client, options); err != nil {
+		client.Close()
+		return nil, errors.Wrap(err, "could not get kafka topic metadata")
+	}
+	return &kafkaWriter{
+		options:  options,
+		client:   client,
+		mutex:    &sync.RWMutex{},
+		errMutex: &sync.Mutex{},
+	}, nil
+}
+
+// checkKafkaTopic returns an error if the topic does not exist
+func checkKafkaTopic(client *kgo.Client, options KafkaOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+
+	request := kmsg.NewPtrMetadataRequest()
+	topic := kmsg.NewMetadataRequestTopic()
+	topic.Topic = kmsg.StringPtr(options.Topic)
+	request.Topics = append(request.Topics, topic)
+	response, err := request.RequestWith(ctx, client)
+	if err != nil {
+		return err
+	}
+	if len(response.Topics) != 1 {
+		return errors.Errorf("no metadata returned for topic %s", options.Topic)
+	}
+	return kerr.ErrorForCode(response.Topics[0].ErrorCode)
+}
+
+// Write produces the event as a message for the topic, blocking while
+// the buffer of the producer is full
+func (w *kafkaWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	if err := w.getError(); err != nil {
+		return err
+	}
+	value, err := jsoniter.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal kafka message")
+	}
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return errors.New("kafka writer is closed")
+	}
+	w.client.Produce(context.Background(), &kgo.Record{Key: []byte(event.URL), Value: value, Timestamp: time.Now()}, w.onProduced)
+	return nil
+}
+
+// onProduced logs the failure of a message, or keeps it to fail the
+// writes if acknowledgements are required
+func (w *kafkaWriter) onProduced(record *kgo.Record, err error) {
+	if err == nil {
+		return
+	}
+	if !w.options.RequireAck {
+		gologger.Warning().Msgf("Could not produce result %s to kafka: %s\n", record.Key, err)
+		return
+	}
+	w.errMutex.Lock()
+	defer w.errMutex.Unlock()
+
+	if w.err == nil {
+		w.err = &FatalError{Err: errors.Wrapf(err, "could not produce result %s to kafka", record.Key)}
+	}
+}
+
+// getError returns the first produce error
+func (w *kafkaWriter) getError() error {
+	w.errMutex.Lock()
+	defer w.errMutex.Unlock()
+
+	return w.err
+}
+
+// Flush waits until the buffered messages are produced, returning the
+// first produce error if acknowledgements are required
+func (w *kafkaWriter) Flush() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return w.getError()
+	}
+	if err := w.client.Flush(context.Background()); err != nil {
+		return errors.Wrap(err, "could not flush kafka producer")
+	}
+	return w.getError()
+}
+
+// Close produces the buffered messages and closes the client, returning
+// the first produce error if acknowledgements are required
+func (w *kafkaWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return w.getError()
+	}
+	w.closed = true
+
+	err := w.client.Flush(context.Background())
+	w.client.Close()
+	if err != nil {
+		return errors.Wrap(err, "could not flush kafka producer")
+	}
+	return w.getError()
+}
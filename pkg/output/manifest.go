@@ -0,0 +1,196 @@
+package output
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// manifestFile is the name of the manifest of the output artifacts
+const manifestFile = "manifest.json"
+
+// Types of the output artifacts in the manifest
+const (
+	artifactOutput    = "output"
+	artifactJSOutput  = "js_output"
+	artifactSummary   = "summary"
+	artifactGraph     = "graph"
+	artifactResponses = "responses"
+	artifactIndex     = "index"
+	artifactBundle    = "bundle"
+)
+
+// manifest describes the output artifacts written by the writer
+type manifest struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	// Results is the number of results written to the output
+	Results   int64              `json:"results"`
+	Artifacts []manifestArtifact `json:"artifacts"`
+}
+
+// manifestArtifact is an output file or directory in the manifest.
+//
+// The checksum of a directory is the sha256 of its sorted relative file
+// paths and file checksums, its size is the size of all its files.
+type manifestArtifact struct {
+	Type   string `json:"type"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	Files  int    `json:"files,omitempty"`
+	SHA256 string `json:"sha256"`
+}
+
+// getManifestPath returns the path of the manifest, which is written next
+// to the output file, in the split output or stored responses directory,
+// or in the working directory.
+func getManifestPath(options *Options) string {
+	switch {
+	case options.SplitBy != "":
+		return filepath.Join(options.OutputFile, manifestFile)
+	case options.OutputFile != "":
+		return filepath.Join(filepath.Dir(options.OutputFile), manifestFile)
+	case options.StoreResponse:
+		directory := options.StoreResponseDir
+		if directory == "" {
+			directory = DefaultResponseDir
+		}
+		return filepath.Join(directory, manifestFile)
+	}
+	return manifestFile
+}
+
+// countManifestResult counts the result written to the output
+func (w *StandardWriter) countManifestResult() {
+	atomic.AddInt64(&w.manifestResults, 1)
+}
+
+// getManifestArtifacts returns the types and paths of the output artifacts
+func (w *StandardWriter) getManifestArtifacts() [][2]string {
+	var artifacts [][2]string
+	add := func(artifactType string, paths ...string) {
+		for _, path := range paths {
+			artifacts = append(artifacts, [2]string{artifactType, path})
+		}
+	}
+	if w.outputFile != nil {
+		add(artifactOutput, w.outputFile.getPaths()...)
+	}
+	if w.split != nil {
+		add(artifactOutput, w.split.getPaths()...)
+	}
+	if w.jsOutput != nil {
+		add(artifactJSOutput, w.jsOutput.getPaths()...)
+	}
+	if w.summary != nil && w.summaryFile != "" {
+		add(artifactSummary, w.summaryFile)
+	}
+	if w.graph != nil {
+		add(artifactGraph, w.graphFile)
+	}
+	if w.storeResponse {
+		add(artifactResponses, w.storeResponseDir)
+		add(artifactIndex, filepath.Join(w.storeResponseDir, getIndexFileName(w.indexFormat)))
+	}
+	if w.bundleFile != "" {
+		add(artifactBundle, w.bundleFile)
+	}
+	return artifacts
+}
+
+// writeManifest writes the manifest with the checksums of the artifacts.
+//
+// It must be called after the artifacts are closed and bundled, the
+// artifacts removed by the bundling are not listed.
+func (w *StandardWriter) writeManifest() error {
+	result := manifest{
+		StartedAt:  w.startedAt,
+		FinishedAt: time.Now(),
+		Results:    atomic.LoadInt64(&w.manifestResults),
+		Artifacts:  []manifestArtifact{},
+	}
+	for _, artifact := range w.getManifestArtifacts() {
+		info, err := os.Stat(artifact[1])
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, "could not stat manifest artifact")
+		}
+		entry := manifestArtifact{Type: artifact[0], Path: artifact[1]}
+		if info.IsDir() {
+			entry.Size, entry.Files, entry.SHA256, err = getDirectoryChecksum(artifact[1])
+		} else {
+			entry.Size = info.Size()
+			entry.SHA256, err = getFileChecksum(artifact[1])
+		}
+		if err != nil {
+			return errors.Wrapf(err, "could not checksum manifest artifact %s", artifact[1])
+		}
+		result.Artifacts = append(result.Artifacts, entry)
+	}
+
+	data, err := jsoniter.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal manifest")
+	}
+	if err := os.WriteFile(w.manifestPath, append(data, '\n'), 0644); err != nil {
+		return errors.Wrap(err, "could not write manifest")
+	}
+	return nil
+}
+
+// getFileChecksum returns the hex encoded sha256 of the file
+func getFileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// getDirectoryChecksum returns the size, number of files and checksum of
+// the files of the directory, skipping the manifest itself
+func getDirectoryChecksum(directory string) (int64, int, string, error) {
+	var paths []string
+	var size int64
+	err := filepath.Walk(directory, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() && info.Name() != manifestFile {
+			paths = append(paths, path)
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, "", err
+	}
+	sort.Strings(paths)
+
+	hasher := sha256.New()
+	for _, path := range paths {
+		checksum, err := getFileChecksum(path)
+		if err != nil {
+			return 0, 0, "", err
+		}
+		relative, _ := filepath.Rel(directory, path)
+		_, _ = io.WriteString(hasher, filepath.ToSlash(relative)+" "+checksum+"\n")
+	}
+	return size, len(paths), hex.EncodeToString(hasher.Sum(nil)), nil
+}
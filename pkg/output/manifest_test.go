@@ -0,0 +1,70 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "output.txt")
+
+	writer, err := NewWithOptions(&Options{
+		OutputFile:       outputFile,
+		StoreResponse:    true,
+		StoreResponseDir: filepath.Join(dir, "responses"),
+		Manifest:         true,
+		Silent:           true,
+	})
+	require.Nil(t, err, "could not create writer")
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("body")),
+		Request:    httptest.NewRequest(http.MethodGet, "https://example.com/", nil),
+	}
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, resp), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/about"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	require.Nil(t, err, "could not read manifest")
+	var result manifest
+	require.Nil(t, jsoniter.Unmarshal(data, &result), "could not parse manifest")
+	require.Equal(t, int64(2), result.Results, "could not count results")
+	require.False(t, result.FinishedAt.Before(result.StartedAt), "could not record times")
+
+	artifacts := make(map[string]manifestArtifact)
+	for _, artifact := range result.Artifacts {
+		artifacts[artifact.Type] = artifact
+	}
+	require.Len(t, artifacts, 3, "could not list artifacts")
+
+	output := artifacts[artifactOutput]
+	require.Equal(t, outputFile, output.Path, "could not list output file")
+	checksum, err := getFileChecksum(outputFile)
+	require.Nil(t, err, "could not checksum output file")
+	require.Equal(t, checksum, output.SHA256, "could not checksum output file")
+	require.Equal(t, int64(len("https://example.com/\nhttps://example.com/about\n")), output.Size, "could not get output file size")
+
+	responses := artifacts[artifactResponses]
+	require.Equal(t, 2, responses.Files, "could not count stored response files")
+	require.Len(t, responses.SHA256, 64, "could not checksum responses directory")
+	require.NotEmpty(t, artifacts[artifactIndex].SHA256, "could not checksum index")
+}
+
+func TestManifestPath(t *testing.T) {
+	require.Equal(t, filepath.Join("out", manifestFile), getManifestPath(&Options{OutputFile: filepath.Join("out", "results.txt")}), "could not get output file manifest path")
+	require.Equal(t, filepath.Join("out", manifestFile), getManifestPath(&Options{OutputFile: "out", SplitBy: SplitByHost}), "could not get split manifest path")
+	require.Equal(t, filepath.Join(DefaultResponseDir, manifestFile), getManifestPath(&Options{StoreResponse: true}), "could not get responses manifest path")
+	require.Equal(t, manifestFile, getManifestPath(&Options{}), "could not get default manifest path")
+}
@@ -0,0 +1,52 @@
+package output
+
+import (
+	"net/http"
+	"sync"
+)
+
+// MemoryWriter is a writer which keeps the written results in memory,
+// for collecting the results of a crawl programmatically and in tests.
+//
+// The results are kept as written without any filtering or formatting.
+type MemoryWriter struct {
+	mutex   *sync.RWMutex
+	results []*Result
+}
+
+// NewMemoryWriter returns a new empty in-memory writer
+func NewMemoryWriter() *MemoryWriter {
+	return &MemoryWriter{mutex: &sync.RWMutex{}}
+}
+
+// Write appends the event to the results
+func (w *MemoryWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	w.mutex.Lock()
+	w.results = append(w.results, event)
+	w.mutex.Unlock()
+	return nil
+}
+
+// Results returns a copy of the list of the written results in the
+// order they were written
+func (w *MemoryWriter) Results() []*Result {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	results := make([]*Result, len(w.results))
+	copy(results, w.results)
+	return results
+}
+
+// Flush is a no-op as the results are not buffered
+func (w *MemoryWriter) Flush() error {
+	return nil
+}
+
+// Close is a no-op, the results are still accessible after Close
+func (w *MemoryWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,33 @@
+package output
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryWriter(t *testing.T) {
+	writer := NewMemoryWriter()
+	var _ Writer = writer
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				_ = writer.Write(&Result{URL: "https://example.com/" + strconv.Itoa(i*100+j)}, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.Nil(t, writer.Write(nil, nil), "could not ignore nil result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	results := writer.Results()
+	require.Len(t, results, 1000, "could not get all results")
+	results[0] = nil
+	require.NotNil(t, writer.Results()[0], "could not return a copy of the results")
+}
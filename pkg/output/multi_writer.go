@@ -0,0 +1,63 @@
+package output
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/multierr"
+)
+
+// multiWriter is a writer which fans out events to multiple writers
+type multiWriter struct {
+	writers []Writer
+}
+
+// MultiWriter returns a writer that duplicates its writes to all the
+// provided writers.
+//
+// Every writer is always called even if a previous one fails, and the
+// errors returned by the writers are combined.
+func MultiWriter(writers ...Writer) Writer {
+	return &multiWriter{writers: writers}
+}
+
+// Write writes the event to all the writers
+func (m *multiWriter) Write(event *Result, resp *http.Response) error {
+	return m.WriteContext(context.Background(), event, resp)
+}
+
+// WriteContext writes the event to all the writers, stopping as soon as
+// the context is done. Writers which don't implement ContextWriter are
+// only called if the context is not done yet.
+func (m *multiWriter) WriteContext(ctx context.Context, event *Result, resp *http.Response) error {
+	var err error
+	for _, writer := range m.writers {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return multierr.Append(err, ctxErr)
+		}
+		if contextWriter, ok := writer.(ContextWriter); ok {
+			err = multierr.Append(err, contextWriter.WriteContext(ctx, event, resp))
+		} else {
+			err = multierr.Append(err, writer.Write(event, resp))
+		}
+	}
+	return err
+}
+
+// Flush flushes all the writers
+func (m *multiWriter) Flush() error {
+	var err error
+	for _, writer := range m.writers {
+		err = multierr.Append(err, writer.Flush())
+	}
+	return err
+}
+
+// Close closes all the writers
+func (m *multiWriter) Close() error {
+	var err error
+	for _, writer := range m.writers {
+		err = multierr.Append(err, writer.Close())
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package output
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type mockWriter struct {
+	results []*Result
+	flushed bool
+	closed  bool
+	err     error
+}
+
+func (m *mockWriter) Write(event *Result, resp *http.Response) error {
+	m.results = append(m.results, event)
+	return m.err
+}
+
+func (m *mockWriter) Flush() error {
+	m.flushed = true
+	return m.err
+}
+
+func (m *mockWriter) Close() error {
+	m.closed = true
+	return m.err
+}
+
+func TestMultiWriter(t *testing.T) {
+	first := &mockWriter{err: errors.New("first failed")}
+	second := &mockWriter{}
+	writer := MultiWriter(first, second)
+
+	result := &Result{URL: "https://example.com/"}
+	err := writer.Write(result, nil)
+	require.EqualError(t, err, "first failed", "could not get combined error")
+	require.Equal(t, []*Result{result}, first.results, "could not write to first writer")
+	require.Equal(t, []*Result{result}, second.results, "could not write to second writer after failure")
+
+	err = writer.Flush()
+	require.Error(t, err, "could not get flush error")
+	require.True(t, first.flushed && second.flushed, "could not flush all writers")
+
+	err = writer.Close()
+	require.Error(t, err, "could not get close error")
+	require.True(t, first.closed && second.closed, "could not close all writers")
+}
+
+func TestMultiWriterContext(t *testing.T) {
+	first := &mockWriter{}
+	writer := MultiWriter(first).(ContextWriter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := writer.WriteContext(ctx, &Result{URL: "https://example.com/"}, nil)
+	require.ErrorIs(t, err, context.Canceled, "could not get context error")
+	require.Empty(t, first.results, "could not skip write after cancellation")
+}
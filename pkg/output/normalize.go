@@ -0,0 +1,60 @@
+package output
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// defaultPorts contains the default ports of the schemes, which are
+// removed from the normalized URLs
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// normalizeResultURL returns the absolute normalized form of the URL,
+// resolving a relative URL against the parent, lowercasing the scheme and
+// host, removing the default port and sorting the query parameters if
+// sortQuery is set.
+//
+// URLs which can't be parsed or resolved to an absolute URL are returned
+// as is.
+func normalizeResultURL(URL, parent string, sortQuery bool) string {
+	trimmed := strings.TrimSpace(URL)
+	parsed, err := url.Parse(trimmed)
+	if err != nil {
+		return URL
+	}
+	if !parsed.IsAbs() && parent != "" {
+		base, err := url.Parse(strings.TrimSpace(parent))
+		if err != nil || !base.IsAbs() {
+			return URL
+		}
+		parsed = base.ResolveReference(parsed)
+	}
+	if !parsed.IsAbs() || parsed.Host == "" {
+		return URL
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	host, port := strings.ToLower(parsed.Hostname()), parsed.Port()
+	if port == defaultPorts[parsed.Scheme] {
+		port = ""
+	}
+	if port != "" {
+		parsed.Host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		parsed.Host = "[" + host + "]"
+	} else {
+		parsed.Host = host
+	}
+	if parsed.Path == "" {
+		parsed.Path = "/"
+	}
+	if sortQuery && parsed.RawQuery != "" {
+		// Encode sorts the query parameters by key
+		parsed.RawQuery = parsed.Query().Encode()
+	}
+	return parsed.String()
+}
@@ -0,0 +1,56 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeResultURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		parent    string
+		sortQuery bool
+		want      string
+	}{
+		{name: "relative path", url: "../b?x=1", parent: "https://example.com/a/c", want: "https://example.com/b?x=1"},
+		{name: "relative root", url: "/login", parent: "https://Example.com:443/a", want: "https://example.com/login"},
+		{name: "host case", url: "HTTP://WWW.Example.COM/Path", want: "http://www.example.com/Path"},
+		{name: "default port", url: "http://example.com:80", want: "http://example.com/"},
+		{name: "custom port", url: "https://EXAMPLE.com:8443/a", want: "https://example.com:8443/a"},
+		{name: "ipv6 default port", url: "http://[::1]:80/a", want: "http://[::1]/a"},
+		{name: "unsorted query", url: "https://example.com/?b=2&a=1", want: "https://example.com/?b=2&a=1"},
+		{name: "sorted query", url: "https://example.com/?b=2&a=1", sortQuery: true, want: "https://example.com/?a=1&b=2"},
+		{name: "relative without parent", url: "/a", want: "/a"},
+		{name: "invalid", url: "http://[::1", want: "http://[::1"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, normalizeResultURL(test.url, test.parent, test.sortQuery), "could not normalize url")
+		})
+	}
+}
+
+func TestNormalizeURLsWriter(t *testing.T) {
+	var results []*Result
+	writer, err := NewWithOptions(&Options{
+		NormalizeURLs:   true,
+		SortQueryParams: true,
+		OnResult: func(result *Result) {
+			results = append(results, result)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "/a?b=2&a=1", Parent: "https://EXAMPLE.com:443/"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/b"}, nil), "could not write result")
+
+	require.Len(t, results, 2, "could not write results")
+	require.Equal(t, "https://example.com/a?a=1&b=2", results[0].URL, "could not normalize url")
+	require.Equal(t, "/a?b=2&a=1", results[0].RawURL, "could not keep raw url")
+	require.Equal(t, []string{"a", "b"}, results[0].Params, "could not get params of normalized url")
+	require.Equal(t, "https://example.com/b", results[1].URL, "could not keep normalized url")
+	require.Empty(t, results[1].RawURL, "got raw url for unchanged url")
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"path/filepath"
+	"time"
+)
+
+// Object storage providers
+const (
+	ObjectStorageS3  = "s3"
+	ObjectStorageGCS = "gcs"
+)
+
+const (
+	// DefaultObjectStoragePartSize is the default size of the parts of the
+	// multipart uploads
+	DefaultObjectStoragePartSize = 8 * 1024 * 1024
+	// DefaultObjectStorageTimeout is the default timeout of the object
+	// storage uploads
+	DefaultObjectStorageTimeout = 5 * time.Minute
+	// objectStorageResults is the name of the object the buffered results
+	// are uploaded to
+	objectStorageResults = "results.jsonl"
+)
+
+// ObjectStorageOptions contains the options of the object storage writer
+type ObjectStorageOptions struct {
+	// Provider is the object storage provider (s3,gcs)
+	Provider string
+	// Bucket is the bucket the objects are uploaded to
+	Bucket string
+	// Prefix is prepended to the names of the uploaded objects, separated
+	// by a slash.
+	Prefix string
+	// Region is the region of the S3 bucket, the AWS_REGION and
+	// AWS_DEFAULT_REGION environment variables are used if it is empty.
+	Region string
+	// Endpoint is the URL of an S3 compatible storage or of a storage
+	// emulator. The AWS_ENDPOINT_URL environment variable is used for S3
+	// and STORAGE_EMULATOR_HOST for GCS if it is empty.
+	Endpoint string
+	// Paths are the output files and directories uploaded on Close, the
+	// directories are uploaded as a tar.gz archive and the missing paths
+	// are skipped.
+	Paths []string
+	// PartSize is the size of the parts of the multipart uploads, the
+	// default is used if it is not positive.
+	PartSize int
+	// Timeout is the timeout of the uploads, the default is used if it
+	// is not positive.
+	Timeout time.Duration
+}
+
+// GetArtifactPaths returns the paths of the output artifacts written by
+// the output writer with the options, which are the output files, the
+// stored responses directory and its index, the bundle and the manifest.
+func GetArtifactPaths(options *Options) []string {
+	var paths []string
+	for _, path := range []string{options.OutputFile, options.JSOutput, options.SummaryFile, options.GraphOutput} {
+		if path != "" {
+			paths = append(paths, path)
+		}
+	}
+	if options.StoreResponse {
+		directory := options.StoreResponseDir
+		if directory == "" {
+			directory = DefaultResponseDir
+		}
+		paths = append(paths, directory, filepath.Join(directory, getIndexFileName(options.IndexFormat)))
+	}
+	if options.BundleOnClose != "" {
+		paths = append(paths, options.BundleOnClose)
+	}
+	if options.Manifest {
+		paths = append(paths, getManifestPath(options))
+	}
+	return paths
+}
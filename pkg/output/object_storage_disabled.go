@@ -0,0 +1,12 @@
+//go:build !objectstorage
+// +build !objectstorage
+
+package output
+
+import "github.com/pkg/errors"
+
+// NewObjectStorageWriter returns an error as the object storage writer is
+// only available in the builds with the objectstorage build tag.
+func NewObjectStorageWriter(options ObjectStorageOptions) (Writer, error) {
+	return nil, errors.New("object storage output is not supported by this build, rebuild with -tags objectstorage")
+}
@@ -0,0 +1,58 @@
+//go:build objectstorage
+// +build objectstorage
+
+package output
+
+import (
+	"context"
+	"io"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+)
+
+// gcsUploader uploads objects to a GCS bucket with the resumable uploads
+// of the GCS client
+type gcsUploader struct {
+	client    *storage.Client
+	bucket    string
+	chunkSize int
+	timeout   time.Duration
+}
+
+// newGCSUploader returns an uploader for the bucket, failing if there are
+// no application default credentials. The emulator endpoints require no
+// credentials.
+func newGCSUploader(options ObjectStorageOptions) (*gcsUploader, error) {
+	var clientOptions []option.ClientOption
+	if endpoint := strings.TrimSuffix(options.Endpoint, "/"); endpoint != "" {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+		clientOptions = append(clientOptions, option.WithEndpoint(endpoint+"/storage/v1/"), option.WithoutAuthentication())
+	}
+	client, err := storage.NewClient(context.Background(), clientOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create gcs client")
+	}
+	return &gcsUploader{client: client, bucket: options.Bucket, chunkSize: options.PartSize, timeout: options.Timeout}, nil
+}
+
+// upload uploads the object in chunks of the chunk size, which the client
+// rounds up to a multiple of 256KiB
+func (u *gcsUploader) upload(name string, reader io.Reader) error {
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+
+	writer := u.client.Bucket(u.bucket).Object(name).NewWriter(ctx)
+	writer.ChunkSize = u.chunkSize
+	if _, err := io.Copy(writer, reader); err != nil {
+		// the upload is aborted by the canceled context instead of
+		// being finalized by Close
+		return err
+	}
+	return writer.Close()
+}
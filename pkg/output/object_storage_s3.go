@@ -0,0 +1,91 @@
+//go:build objectstorage
+// +build objectstorage
+
+package output
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/pkg/errors"
+)
+
+// defaultS3Region is the region used when no region is configured
+const defaultS3Region = "us-east-1"
+
+// s3Uploader uploads objects to an S3 bucket with the upload manager of
+// the AWS SDK, which uses multipart uploads for the large objects.
+type s3Uploader struct {
+	uploader *manager.Uploader
+	bucket   string
+	timeout  time.Duration
+}
+
+// newS3Uploader returns an uploader for the bucket, failing if there are
+// no credentials in the default credential chain of the AWS SDK
+func newS3Uploader(options ObjectStorageOptions) (*s3Uploader, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
+	defer cancel()
+
+	region := getFirstEnv("", "AWS_DEFAULT_REGION")
+	if region == "" {
+		region = defaultS3Region
+	}
+	loadOptions := []func(*config.LoadOptions) error{config.WithDefaultRegion(region)}
+	if options.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(options.Region))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOptions...)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load aws config")
+	}
+	if _, err := cfg.Credentials.Retrieve(ctx); err != nil {
+		return nil, errors.Wrap(err, "could not get aws credentials")
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		// the custom endpoints address the bucket in the path
+		if endpoint := getFirstEnv(options.Endpoint, "AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL"); endpoint != "" {
+			o.EndpointResolver = s3.EndpointResolverFromURL(endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	uploader := manager.NewUploader(client, func(u *manager.Uploader) {
+		u.PartSize = int64(options.PartSize)
+		if u.PartSize < manager.MinUploadPartSize {
+			u.PartSize = manager.MinUploadPartSize
+		}
+	})
+	return &s3Uploader{uploader: uploader, bucket: options.Bucket, timeout: options.Timeout}, nil
+}
+
+// upload uploads the object, aborting the multipart upload on failure
+func (u *s3Uploader) upload(name string, reader io.Reader) error {
+	ctx, cancel := context.WithTimeout(context.Background(), u.timeout)
+	defer cancel()
+
+	_, err := u.uploader.Upload(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(name),
+		Body:   reader,
+	})
+	return err
+}
+
+// getFirstEnv returns the value if it is not empty, or the first non
+// empty environment variable of the names
+func getFirstEnv(value string, names ...string) string {
+	for _, name := range names {
+		if value != "" {
+			break
+		}
+		value = os.Getenv(name)
+	}
+	return value
+}
@@ -0,0 +1,193 @@
+//go:build objectstorage
+// +build objectstorage
+
+package output
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeObjectStorage is an S3 and GCS server keeping the uploaded objects
+type fakeObjectStorage struct {
+	server  *httptest.Server
+	mutex   *sync.Mutex
+	objects map[string][]byte
+	// uploads are the parts of the pending multipart or resumable uploads
+	uploads map[string][][]byte
+	auth    []string
+}
+
+func newFakeObjectStorage(t *testing.T) *fakeObjectStorage {
+	storage := &fakeObjectStorage{mutex: &sync.Mutex{}, objects: make(map[string][]byte), uploads: make(map[string][][]byte)}
+	storage.server = httptest.NewServer(http.HandlerFunc(storage.handle))
+	t.Cleanup(storage.server.Close)
+	return storage
+}
+
+func (s *fakeObjectStorage) handle(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	body, _ := io.ReadAll(r.Body)
+	query := r.URL.Query()
+	switch {
+	// gcs uploads
+	case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/") && query.Get("uploadType") == "multipart":
+		bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+		_, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		var metadata struct {
+			Name string `json:"name"`
+		}
+		if part, err := reader.NextPart(); err == nil {
+			_ = jsoniter.NewDecoder(part).Decode(&metadata)
+		}
+		if part, err := reader.NextPart(); err == nil {
+			s.objects[bucket+"/"+metadata.Name], _ = io.ReadAll(part)
+		}
+		s.writeGCSObject(w, bucket, metadata.Name)
+	case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/") && query.Get("uploadType") == "resumable":
+		bucket := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/upload/storage/v1/b/"), "/o")
+		var metadata struct {
+			Name string `json:"name"`
+		}
+		_ = jsoniter.Unmarshal(body, &metadata)
+		session := "/session/" + bucket + "/" + metadata.Name
+		s.uploads[session] = nil
+		w.Header().Set("Location", s.server.URL+session)
+	case strings.HasPrefix(r.URL.Path, "/session/"):
+		s.uploads[r.URL.Path] = append(s.uploads[r.URL.Path], body)
+		if strings.HasSuffix(r.Header.Get("Content-Range"), "/*") {
+			w.Header().Set("X-Http-Status-Code-Override", "308")
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/session/")
+		s.objects[name] = bytes.Join(s.uploads[r.URL.Path], nil)
+		parts := strings.SplitN(name, "/", 2)
+		s.writeGCSObject(w, parts[0], parts[1])
+	// s3 uploads
+	case r.Method == http.MethodPost && query.Has("uploads"):
+		s.uploads[r.URL.Path] = nil
+		_, _ = w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+	case r.Method == http.MethodPut && query.Get("uploadId") == "upload":
+		s.uploads[r.URL.Path] = append(s.uploads[r.URL.Path], body)
+		w.Header().Set("ETag", `"`+query.Get("partNumber")+`"`)
+	case r.Method == http.MethodPost && query.Get("uploadId") == "upload":
+		var complete struct {
+			Parts []struct {
+				PartNumber int `xml:"PartNumber"`
+			} `xml:"Part"`
+		}
+		if err := xml.Unmarshal(body, &complete); err != nil || len(complete.Parts) != len(s.uploads[r.URL.Path]) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`<Error><Code>InvalidPart</Code><Message>invalid parts</Message></Error>`))
+			return
+		}
+		s.objects[strings.TrimPrefix(r.URL.Path, "/")] = bytes.Join(s.uploads[r.URL.Path], nil)
+		_, _ = w.Write([]byte(`<CompleteMultipartUploadResult><ETag>"object"</ETag></CompleteMultipartUploadResult>`))
+	case r.Method == http.MethodPut:
+		s.objects[strings.TrimPrefix(r.URL.Path, "/")] = body
+		w.Header().Set("ETag", `"object"`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// writeGCSObject writes the metadata of the uploaded gcs object
+func (s *fakeObjectStorage) writeGCSObject(w http.ResponseWriter, bucket, name string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = jsoniter.NewEncoder(w).Encode(map[string]string{"bucket": bucket, "name": name})
+}
+
+// writeObjectStorageArtifacts writes an output file and a stored
+// responses directory, returning their paths
+func writeObjectStorageArtifacts(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "output.txt")
+	require.Nil(t, os.WriteFile(outputFile, bytes.Repeat([]byte("https://example.com/\n"), 300000), 0644), "could not write output file")
+	responses := filepath.Join(dir, "responses")
+	require.Nil(t, os.MkdirAll(responses, os.ModePerm), "could not create responses directory")
+	require.Nil(t, os.WriteFile(filepath.Join(responses, "index.txt"), []byte("index"), 0644), "could not write index file")
+	return outputFile, responses
+}
+
+func testObjectStorageWriter(t *testing.T, storage *fakeObjectStorage, options ObjectStorageOptions) {
+	outputFile, responses := writeObjectStorageArtifacts(t)
+	options.Paths = []string{outputFile, responses, filepath.Join(responses, "index.txt"), filepath.Join(responses, "missing.txt")}
+
+	writer, err := NewObjectStorageWriter(options)
+	require.Nil(t, err, "could not create object storage writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Flush(), "could not flush writer")
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Nil(t, writer.Close(), "could not close writer twice")
+
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+
+	var result Result
+	require.Nil(t, jsoniter.Unmarshal(storage.objects["bucket/crawl/results.jsonl"], &result), "could not upload results")
+	require.Equal(t, "https://example.com/", result.URL, "could not upload results")
+	expected, _ := os.ReadFile(outputFile)
+	require.Equal(t, expected, storage.objects["bucket/crawl/output.txt"], "could not upload output file")
+	require.Equal(t, "index", string(storage.objects["bucket/crawl/index.txt"]), "could not upload index file")
+	require.NotEmpty(t, storage.objects["bucket/crawl/responses.tar.gz"], "could not upload responses archive")
+	require.Len(t, storage.objects, 4, "could not skip missing paths")
+}
+
+func TestS3Writer(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_SESSION_TOKEN", "token")
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	storage := newFakeObjectStorage(t)
+
+	testObjectStorageWriter(t, storage, ObjectStorageOptions{Provider: ObjectStorageS3, Bucket: "bucket", Prefix: "/crawl/", Endpoint: storage.server.URL, PartSize: 64 * 1024})
+	for _, auth := range storage.auth {
+		require.True(t, strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), "could not sign request")
+		require.Contains(t, auth, "x-amz-security-token", "could not sign session token")
+	}
+	require.Len(t, storage.uploads["/bucket/crawl/output.txt"], 2, "could not upload output file in parts")
+}
+
+func TestS3WriterNoCredentials(t *testing.T) {
+	for _, name := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE", "AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_CONTAINER_CREDENTIALS_FULL_URI", "AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(t.TempDir(), "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(t.TempDir(), "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	_, err := NewObjectStorageWriter(ObjectStorageOptions{Provider: ObjectStorageS3, Bucket: "bucket"})
+	require.Error(t, err, "could not get missing credentials error")
+}
+
+func TestGCSWriter(t *testing.T) {
+	t.Setenv("STORAGE_EMULATOR_HOST", "")
+	storage := newFakeObjectStorage(t)
+
+	// the emulator endpoint requires no authorization and the chunks
+	// are rounded up to 256KiB
+	testObjectStorageWriter(t, storage, ObjectStorageOptions{Provider: ObjectStorageGCS, Bucket: "bucket", Prefix: "crawl", Endpoint: storage.server.URL, PartSize: 300 * 1024})
+	require.Greater(t, len(storage.uploads["/session/bucket/crawl/output.txt"]), 1, "could not upload output file in chunks")
+	require.Len(t, storage.uploads["/session/bucket/crawl/output.txt"][0], 512*1024, "could not align chunks")
+	for _, auth := range storage.auth {
+		require.Empty(t, auth, "could not skip authorization of emulator")
+	}
+}
@@ -0,0 +1,199 @@
+//go:build objectstorage
+// +build objectstorage
+
+package output
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// objectUploader uploads objects to a bucket of an object storage
+type objectUploader interface {
+	// upload uploads the data read from the reader as the object
+	upload(name string, reader io.Reader) error
+}
+
+// objectStorageWriter is a writer which buffers the results in a temporary
+// file and uploads them with the output artifacts to a bucket on Close.
+type objectStorageWriter struct {
+	options  ObjectStorageOptions
+	uploader objectUploader
+
+	results *os.File
+	buffer  *bufio.Writer
+	written bool
+	closed  bool
+	mutex   *sync.Mutex
+}
+
+// NewObjectStorageWriter returns a writer which uploads the results as
+// JSONL and the output artifacts to an S3 or GCS bucket, for the crawls
+// without a persistent local disk.
+//
+// The results are buffered in a temporary file and everything is uploaded
+// on Close with the SDK of the provider, the files larger than the part
+// size with multipart or resumable uploads. The credentials are read from
+// the default credential chain of the SDK, like the environment or the
+// instance metadata of the cloud the crawl runs in.
+func NewObjectStorageWriter(options ObjectStorageOptions) (Writer, error) {
+	if options.Bucket == "" {
+		return nil, errors.New("no object storage bucket specified")
+	}
+	if options.PartSize <= 0 {
+		options.PartSize = DefaultObjectStoragePartSize
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultObjectStorageTimeout
+	}
+
+	var uploader objectUploader
+	var err error
+	switch options.Provider {
+	case ObjectStorageS3:
+		uploader, err = newS3Uploader(options)
+	case ObjectStorageGCS:
+		uploader, err = newGCSUploader(options)
+	default:
+		return nil, errors.Errorf("invalid object storage provider %s specified", options.Provider)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not create %s uploader", options.Provider)
+	}
+
+	results, err := os.CreateTemp("", "katana-results-*.jsonl")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create results buffer file")
+	}
+	return &objectStorageWriter{
+		options:  options,
+		uploader: uploader,
+		results:  results,
+		buffer:   bufio.NewWriter(results),
+		mutex:    &sync.Mutex{},
+	}, nil
+}
+
+// Write buffers the event as a JSONL line in the results file
+func (w *objectStorageWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	line, err := jsoniter.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal object storage result")
+	}
+	line = append(line, '\n')
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return errors.New("object storage writer is closed")
+	}
+	if _, err := w.buffer.Write(line); err != nil {
+		return errors.Wrap(err, "could not buffer object storage result")
+	}
+	w.written = true
+	return nil
+}
+
+// Flush flushes the buffered results to the results file, the results
+// are only uploaded on Close.
+func (w *objectStorageWriter) Flush() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	return w.buffer.Flush()
+}
+
+// Close uploads the results and the output artifacts and removes the
+// results file. The artifacts are uploaded even if a previous upload
+// failed, and the upload errors are combined.
+func (w *objectStorageWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := w.buffer.Flush()
+	if err == nil && w.written {
+		err = w.uploadFile(objectStorageResults, w.results.Name())
+	}
+	w.results.Close()
+	_ = os.Remove(w.results.Name())
+
+	for _, path := range w.options.Paths {
+		err = multierr.Append(err, w.uploadPath(path))
+	}
+	return err
+}
+
+// uploadPath uploads the file, or the directory as a tar.gz archive,
+// skipping the missing paths
+func (w *objectStorageWriter) uploadPath(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not stat %s", path)
+	}
+	name := filepath.Base(filepath.Clean(path))
+	if !info.IsDir() {
+		return w.uploadFile(name, path)
+	}
+
+	archive, err := os.CreateTemp("", "katana-archive-*.tar.gz")
+	if err != nil {
+		return errors.Wrap(err, "could not create archive file")
+	}
+	defer os.Remove(archive.Name())
+
+	err = writeTarGzBundle(archive, archive.Name(), []string{path})
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Wrapf(err, "could not archive %s", path)
+	}
+	return w.uploadFile(name+".tar.gz", archive.Name())
+}
+
+// uploadFile uploads the file as the named object under the prefix
+func (w *objectStorageWriter) uploadFile(name, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "could not open %s", path)
+	}
+	defer file.Close()
+
+	name = getObjectName(w.options.Prefix, name)
+	if err := w.uploader.upload(name, file); err != nil {
+		return errors.Wrapf(err, "could not upload %s to %s bucket %s", name, w.options.Provider, w.options.Bucket)
+	}
+	return nil
+}
+
+// getObjectName returns the name of the object under the prefix
+func getObjectName(prefix, name string) string {
+	if prefix = strings.Trim(prefix, "/"); prefix == "" {
+		return name
+	}
+	return prefix + "/" + name
+}
@@ -1,52 +1,562 @@
 package output
 
 import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/logrusorgru/aurora"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/gologger"
+	"go.uber.org/multierr"
 )
 
 // Writer is an interface which writes output to somewhere for katana events.
 type Writer interface {
-	// Close closes the output writer interface
+	// Close closes the output writer interface, flushing any buffered
+	// output before closing.
 	Close() error
+	// Flush writes any buffered output to the underlying destination.
+	// Writers which don't buffer output implement it as a no-op.
+	Flush() error
 	// Write writes the event to file and/or screen.
 	Write(*Result, *http.Response) error
 }
 
+// ContextWriter is a writer which supports cancelling writes with a context.
+type ContextWriter interface {
+	Writer
+	// WriteContext writes the event to file and/or screen, returning the
+	// context error as soon as the context is done.
+	WriteContext(context.Context, *Result, *http.Response) error
+}
+
 var (
 	decolorizerRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
 )
 
 // StandardWriter is an standard output writer structure
 type StandardWriter struct {
+	// manifestResults is the number of results counted for the manifest,
+	// it is the first field to be 64-bit aligned for atomic access.
+	manifestResults int64
+	// seq is the sequence id of the last written result, it follows the
+	// manifest counter to be 64-bit aligned as well.
+	seq int64
+
 	storeFields      []string
+	storeFieldsDir   string
 	fields           string
+	outputTemplate   *outputTemplate
 	json             bool
+	jsonl            bool
+	prettyJSON       bool
+	jsonArray        bool
+	jsonArrayOpen    bool
+	csv              bool
+	tsv              bool
+	yaml             bool
+	cef              bool
+	curl             bool
+	cefPrefix        string
+	csvHeader        bool
 	verbose          bool
+	keepFileColor    bool
+	tabular          bool
+	tabularRows      [][]string
+	sortOutput       bool
+	paramsOnly       bool
+	params           map[string]struct{}
+	sortedResults    []*Result
+	dedupByBody      bool
+	normalizeURLs    bool
+	sortQueryParams  bool
+	bodyResults      map[string]*Result
+	bodyResultsOrder []*Result
+	silent           bool
+	countOnly        bool
+	bundleFile       string
+	bundleFormat     string
+	manifestPath     string
+	startedAt        time.Time
+	bundleRemove     bool
 	aurora           aurora.Aurora
+	colorScheme      string
+	timestampFormat  string
 	outputFile       *fileWriter
+	split            *splitOutput
+	jsOutput         *fileWriter
 	outputMutex      *sync.Mutex
+	closeOnce        *sync.Once
+	closeErr         error
+	queue            *outputQueue
 	storeResponse    bool
 	storeResponseDir string
+	omitRequest      bool
+	splitResponses   bool
+	responseNamer    *responseNamer
+	compressResponse bool
+	indexFormat      string
+	indexMutex       *sync.Mutex
+	indexedURLs      map[string]struct{}
+	har              bool
+	onResult         func(*Result)
+	transformers     []func(*Result) *Result
+	captureHeaders   map[string]struct{}
+	captureRequest   bool
+	detectTech       bool
+	captureTLS       bool
+	sniffContentType bool
+	extractSelectors []extractSelector
+	validateOutput   bool
+	outputSchema     *jsonSchema
+	fieldAliases     map[string]string
+	jsonFields       []jsonField
+	hashAlgorithm    string
+	summary          *summary
+	progress         *progress
+	summaryFile      string
+	graph            *linkGraph
+	graphFile        string
+	protobuf         bool
+	deduper          *deduper
+	harEntries       []harEntry
+
+	matchContentTypes  map[string]struct{}
+	filterContentTypes map[string]struct{}
+	matchStatusCodes   map[int]struct{}
+	filterStatusCodes  map[int]struct{}
+	matchExtensions    map[string]struct{}
+	filterExtensions   map[string]struct{}
+	matchRegex         []*regexp.Regexp
+	filterRegex        []*regexp.Regexp
+	redactRegex        []*regexp.Regexp
+	maxOutputDepth     int
+	minBodySize        int64
+	maxBodySize        int64
+	sampler            *sampler
+	throttle           *throttle
+	sinks              []*StandardWriter
+	resultWriters      []Writer
+	logFiltered        bool
+	sequenceIDs        bool
+	baseline           *baseline
+
+	storeStatusCodes  map[int]struct{}
+	storeContentTypes map[string]struct{}
+	// storedResponses are the stored responses by body hash when
+	// deduplicating the stored responses
+	storedResponses      map[string]storedResponse
+	storedResponsesMutex *sync.Mutex
 }
 
 // Options contains the configuration options for output writer
 type Options struct {
-	// Color
+	// Colors enables coloring of the screen output
 	Colors bool
+	// ColorScheme is the color scheme for the screen output (default,source).
+	//
+	// The source scheme colors the URLs based on the tag they were
+	// discovered from and dims the tag and attribute.
+	ColorScheme string
+	// PreserveFileColor specifies to keep the colors of the screen output
+	// in the output file, which are stripped by default.
+	PreserveFileColor bool
 	// JSON specifies to write output in JSON format
-	JSON string
+	JSON bool
+	// PrettyJSON specifies to indent the JSON output, writing every result
+	// as a multi-line object. It has no effect on the JSONL and JSON array
+	// outputs.
+	PrettyJSON bool
+	// JSONL specifies to write output in JSONL format, one compact
+	// JSON object per line.
+	JSONL bool
+	// JSONArray specifies to write output as a single JSON array of
+	// compact JSON objects. The array is opened on the first write and
+	// closed on Close, so the output is not valid JSON until then.
+	JSONArray bool
+	// CSV specifies to write output in CSV format
+	CSV bool
+	// TSV specifies to write output in tab separated values format
+	TSV bool
+	// YAML specifies to write output as YAML documents separated by ---
+	YAML bool
+	// CEF specifies to write output as Common Event Format lines for
+	// the ingestion by SIEMs, like over syslog.
+	CEF bool
+	// OutputCurl specifies to write every result as a curl command
+	// replaying its request with the method, body and, if captured, the
+	// request headers.
+	OutputCurl bool
+	// CEFDeviceVendor is the device vendor of the CEF header, the
+	// default vendor is used if it is empty.
+	CEFDeviceVendor string
+	// CEFDeviceProduct is the device product of the CEF header, the
+	// default product is used if it is empty.
+	CEFDeviceProduct string
+	// CEFDeviceVersion is the device version of the CEF header, the
+	// default version is used if it is empty.
+	CEFDeviceVersion string
+	// Protobuf specifies to write output as varint length-prefixed
+	// protobuf messages (see the pb package) to the output file, or
+	// stdout if no output file is specified.
+	Protobuf bool
+	// HAR specifies to write the requests/responses in HAR format.
+	//
+	// HAR is a single JSON document, so the entries are buffered in
+	// memory and written on Close.
+	HAR bool
+	// Verbose specifies showing verbose output
+	Verbose bool
+	// TimestampFormat is the format of the result timestamps in the JSON
+	// based and verbose screen output (rfc3339,epoch,epochmillis). The
+	// default is rfc3339, zero timestamps are omitted.
+	TimestampFormat string
+	// Tabular specifies to show the URL, status code and content type of
+	// the results in aligned columns on screen. The rows are buffered to
+	// compute the column widths and written in batches and on Flush.
+	Tabular bool
 	// OutputFile is the optional file to write output to
 	OutputFile string
+	// OutputWriter is the optional writer to write output to instead of
+	// the output file, it takes precedence over OutputFile. The formatted
+	// results are written to it as they are written, and it is not closed
+	// on Close.
+	OutputWriter io.Writer
+	// JSOutput is the optional file to additionally write the results
+	// extracted from javascript files and inline scripts to as JSONL,
+	// their source being the script URL for the javascript files.
+	JSOutput string
+	// SplitBy splits the output into a file for each key of the results
+	// (source,status,host) in the OutputFile directory, like js.jsonl and
+	// form.jsonl for the source, or 2xx.jsonl for the status class.
+	//
+	// It can't be combined with the json array, protobuf, HAR and tabular
+	// output which are written as a single document.
+	SplitBy string
+	// CompressOutput specifies to gzip compress the output file. It is
+	// also compressed if its name has the .gz extension.
+	CompressOutput bool
+	// SortOutput specifies to buffer the results in memory and write them
+	// sorted by their URL and then their source on Close, making the
+	// output of crawls reproducible for diffing.
+	//
+	// All the results of the crawl are kept in memory until Close, which
+	// can grow large for very big crawls, and nothing is written before.
+	SortOutput bool
+	// DedupByBody specifies to write only the first result for every
+	// unique response body hash, with the number of the other results
+	// with the same body as its duplicate count.
+	//
+	// The results with a response body are kept in memory and written on
+	// Close, the results without a body are written immediately.
+	DedupByBody bool
+	// ParamsOnly specifies to only write the sorted unique query parameter
+	// names of all the results, one per line, on Close instead of the
+	// results.
+	ParamsOnly bool
+	// BundleOnClose is the optional .zip or .tar.gz file to package the
+	// output file, summary and graph files and stored responses into on
+	// Close.
+	BundleOnClose string
+	// BundleRemoveOriginals specifies to remove the bundled files after
+	// the bundle is written.
+	BundleRemoveOriginals bool
+	// Manifest specifies to write a manifest.json of the output artifacts
+	// with their paths, sizes and sha256 checksums, the number of results
+	// and the start and end time on Close.
+	//
+	// The manifest is written next to the output file, in the split output
+	// or stored responses directory, or else in the working directory.
+	Manifest bool
+	// Silent specifies to not write the output to the screen, the output
+	// is still written to the output file. The output is written to both
+	// the screen and the output file by default.
+	Silent bool
+	// Concurrency is a hint of the number of goroutines writing results
+	// concurrently. If it is greater than 1, the formatted results are
+	// handed off to a queue written by a single goroutine instead of
+	// being written under a global lock by every writer. Write errors
+	// are then returned by the next Flush or Close.
+	Concurrency int
+	// BufferSize is the size of the output file buffer in bytes
+	BufferSize int
+	// FlushInterval is the interval to periodically flush the output file
+	// buffer at. The buffer is always flushed on Close.
+	FlushInterval time.Duration
+	// SyncEvery is the number of written results after which the output
+	// file is synced to disk, guaranteeing the durability of the output
+	// up to the last sync if the process is killed. Syncing is slow, so
+	// it lowers the write throughput, and it is disabled by default.
+	SyncEvery int
+	// SyncInterval is the interval to periodically sync the output file
+	// to disk at, it is disabled by default.
+	SyncInterval time.Duration
+	// RotateSize is the size in bytes after which the output file is
+	// rotated to numbered files like output.1, output.2, etc. Rotation
+	// by size is disabled if it is not positive.
+	//
+	// The rotation can't be used with the csv, tsv and json array outputs
+	// as the rotated files would miss the header or the array brackets.
+	RotateSize int64
+	// RotateInterval is the interval after which the output file is
+	// rotated to the next numbered file on the next write. Rotation by
+	// interval is disabled if it is not positive.
+	RotateInterval time.Duration
+	// Fields is the fields to format in output.
+	//
+	// The json, jsonl and yaml results only contain the selected fields,
+	// with url written as endpoint and the derived fields like path and
+	// the dotted field paths written as strings.
+	Fields string
+	// SelectFields is the list of fields to display in output, it is an
+	// alternative to the comma separated Fields names.
+	SelectFields []Field
+	// OutputTemplate is the template to format the screen output with,
+	// containing {field} placeholders for the field names like
+	// {url}\t{status_code}. It takes precedence over Fields.
+	OutputTemplate string
+	// StoreFields is the fields to store in separate per-host files
+	StoreFields string
+	// StoreFieldsDir is the base directory of the store fields files.
+	//
+	// The files are written to a subdirectory for the run in it, so that
+	// concurrent runs don't write to the same files. If it is empty, no
+	// directory is created and the files are written to the working
+	// directory.
+	StoreFieldsDir string
+	// StoreFieldsRunID is the name of the subdirectory for the run in the
+	// store fields directory, a timestamp with a random suffix like
+	// 20060102T150405Z_1a2b3c4d is used if it is empty.
+	StoreFieldsRunID string
+	// StoreResponse specifies if http requests/responses should be stored
+	StoreResponse bool
+	// StoreResponseDir is the custom directory to store http requests/responses
+	StoreResponseDir string
+	// OmitRequest specifies to store only the response without the request
+	OmitRequest bool
+	// ResponseFilenameMode is the naming scheme of the stored response
+	// files (default,sha1,hierarchical).
+	//
+	// The hierarchical mode mirrors the host and path of the URLs while
+	// the other modes use the sha1 hash of the URL as the file name.
+	ResponseFilenameMode string
+	// SplitStoredResponses specifies to store the request, response and
+	// metadata of each URL as separate request.txt, response.txt and
+	// meta.json files in a directory for the URL.
+	SplitStoredResponses bool
+	// StoreMatchStatusCodes is the list of response status codes of the
+	// responses to store. Ranges of codes can be specified as 2xx, 3xx,
+	// etc. All the responses are stored if it is empty.
+	StoreMatchStatusCodes []string
+	// StoreMatchContentTypes is the list of content-types of the responses
+	// to store, all the responses are stored if it is empty.
+	StoreMatchContentTypes []string
+	// CompressResponses specifies if stored responses should be gzip compressed
+	CompressResponses bool
+	// IndexFormat is the format of the store response index (txt,json)
+	IndexFormat string
+	// ResumeResponses specifies to keep the responses stored by a previous
+	// crawl, appending to the existing index without duplicate URLs.
+	ResumeResponses bool
+	// DedupResponsesByHash specifies to store the responses with the same
+	// body as an already stored response as a link to the file of the
+	// first response, which is recorded in the index as the canonical file.
+	// Only the responses stored by the current crawl are deduplicated.
+	DedupResponsesByHash bool
+	// NormalizeURLs specifies to write the absolute normalized URLs of
+	// the results, resolving the relative URLs against their parent,
+	// lowercasing the host and removing the default port. The original
+	// URL is kept as the raw URL of the results it changes.
+	//
+	// The URLs are normalized before the filters and deduplication, so
+	// they match on the normalized URLs.
+	NormalizeURLs bool
+	// SortQueryParams specifies to also sort the query parameters of the
+	// normalized URLs by name.
+	SortQueryParams bool
+	// Dedup specifies to skip the results already written to output.
+	//
+	// The keys of all the unique results are kept in memory, which can
+	// grow large for very big crawls. DedupMaxEntries caps the number
+	// of tracked keys.
+	Dedup bool
+	// DedupKey is the key to deduplicate results on (url,method-url,url-no-query).
+	// The method and URL are used by default.
+	DedupKey string
+	// DedupMaxEntries is the maximum number of keys to track for
+	// deduplication, everything is tracked if it is not positive.
+	DedupMaxEntries int
+	// CountOnly specifies to only count the results without formatting
+	// and writing them, the totals are written as the summary on Close.
+	//
+	// The output, stored responses, store fields, HAR, graph and bundle
+	// files are not created in this mode.
+	CountOnly bool
+	// Progress specifies to print a progress line with the number of
+	// written results, unique hosts and the write rate to stderr, updated
+	// in place every second until Close.
+	Progress bool
+	// Summary specifies to write a summary of the written results with
+	// the total results, unique hosts, status codes and sources on Close.
+	Summary bool
+	// SummaryFile is the file to write the summary to, the summary is
+	// written to stderr if it is not specified.
+	SummaryFile string
+	// GraphOutput is the optional file to write a Graphviz DOT graph of
+	// the parent to child links of the written results to on Close.
+	GraphOutput string
+	// GraphMaxNodes is the maximum number of URLs in the graph, the
+	// default maximum is used if it is not positive.
+	GraphMaxNodes int
+	// HashAlgorithm is the algorithm to hash the response bodies of the
+	// results with (sha256,sha1,md5). The default is sha256.
+	HashAlgorithm string
+	// CaptureHeaders is the allowlist of response headers to capture in
+	// the results, all the response headers are captured if it is empty.
+	CaptureHeaders []string
+	// CaptureRequestHeaders specifies to capture the headers sent in
+	// the requests in the results.
+	CaptureRequestHeaders bool
+	// DetectTechnologies specifies to fingerprint the technologies of the
+	// responses from their headers, cookies and HTML generator.
+	DetectTechnologies bool
+	// SniffContentType specifies to detect the content-type of the
+	// responses without a Content-Type header from the first 512 bytes
+	// of their body. Sniffing requires the response body, so it has no
+	// effect for the results written without a response or body.
+	SniffContentType bool
+	// ExtractSelectors contains the name to CSS selector pairs of the
+	// values extracted from the HTML responses into the results, like a
+	// meta tag or an embedded JSON blob. The text of the first matched
+	// element is extracted, or an attribute if the selector is followed
+	// by @attribute like meta[name=description]@content.
+	ExtractSelectors map[string]string
+	// CaptureTLS specifies to capture the leaf certificate details of
+	// the HTTPS responses in the results.
+	CaptureTLS bool
+	// ValidateOutput specifies to validate every json result against the
+	// ResultJSONSchema before writing it, failing the write on a mismatch.
+	// It is meant for tests and debugging as it slows down writing.
+	ValidateOutput bool
+	// FieldAliases renames the keys of the json, jsonl and yaml results
+	// mapping the json keys to their new names, like endpoint to loc.
+	// The renamed keys can't collide with each other or the other keys.
+	FieldAliases map[string]string
+	// OnResult is an optional callback invoked with every result after
+	// filtering and before formatting.
+	//
+	// The callback may be called concurrently from multiple goroutines
+	// and must be safe for concurrent use. A panic in the callback is
+	// recovered and logged without stopping the crawl.
+	OnResult func(*Result)
+	// Transformers are optional functions modifying the results, like
+	// stripping the query strings, before filtering and formatting.
+	//
+	// The transformers are applied in order to every result after it is
+	// updated from its response, each one receiving the result returned by
+	// the previous one. A transformer returning nil drops the result. They
+	// run under the concurrency of the crawler and must be safe for
+	// concurrent use, a panic in a transformer is recovered and logged,
+	// dropping the result.
+	Transformers []func(*Result) *Result
+	// MatchContentTypes is the list of content-types to match in output.
+	//
+	// A blank entry matches the results with no content-type.
+	MatchContentTypes []string
+	// FilterContentTypes is the list of content-types to filter from output
+	FilterContentTypes []string
+	// MatchStatusCodes is the list of response status codes to match in
+	// output. Ranges of codes can be specified as 2xx, 3xx, etc.
+	MatchStatusCodes []string
+	// FilterStatusCodes is the list of response status codes to filter
+	// from output. Ranges of codes can be specified as 2xx, 3xx, etc.
+	FilterStatusCodes []string
+	// MatchExtensions is the list of URL path extensions to match in output
+	MatchExtensions []string
+	// FilterExtensions is the list of URL path extensions to filter from output
+	FilterExtensions []string
+	// MatchRegex is the list of regexes to match the result URLs in output
+	MatchRegex []string
+	// FilterRegex is the list of regexes to filter the result URLs from output
+	FilterRegex []string
+	// SampleRate is the probability between 0 and 1 of writing each
+	// result, sampling by rate is disabled if it is 0.
+	SampleRate float64
+	// SampleEveryN specifies to only write every nth result, sampling
+	// every nth result is disabled if it is not greater than 1.
+	SampleEveryN int
+	// MaxOutputDepth is the maximum crawl depth of the results in output,
+	// the seed results with depth 0 are always written.
+	MaxOutputDepth int
+	// MinBodySize and MaxBodySize are the minimum and maximum size in
+	// bytes of the response bodies of the results in output, the limits
+	// are disabled if they are not positive.
+	//
+	// The size is the content length of the response, measured from the
+	// body when the length is unknown. The results without a response
+	// are not filtered by size.
+	MinBodySize int64
+	MaxBodySize int64
+	// RedactPatterns is the list of regexes of the secrets to redact from
+	// the request bodies of the results and from the stored requests and
+	// responses, the matches are replaced with [REDACTED].
+	RedactPatterns []string
+	// RedactBuiltins specifies to also redact the common secrets like the
+	// AWS keys, JWTs, bearer tokens and private keys.
+	RedactBuiltins bool
+	// BaselineFile is a file of known URLs, one per line or as the json
+	// results of a previous crawl, whose results are dropped so that only
+	// the new URLs are written. A missing file is an empty baseline.
+	BaselineFile string
+	// UpdateBaseline specifies to append the new URLs written to output to
+	// the baseline file on close, keeping the baseline current.
+	UpdateBaseline bool
+	// SequenceIDs specifies to assign an increasing sequence id to every
+	// result written to output, which orders and identifies the results
+	// even if their timestamps collide.
+	//
+	// The id is assigned before formatting and is seen by the writers
+	// following the output writer in a MultiWriter, as they receive the
+	// same result. The results may be written out of sequence with
+	// concurrent output.
+	SequenceIDs bool
+	// MaxResultsPerSecond is the maximum number of results written per
+	// second, protecting the slow consumers of the webhook or socket
+	// writers from bursts. The writes over the limit block until they are
+	// allowed in order, disabled if not positive.
+	//
+	// The crawl waits on the blocked writes, so a low limit slows down
+	// the crawl throughput and should be tuned carefully.
+	MaxResultsPerSecond int
+	// Sinks contains the additional outputs fed from the same crawl, each
+	// with its own format, file and filters. The sinks receive a copy of
+	// every result after the URL normalization, capture and transformers,
+	// before the filters of the output writer, and are flushed and closed
+	// with it.
+	Sinks []SinkConfig
+	// ResultWriters are the additional writers, like the webhook and unix
+	// socket writers, receiving a copy of every result written by the
+	// output writer after its filters, deduplication, sampling and rate
+	// limit. They receive no results if only the results are counted, and
+	// are flushed and closed with the output writer.
+	ResultWriters []Writer
+	// LogFiltered specifies to log the URL and the reason of the results
+	// dropped by the filters, the deduplication or the sampling at the
+	// debug level, which is written to stderr.
+	LogFiltered bool
 }
 
 // Result is a result structure for the crawler
@@ -65,118 +575,1122 @@ type Result struct {
 	Tag string `json:"tag,omitempty"`
 	// Attribute is the attribute for the result
 	Attribute string `json:"attribute,omitempty"`
+	// StatusCode is the status code of the response for the result
+	StatusCode int `json:"status_code,omitempty"`
+	// ContentLength is the size of the response body for the result
+	ContentLength int64 `json:"content_length,omitempty"`
+	// ContentType is the media type of the response for the result
+	ContentType string `json:"content_type,omitempty"`
+	// Latency is the round-trip time of the request for the result,
+	// serialized as fractional milliseconds.
+	Latency Duration `json:"latency,omitempty"`
+	// ResponseHeaders contains the captured headers of the response for
+	// the result. Multiple values of a header are joined with "; ".
+	ResponseHeaders map[string]string `json:"response_headers,omitempty"`
+	// RequestHeaders contains the headers sent in the request for the
+	// result. Multiple values of a header are joined with "; ".
+	RequestHeaders map[string]string `json:"request_headers,omitempty"`
+	// Redirects contains the URLs redirected to from the result URL in
+	// order, the last one being the URL of the final response.
+	Redirects []string `json:"redirects,omitempty"`
+	// RedirectLoop specifies if the redirects were truncated because
+	// of a redirect loop or too many redirects.
+	RedirectLoop bool `json:"redirect_loop,omitempty"`
+	// Title is the title of the HTML page of the result
+	Title string `json:"title,omitempty"`
+	// BodyHash is the hex encoded hash of the response body for the result
+	BodyHash string `json:"body_hash,omitempty"`
+	// Form contains the details of the form for form results
+	Form *Form `json:"form,omitempty"`
+	// Depth is the crawl depth the result was found at, seeds being
+	// at depth 0.
+	Depth int `json:"depth,omitempty"`
+	// Parent is the URL of the page the result was discovered on, it is
+	// empty for the seed URLs.
+	Parent string `json:"parent,omitempty"`
+	// Error is the error of the failed request for error results
+	Error string `json:"error,omitempty"`
+	// Technologies contains the technologies fingerprinted from the
+	// response of the result, it is empty if no detection ran.
+	Technologies []string `json:"technologies,omitempty"`
+	// SourceSnippet is the source text around the URL for the results
+	// extracted from scripts, it is empty unless snippets are included.
+	SourceSnippet string `json:"source_snippet,omitempty"`
+	// TLS contains the certificate details of the HTTPS responses, it is
+	// empty unless the TLS details are captured.
+	TLS *TLS `json:"tls,omitempty"`
+	// Params contains the sorted unique names of the query parameters of
+	// the URL, with the array-style names like a[] normalized to a.
+	Params []string `json:"params,omitempty"`
+	// Proto is the protocol of the response like HTTP/1.1 or HTTP/2.0
+	Proto string `json:"proto,omitempty"`
+	// DuplicateCount is the number of other results with the same response
+	// body, it is only counted if the results are deduplicated by body.
+	DuplicateCount int `json:"duplicate_count,omitempty"`
+	// Seq is the sequence id of the result starting from 1, it is only
+	// assigned if sequence ids are enabled.
+	Seq int64 `json:"seq,omitempty"`
+	// Extracted contains the values extracted by the extract selectors
+	// from the HTML response, keyed by the selector names.
+	Extracted map[string]string `json:"extracted,omitempty"`
+	// RawURL is the original URL of the result, it is only set if the URL
+	// was changed by the URL normalization.
+	RawURL string `json:"raw_url,omitempty"`
+}
+
+// Form is a form discovered during crawling
+type Form struct {
+	// Action is the absolute URL the form is submitted to
+	Action string `json:"action,omitempty"`
+	// Method is the method the form is submitted with
+	Method string `json:"method,omitempty"`
+	// Inputs contains the names of the form inputs
+	Inputs []string `json:"inputs,omitempty"`
 }
 
 const (
-	storeFieldsDirectory = "katana_output"
-	indexFile            = "index.txt"
-	DefaultResponseDir   = "katana_responses"
+	indexFile             = "index.txt"
+	jsonIndexFile         = "index.jsonl"
+	requestFile           = "request.txt"
+	responseFile          = "response.txt"
+	metaFile              = "meta.json"
+	DefaultResponseDir    = "katana_responses"
+	DefaultStoreFieldsDir = "katana_output"
+)
+
+// Color schemes for the screen output
+const (
+	ColorSchemeDefault = "default"
+	ColorSchemeSource  = "source"
+)
+
+// Formats of the result timestamps
+const (
+	TimestampFormatRFC3339     = "rfc3339"
+	TimestampFormatEpoch       = "epoch"
+	TimestampFormatEpochMillis = "epochmillis"
+)
+
+// Algorithms for hashing the response bodies
+const (
+	HashAlgorithmSHA256 = "sha256"
+	HashAlgorithmSHA1   = "sha1"
+	HashAlgorithmMD5    = "md5"
+)
+
+// Formats of the store response index file
+const (
+	IndexFormatText = "txt"
+	IndexFormatJSON = "json"
 )
 
 // New returns a new output writer instance
+//
+// Deprecated: use NewWithOptions instead.
 func New(colors, json, verbose, storeResponse bool, file, fields, storeFields, storeResponseDir string) (Writer, error) {
+	return NewWithOptions(&Options{
+		Colors:           colors,
+		JSON:             json,
+		Verbose:          verbose,
+		StoreResponse:    storeResponse,
+		OutputFile:       file,
+		Fields:           fields,
+		StoreFields:      storeFields,
+		StoreFieldsDir:   DefaultStoreFieldsDir,
+		StoreResponseDir: storeResponseDir,
+	})
+}
+
+// NewWithOptions returns a new output writer instance from options
+func NewWithOptions(options *Options) (Writer, error) {
+	if options.CountOnly {
+		options = getCountOnlyOptions(options)
+	}
 	writer := &StandardWriter{
-		fields:           fields,
-		json:             json,
-		verbose:          verbose,
-		aurora:           aurora.NewAurora(colors),
+		fields:           options.Fields,
+		countOnly:        options.CountOnly,
+		json:             options.JSON,
+		jsonl:            options.JSONL,
+		prettyJSON:       options.PrettyJSON,
+		jsonArray:        options.JSONArray,
+		csv:              options.CSV,
+		tsv:              options.TSV,
+		yaml:             options.YAML,
+		cef:              options.CEF,
+		curl:             options.OutputCurl,
+		cefPrefix:        getCEFPrefix(options.CEFDeviceVendor, options.CEFDeviceProduct, options.CEFDeviceVersion),
+		har:              options.HAR,
+		onResult:         options.OnResult,
+		resultWriters:    options.ResultWriters,
+		transformers:     options.Transformers,
+		captureHeaders:   newHeaderSet(options.CaptureHeaders),
+		captureRequest:   options.CaptureRequestHeaders,
+		detectTech:       options.DetectTechnologies,
+		captureTLS:       options.CaptureTLS,
+		sniffContentType: options.SniffContentType,
+		validateOutput:   options.ValidateOutput,
+		outputSchema:     resultSchema.withAliases(options.FieldAliases),
+		fieldAliases:     options.FieldAliases,
+		hashAlgorithm:    options.HashAlgorithm,
+		summaryFile:      options.SummaryFile,
+		graphFile:        options.GraphOutput,
+		protobuf:         options.Protobuf,
+		verbose:          options.Verbose,
+		keepFileColor:    options.PreserveFileColor,
+		tabular:          options.Tabular,
+		sortOutput:       options.SortOutput,
+		paramsOnly:       options.ParamsOnly,
+		params:           make(map[string]struct{}),
+		dedupByBody:      options.DedupByBody,
+		normalizeURLs:    options.NormalizeURLs,
+		sortQueryParams:  options.SortQueryParams,
+		bodyResults:      make(map[string]*Result),
+		silent:           options.Silent,
+		bundleFile:       options.BundleOnClose,
+		bundleRemove:     options.BundleRemoveOriginals,
+		startedAt:        time.Now(),
+		aurora:           aurora.NewAurora(options.Colors && !noColorEnabled()),
 		outputMutex:      &sync.Mutex{},
-		storeResponse:    storeResponse,
-		storeResponseDir: storeResponseDir,
+		closeOnce:        &sync.Once{},
+		storeResponse:    options.StoreResponse,
+		storeResponseDir: options.StoreResponseDir,
+		omitRequest:      options.OmitRequest,
+		splitResponses:   options.SplitStoredResponses,
+		compressResponse: options.CompressResponses,
+		indexFormat:      options.IndexFormat,
+		indexMutex:       &sync.Mutex{},
+
+		matchContentTypes:  newContentTypeSet(options.MatchContentTypes),
+		filterContentTypes: newContentTypeSet(options.FilterContentTypes),
+		matchExtensions:    newExtensionSet(options.MatchExtensions),
+		filterExtensions:   newExtensionSet(options.FilterExtensions),
+		maxOutputDepth:     options.MaxOutputDepth,
+		minBodySize:        options.MinBodySize,
+		maxBodySize:        options.MaxBodySize,
+		logFiltered:        options.LogFiltered,
+		sequenceIDs:        options.SequenceIDs,
+		throttle:           newThrottle(options.MaxResultsPerSecond),
+
+		storeContentTypes: newContentTypeSet(options.StoreMatchContentTypes),
+	}
+	if err := validateFieldAliases(options.FieldAliases); err != nil {
+		return nil, errors.Wrap(err, "could not validate field aliases")
+	}
+	if options.MinBodySize > 0 && options.MaxBodySize > 0 && options.MinBodySize > options.MaxBodySize {
+		return nil, errors.Errorf("invalid body size range %d-%d specified", options.MinBodySize, options.MaxBodySize)
+	}
+	switch options.ColorScheme {
+	case "", ColorSchemeDefault, ColorSchemeSource:
+	default:
+		return nil, errors.Errorf("invalid color scheme %s specified", options.ColorScheme)
+	}
+	switch options.TimestampFormat {
+	case "":
+		writer.timestampFormat = TimestampFormatRFC3339
+	case TimestampFormatRFC3339, TimestampFormatEpoch, TimestampFormatEpochMillis:
+		writer.timestampFormat = options.TimestampFormat
+	default:
+		return nil, errors.Errorf("invalid timestamp format %s specified", options.TimestampFormat)
+	}
+	if options.Summary {
+		writer.summary = newSummary()
+	}
+	if options.GraphOutput != "" {
+		writer.graph = newLinkGraph(options.GraphMaxNodes)
+	}
+	if options.Manifest {
+		writer.manifestPath = getManifestPath(options)
+	}
+	if options.Progress {
+		writer.progress = newProgress(os.Stderr, progressInterval)
+	}
+	if options.Colors && !noColorEnabled() {
+		writer.colorScheme = options.ColorScheme
+	}
+	switch options.HashAlgorithm {
+	case "":
+		writer.hashAlgorithm = HashAlgorithmSHA256
+	case HashAlgorithmSHA256, HashAlgorithmSHA1, HashAlgorithmMD5:
+	default:
+		return nil, errors.Errorf("invalid hash algorithm %s specified", options.HashAlgorithm)
+	}
+	switch options.IndexFormat {
+	case "", IndexFormatText, IndexFormatJSON:
+	default:
+		return nil, errors.Errorf("invalid index format %s specified", options.IndexFormat)
+	}
+	if options.BundleOnClose != "" {
+		format, err := getBundleFormat(options.BundleOnClose)
+		if err != nil {
+			return nil, err
+		}
+		writer.bundleFormat = format
+	}
+	var err error
+	if writer.matchStatusCodes, err = newStatusCodeSet(options.MatchStatusCodes); err != nil {
+		return nil, errors.Wrap(err, "could not parse match status codes")
+	}
+	if writer.filterStatusCodes, err = newStatusCodeSet(options.FilterStatusCodes); err != nil {
+		return nil, errors.Wrap(err, "could not parse filter status codes")
+	}
+	if writer.storeStatusCodes, err = newStatusCodeSet(options.StoreMatchStatusCodes); err != nil {
+		return nil, errors.Wrap(err, "could not parse store status codes")
+	}
+	if writer.matchRegex, err = compileRegexes(options.MatchRegex); err != nil {
+		return nil, errors.Wrap(err, "could not compile match regex")
+	}
+	if writer.filterRegex, err = compileRegexes(options.FilterRegex); err != nil {
+		return nil, errors.Wrap(err, "could not compile filter regex")
+	}
+	if writer.redactRegex, err = compileRedactRegexes(options.RedactPatterns, options.RedactBuiltins); err != nil {
+		return nil, errors.Wrap(err, "could not compile redact pattern")
+	}
+	if options.BaselineFile != "" {
+		if writer.baseline, err = newBaseline(options.BaselineFile, options.UpdateBaseline); err != nil {
+			return nil, errors.Wrap(err, "could not load baseline")
+		}
+	}
+	if writer.extractSelectors, err = compileExtractSelectors(options.ExtractSelectors); err != nil {
+		return nil, errors.Wrap(err, "could not compile extract selectors")
+	}
+	if writer.sampler, err = newSampler(options.SampleRate, options.SampleEveryN); err != nil {
+		return nil, errors.Wrap(err, "could not create sampler")
+	}
+	if options.Dedup {
+		deduper, err := newDeduper(options.DedupKey, options.DedupMaxEntries)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create deduper")
+		}
+		writer.deduper = deduper
+	}
+	if len(options.SelectFields) > 0 {
+		if options.Fields != "" {
+			return nil, errors.New("fields and select fields can't be used together")
+		}
+		fields, err := joinFields(options.SelectFields)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not validate select fields")
+		}
+		writer.fields = fields
 	}
 	// Perform validations for fields and store-fields
-	if fields != "" {
-		if err := validateFieldNames(fields); err != nil {
+	if options.Fields != "" {
+		if err := validateFieldNames(options.Fields); err != nil {
+			return nil, errors.Wrap(err, "could not validate fields")
+		}
+	}
+	if writer.fields != "" {
+		writer.jsonFields = getJSONFields(writer.fields)
+		if err := validateJSONFields(writer.jsonFields, options.FieldAliases); err != nil {
 			return nil, errors.Wrap(err, "could not validate fields")
 		}
+		writer.outputSchema = writer.outputSchema.withJSONFields(writer.jsonFields)
 	}
-	if storeFields != "" {
-		_ = os.MkdirAll(storeFieldsDirectory, os.ModePerm)
-		if err := validateFieldNames(storeFields); err != nil {
+	if options.OutputTemplate != "" {
+		template, err := parseOutputTemplate(options.OutputTemplate)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not parse output template")
+		}
+		writer.outputTemplate = template
+	}
+	if options.StoreFields != "" {
+		if err := validateFieldNames(options.StoreFields); err != nil {
 			return nil, errors.Wrap(err, "could not validate store fields")
 		}
-		writer.storeFields = append(writer.storeFields, strings.Split(storeFields, ",")...)
+		if options.StoreFieldsDir != "" {
+			directory, err := createStoreFieldsDir(options.StoreFieldsDir, options.StoreFieldsRunID)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not create store fields directory")
+			}
+			writer.storeFieldsDir = directory
+		}
+		writer.storeFields = append(writer.storeFields, strings.Split(options.StoreFields, ",")...)
+	}
+	if (options.RotateSize > 0 || options.RotateInterval > 0) && (options.CSV || options.TSV || options.JSONArray) {
+		return nil, errors.New("output rotation can't be used with csv, tsv or json array output")
 	}
-	if file != "" {
-		output, err := newFileOutputWriter(file)
+	fileOptions := fileWriterOptions{
+		compress:       options.CompressOutput || strings.HasSuffix(options.OutputFile, ".gz"),
+		bufferSize:     options.BufferSize,
+		flushInterval:  options.FlushInterval,
+		rotateSize:     options.RotateSize,
+		rotateInterval: options.RotateInterval,
+		syncEvery:      options.SyncEvery,
+		syncInterval:   options.SyncInterval,
+	}
+	if options.SplitBy != "" {
+		if options.OutputWriter != nil {
+			return nil, errors.New("split by can't be used with an output writer")
+		}
+		if options.JSONArray || options.Protobuf || options.HAR || options.Tabular {
+			return nil, errors.New("split by can't be used with json array, protobuf, har or tabular output")
+		}
+		fileOptions.compress = options.CompressOutput
+		split, err := newSplitOutput(options.SplitBy, options.OutputFile, getSplitExtension(options), fileOptions)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create split output")
+		}
+		writer.split = split
+	} else if options.OutputWriter != nil {
+		fileOptions.compress = options.CompressOutput
+		writer.outputFile = newStreamOutputWriter(options.OutputWriter, fileOptions)
+	} else if options.OutputFile != "" {
+		output, err := newFileOutputWriterWithOptions(options.OutputFile, fileOptions)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not create output file")
 		}
 		writer.outputFile = output
 	}
-	if storeResponse {
+	if options.JSOutput != "" {
+		jsOutput, err := newFileOutputWriter(options.JSOutput)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create javascript output file")
+		}
+		writer.jsOutput = jsOutput
+	}
+	if options.StoreResponse {
 		writer.storeResponseDir = DefaultResponseDir
-		if storeResponseDir != DefaultResponseDir && storeResponseDir != "" {
-			writer.storeResponseDir = storeResponseDir
+		if options.StoreResponseDir != DefaultResponseDir && options.StoreResponseDir != "" {
+			writer.storeResponseDir = options.StoreResponseDir
 		}
-		_ = os.RemoveAll(writer.storeResponseDir)
-		_ = os.MkdirAll(writer.storeResponseDir, os.ModePerm)
-		_, err := newFileOutputWriter(filepath.Join(writer.storeResponseDir, indexFile))
+		responseNamer, err := newResponseNamer(writer.storeResponseDir, options.ResponseFilenameMode)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not create index file")
+			return nil, errors.Wrap(err, "could not create response namer")
+		}
+		writer.responseNamer = responseNamer
+		if options.DedupResponsesByHash {
+			writer.storedResponses = make(map[string]storedResponse)
+			writer.storedResponsesMutex = &sync.Mutex{}
 		}
+
+		indexPath := filepath.Join(writer.storeResponseDir, getIndexFileName(writer.indexFormat))
+		if options.ResumeResponses {
+			_ = os.MkdirAll(writer.storeResponseDir, os.ModePerm)
+			indexedURLs, err := readIndexURLs(indexPath, writer.indexFormat)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not read index file")
+			}
+			writer.indexedURLs = indexedURLs
+
+			index, err := os.OpenFile(indexPath, os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not create index file")
+			}
+			index.Close()
+		} else {
+			_ = os.RemoveAll(writer.storeResponseDir)
+			_ = os.MkdirAll(writer.storeResponseDir, os.ModePerm)
+			index, err := newFileOutputWriter(indexPath)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not create index file")
+			}
+			index.Close()
+		}
+	}
+	if err := writer.createSinks(options); err != nil {
+		return nil, err
+	}
+	if options.Concurrency > 1 && !options.HAR && !options.Tabular && !options.CountOnly && !options.SortOutput && !options.ParamsOnly && !options.DedupByBody {
+		writer.queue = newOutputQueue(writer, options.Concurrency)
 	}
 	return writer, nil
 }
 
+// noColorEnabled returns true if colors are disabled with the
+// NO_COLOR environment variable (https://no-color.org).
+func noColorEnabled() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
 // Write writes the event to file and/or screen.
 func (w *StandardWriter) Write(event *Result, resp *http.Response) error {
+	return w.WriteContext(context.Background(), event, resp)
+}
+
+// WriteContext writes the event to file and/or screen, returning the
+// context error as soon as the context is done.
+//
+// The context is checked before every blocking step, a write to the
+// underlying file which has already started is not interrupted.
+func (w *StandardWriter) WriteContext(ctx context.Context, event *Result, resp *http.Response) (err error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	if event != nil {
-		if len(w.storeFields) > 0 {
-			storeFields(event, w.storeFields)
+		if w.normalizeURLs {
+			if normalized := normalizeResultURL(event.URL, event.Parent, w.sortQueryParams); normalized != event.URL {
+				event.RawURL, event.URL = event.URL, normalized
+			}
+		}
+		event.Params = getQueryParams(event.URL)
+		if len(w.redactRegex) > 0 && event.Body != "" {
+			event.Body = w.redactString(event.Body)
+		}
+		if resp != nil {
+			updateResultFromResponse(event, resp)
+			if w.sniffContentType && event.ContentType == "" {
+				sniffResultContentType(event, resp)
+			}
+		}
+		// the filters only use the url and the response status, so the
+		// filtered results are not enriched unless the sinks write them.
+		// The transformed results are filtered as the transformers can
+		// change the filtered fields.
+		filtered := len(w.transformers) == 0 && w.isFilteredResult(event)
+		if filtered && len(w.sinks) == 0 {
+			event = nil
+		}
+		if event != nil && resp != nil {
+			if len(w.extractSelectors) > 0 && isHTMLContentType(event.ContentType) {
+				event.Extracted = extractValues(w.extractSelectors, readResponseBody(resp))
+			}
+			event.ResponseHeaders = w.getResponseHeaders(resp.Header)
+			event.BodyHash = hashBody(w.hashAlgorithm, readResponseBody(resp))
+			if w.captureRequest && resp.Request != nil {
+				event.RequestHeaders = getRequestHeaders(resp.Request)
+			}
+			if w.detectTech {
+				event.Technologies = detectTechnologies(resp)
+			}
+			if w.captureTLS {
+				event.TLS = getTLSDetails(resp.TLS)
+			}
+		}
+		if event != nil && len(w.transformers) > 0 {
+			if event = w.transformResult(event); event != nil {
+				filtered = w.isFilteredResult(event)
+			}
+		}
+		if event != nil && len(w.sinks) > 0 {
+			// the sinks are written even if the result is filtered or its
+			// write fails
+			sinkErr := w.writeSinks(ctx, event)
+			defer func() { err = multierr.Append(err, sinkErr) }()
 		}
-		var data []byte
-		var err error
+		if event != nil && !filtered && w.isWrittenResult(event) {
+			if w.throttle != nil {
+				if err := w.throttle.wait(ctx); err != nil {
+					return err
+				}
+			}
+			if w.sequenceIDs {
+				event.Seq = atomic.AddInt64(&w.seq, 1)
+			}
+			if w.onResult != nil {
+				w.callOnResult(event)
+			}
+			if w.summary != nil {
+				w.updateSummary(event)
+			}
+			if w.progress != nil {
+				w.progress.update(event)
+			}
+			if w.manifestPath != "" {
+				w.countManifestResult()
+			}
+			if w.baseline != nil {
+				w.baseline.add(event.URL)
+			}
+			if w.countOnly {
+				return nil
+			}
+			if len(w.resultWriters) > 0 {
+				// the result writers are written even if the write of
+				// the result fails
+				resultErr := w.writeResultWriters(event, resp)
+				defer func() { err = multierr.Append(err, resultErr) }()
+			}
+			if w.jsOutput != nil && isJSResult(event) {
+				if err := w.writeJSResult(event); err != nil {
+					return err
+				}
+			}
+			if w.graph != nil {
+				w.updateGraph(event)
+			}
+			if w.har {
+				if resp != nil {
+					w.writeHAREntry(event, resp)
+				}
+			} else if w.paramsOnly {
+				w.addParams(event)
+			} else if w.dedupByBody && isBodyDedupResult(event) {
+				w.bufferBodyResult(event)
+			} else if w.sortOutput {
+				w.bufferSortedResult(event)
+			} else if err := w.writeResult(ctx, event); err != nil {
+				return err
+			}
+		}
+	} else if w.har && resp != nil {
+		w.writeHAREntry(nil, resp)
+	}
+	if w.storeResponse && resp != nil && w.matchStoredResponse(resp) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := w.writeResponse(resp); err != nil {
+			return errors.Wrap(err, "could not store response")
+		}
+	}
+	return nil
+}
 
-		if w.json {
-			data, err = w.formatJSON(event)
-		} else {
-			data, err = w.formatScreen(event)
+// getCountOnlyOptions returns a copy of the options with the summary
+// enabled and all the file outputs disabled
+func getCountOnlyOptions(options *Options) *Options {
+	countOptions := *options
+	countOptions.Summary = true
+	countOptions.OutputFile = ""
+	countOptions.OutputWriter = nil
+	countOptions.SplitBy = ""
+	countOptions.JSOutput = ""
+	countOptions.StoreResponse = false
+	countOptions.StoreFields = ""
+	countOptions.HAR = false
+	countOptions.GraphOutput = ""
+	countOptions.BundleOnClose = ""
+	countOptions.Manifest = false
+	return &countOptions
+}
+
+// writeResultWriters writes a copy of the result to every result writer.
+// All the writers are written even if one fails.
+func (w *StandardWriter) writeResultWriters(event *Result, resp *http.Response) error {
+	var err error
+	for _, resultWriter := range w.resultWriters {
+		err = multierr.Append(err, resultWriter.Write(event.Clone(), resp))
+	}
+	return err
+}
+
+// callOnResult calls the result callback recovering from any panics
+func (w *StandardWriter) callOnResult(event *Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			gologger.Error().Msgf("Recovered from panic in result callback for %s: %v\n", event.URL, r)
 		}
-		if err != nil {
-			return errors.Wrap(err, "could not format output")
+	}()
+	w.onResult(event)
+}
+
+// transformResult applies the transformers to the result in order,
+// returning nil if the result is dropped by a transformer.
+func (w *StandardWriter) transformResult(event *Result) (transformed *Result) {
+	defer func() {
+		if r := recover(); r != nil {
+			gologger.Error().Msgf("Recovered from panic in result transformer for %s: %v\n", event.URL, r)
+			transformed = nil
 		}
-		if len(data) == 0 {
+	}()
+	transformed = event
+	for _, transformer := range w.transformers {
+		if transformed = transformer(transformed); transformed == nil {
+			if w.logFiltered {
+				gologger.Debug().Msgf("Filtered %s: dropped by a transformer\n", event.URL)
+			}
 			return nil
 		}
-		w.outputMutex.Lock()
-		defer w.outputMutex.Unlock()
+	}
+	return transformed
+}
+
+// writeResult formats and writes the result to file and/or screen.
+func (w *StandardWriter) writeResult(ctx context.Context, event *Result) error {
+	if len(w.storeFields) > 0 {
+		storeFields(event, w.storeFields, w.storeFieldsDir)
+	}
+	var data []byte
+	var err error
+
+	switch {
+	case w.jsonArray, w.jsonl:
+		data, err = w.formatJSONL(event)
+	case w.json:
+		data, err = w.formatJSON(event)
+	case w.csv:
+		data, err = w.formatCSV(event)
+	case w.tsv:
+		data, err = w.formatTSV(event)
+	case w.yaml:
+		data, err = w.formatYAML(event)
+	case w.cef:
+		data, err = w.formatCEF(event)
+	case w.curl:
+		data, err = w.formatCurl(event)
+	case w.protobuf:
+		data, err = w.formatProtobuf(event)
+	case w.outputTemplate != nil:
+		data, err = w.formatTemplate(event)
+	case w.tabular && w.fields == "":
+		if err := w.writeTabularRow(event); err != nil {
+			return errors.Wrap(err, "could not write to output")
+		}
+		return nil
+	default:
+		data, err = w.formatScreen(event)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not format output")
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if w.validateOutput && (w.json || w.jsonl || w.jsonArray) {
+		if err := w.outputSchema.validateJSON(data); err != nil {
+			return errors.Wrap(err, "could not validate output")
+		}
+	}
+	// the file data is decolorized before taking the output mutex as
+	// it is the most expensive step of writing
+	fileData := data
+	if (w.outputFile != nil || w.split != nil) && !w.protobuf && !w.keepFileColor && !w.json && !w.jsonl && !w.jsonArray && !w.csv && !w.tsv && !w.yaml && !w.cef && !w.curl {
+		fileData = decolorizerRegex.ReplaceAll(data, []byte(""))
+	}
+	var splitKey string
+	if w.split != nil {
+		splitKey = w.split.getKey(event)
+	}
+	if w.queue != nil {
+		return w.queue.push(ctx, data, fileData, splitKey)
+	}
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	// the context may be done while waiting for other writes
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return w.writeData(data, fileData, splitKey)
+}
 
+// writeData writes the formatted data to screen and the decolorized
+// file data to the output file, or to the split output file of the key.
+//
+// It must be called with the output mutex held.
+func (w *StandardWriter) writeData(data, fileData []byte, splitKey string) error {
+	if w.protobuf {
+		return w.writeBinary(data)
+	}
+	if w.jsonArray {
+		data = w.getJSONArrayItem(data)
+		fileData = data
+	}
+	if (w.csv || w.tsv) && !w.csvHeader {
+		if err := w.writeCSVHeader(); err != nil {
+			return errors.Wrap(err, "could not write csv header")
+		}
+	}
+	w.writeScreen(data)
+	output := w.outputFile
+	if w.split != nil {
+		var header func() ([]byte, error)
+		if w.csv || w.tsv {
+			header = w.formatCSVFileHeader
+		}
+		file, err := w.split.getFile(splitKey, header)
+		if err != nil {
+			return err
+		}
+		output = file
+	}
+	if output != nil {
+		if writeErr := output.Write(fileData); writeErr != nil {
+			return errors.Wrap(writeErr, "could not write to output")
+		}
+	}
+	return nil
+}
+
+// writeScreen writes the data to the screen unless silent
+func (w *StandardWriter) writeScreen(data []byte) {
+	if !w.silent {
 		gologger.Silent().Msgf("%s", string(data))
-		if w.outputFile != nil {
-			if !w.json {
-				data = decolorizerRegex.ReplaceAll(data, []byte(""))
-			}
-			if writeErr := w.outputFile.Write(data); writeErr != nil {
-				return errors.Wrap(err, "could not write to output")
-			}
+	}
+}
+
+// getJSONArrayItem returns the json object as an item of the json array,
+// opening the array on the first item and separating the later ones.
+//
+// It must be called with the output mutex held.
+func (w *StandardWriter) getJSONArrayItem(data []byte) []byte {
+	prefix := ","
+	if !w.jsonArrayOpen {
+		prefix = "["
+		w.jsonArrayOpen = true
+	}
+	return append([]byte(prefix), data...)
+}
+
+// closeJSONArray writes the end of the json array to file and/or screen,
+// writing an empty array if no results were written.
+func (w *StandardWriter) closeJSONArray() error {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	data := []byte("]")
+	if !w.jsonArrayOpen {
+		data = []byte("[]")
+	}
+	w.writeScreen(data)
+	if w.outputFile != nil {
+		if err := w.outputFile.Write(data); err != nil {
+			return errors.Wrap(err, "could not write to output")
 		}
 	}
+	return nil
+}
 
-	if w.storeResponse && resp != nil {
-		if file, err := getResponseFile(w.storeResponseDir, resp.Request.URL.String()); err == nil {
-			data, err := w.formatResponse(resp)
-			if err != nil {
-				return errors.Wrap(err, "could not store response")
-			}
-			if err := updateIndex(w.storeResponseDir, resp); err != nil {
-				return errors.Wrap(err, "could not store response")
-			}
-			if writeErr := file.Write(data); writeErr != nil {
-				return errors.Wrap(err, "could not store response")
+// writeBinary writes the binary data to the output file, or stdout if
+// there is no output file and the writer is not silent.
+//
+// It must be called with the output mutex held.
+func (w *StandardWriter) writeBinary(data []byte) error {
+	if w.outputFile != nil {
+		if err := w.outputFile.WriteRaw(data); err != nil {
+			return errors.Wrap(err, "could not write to output")
+		}
+		return nil
+	}
+	if w.silent {
+		return nil
+	}
+	if _, err := os.Stdout.Write(data); err != nil {
+		return errors.Wrap(err, "could not write to stdout")
+	}
+	return nil
+}
+
+// writeResponse stores the response in the store response directory
+func (w *StandardWriter) writeResponse(resp *http.Response) error {
+	if w.splitResponses {
+		return w.writeSplitResponse(resp)
+	}
+	URL := resp.Request.URL.String()
+	fileName, err := w.getResponseFileName(URL)
+	if err != nil {
+		return errors.Wrapf(err, "could not get response file for %s", URL)
+	}
+	data, err := w.formatResponse(resp)
+	if err != nil {
+		return err
+	}
+	canonical, err := w.writeResponseData(resp, fileName, fileName, func() error {
+		file, err := w.getResponseFile(URL)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return file.Write(data)
+	})
+	if err != nil {
+		return err
+	}
+	return w.updateIndex(resp, canonical)
+}
+
+// writeHAREntry buffers the request/response pair as a HAR entry
+func (w *StandardWriter) writeHAREntry(event *Result, resp *http.Response) {
+	entry := newHAREntry(event, resp)
+
+	w.outputMutex.Lock()
+	w.harEntries = append(w.harEntries, entry)
+	w.outputMutex.Unlock()
+}
+
+// writeHAR writes the HAR document with the buffered entries to file and/or screen
+func (w *StandardWriter) writeHAR() error {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	data, err := w.formatHAR()
+	if err != nil {
+		return errors.Wrap(err, "could not format har output")
+	}
+	w.writeScreen(data)
+	if w.outputFile != nil {
+		if writeErr := w.outputFile.Write(data); writeErr != nil {
+			return errors.Wrap(writeErr, "could not write to output")
+		}
+	}
+	return nil
+}
+
+// updateResultFromResponse updates the result with the response details
+func updateResultFromResponse(event *Result, resp *http.Response) {
+	event.StatusCode = resp.StatusCode
+	event.Proto = resp.Proto
+	event.ContentLength = resp.ContentLength
+	if event.ContentLength < 0 {
+		event.ContentLength = int64(len(readResponseBody(resp)))
+	}
+	event.ContentType = getMediaType(resp.Header.Get("Content-Type"))
+	event.Redirects, event.RedirectLoop = getRedirects(resp)
+	if isHTMLContentType(event.ContentType) {
+		event.Title = getHTMLTitle(readResponseBody(resp))
+	}
+}
+
+// sniffContentTypeSize is the number of body bytes the content-type is
+// sniffed from, which is the maximum used by http.DetectContentType
+const sniffContentTypeSize = 512
+
+// sniffResultContentType updates the content-type of the result with the
+// content-type detected from the response body, and the title for the
+// detected HTML responses.
+func sniffResultContentType(event *Result, resp *http.Response) {
+	body := readResponseBody(resp)
+	if len(body) == 0 {
+		return
+	}
+	if len(body) > sniffContentTypeSize {
+		body = body[:sniffContentTypeSize]
+	}
+	event.ContentType = getMediaType(http.DetectContentType(body))
+	if isHTMLContentType(event.ContentType) {
+		event.Title = getHTMLTitle(readResponseBody(resp))
+	}
+}
+
+// hashBody returns the hex encoded hash of the body for the algorithm
+func hashBody(algorithm string, body []byte) string {
+	var hasher hash.Hash
+	switch algorithm {
+	case HashAlgorithmMD5:
+		hasher = md5.New()
+	case HashAlgorithmSHA1:
+		hasher = sha1.New()
+	default:
+		hasher = sha256.New()
+	}
+	_, _ = hasher.Write(body)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// maxRedirects is the maximum number of redirects kept for a result
+const maxRedirects = 20
+
+// getRedirects returns the URLs redirected to for a response by walking
+// back the responses which caused the redirected requests.
+//
+// The chain is truncated and flagged as a loop when a URL is repeated
+// or when there are more than the maximum number of redirects.
+func getRedirects(resp *http.Response) ([]string, bool) {
+	req := resp.Request
+	if req == nil || req.Response == nil {
+		return nil, false
+	}
+	var chain []string
+	var loop bool
+	for req != nil {
+		if len(chain) > maxRedirects {
+			loop = true
+			break
+		}
+		chain = append(chain, req.URL.String())
+		if req.Response == nil {
+			break
+		}
+		req = req.Response.Request
+	}
+	// reverse the chain to start from the original request
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+
+	seen := make(map[string]struct{}, len(chain))
+	for i, URL := range chain {
+		if _, ok := seen[URL]; ok {
+			chain = chain[:i+1]
+			loop = true
+			break
+		}
+		seen[URL] = struct{}{}
+	}
+	return chain[1:], loop
+}
+
+// getResponseHeaders returns the captured headers from the response headers
+func (w *StandardWriter) getResponseHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := make(map[string]string)
+	for name, values := range header {
+		if w.captureHeaders != nil {
+			if _, ok := w.captureHeaders[name]; !ok {
+				continue
 			}
-			file.Close()
 		}
+		headers[name] = strings.Join(values, "; ")
 	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// getRequestHeaders returns the headers sent in the request including
+// the host header, which is not part of the request header map.
+func getRequestHeaders(req *http.Request) map[string]string {
+	headers := make(map[string]string, len(req.Header)+1)
+	for name, values := range req.Header {
+		headers[name] = strings.Join(values, "; ")
+	}
+	if req.Host != "" {
+		headers["Host"] = req.Host
+	} else if req.URL != nil && req.URL.Host != "" {
+		headers["Host"] = req.URL.Host
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
+
+// newHeaderSet returns a lookup set of canonical header names
+func newHeaderSet(headers []string) map[string]struct{} {
+	if len(headers) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(headers))
+	for _, header := range headers {
+		set[http.CanonicalHeaderKey(strings.TrimSpace(header))] = struct{}{}
+	}
+	return set
+}
 
+// writeCSVHeader writes the csv or tsv header row once to screen and file.
+//
+// It must be called with the output mutex held.
+func (w *StandardWriter) writeCSVHeader() error {
+	header, err := w.formatCSVFileHeader()
+	if err != nil {
+		return err
+	}
+	w.csvHeader = true
+
+	w.writeScreen(header)
+	if w.outputFile != nil {
+		return w.outputFile.Write(header)
+	}
 	return nil
 }
 
-// Close closes the output writer
+// Flush flushes the buffered output file data to disk.
+//
+// The response index is written on every stored response and
+// requires no flushing.
+func (w *StandardWriter) Flush() error {
+	for _, sink := range w.sinks {
+		if err := sink.Flush(); err != nil {
+			return err
+		}
+	}
+	for _, resultWriter := range w.resultWriters {
+		if err := resultWriter.Flush(); err != nil {
+			return err
+		}
+	}
+	if w.queue != nil {
+		if err := w.queue.flush(); err != nil {
+			return err
+		}
+	}
+	if w.tabular {
+		w.outputMutex.Lock()
+		err := w.flushTabularRows()
+		w.outputMutex.Unlock()
+		if err != nil {
+			return errors.Wrap(err, "could not write to output")
+		}
+	}
+	if w.split != nil {
+		if err := w.split.flush(); err != nil {
+			return err
+		}
+	}
+	if w.jsOutput != nil {
+		if err := w.jsOutput.Flush(); err != nil {
+			return err
+		}
+	}
+	if w.outputFile != nil {
+		return w.outputFile.Flush()
+	}
+	return nil
+}
+
+// Close closes the output writer.
+//
+// It is safe to call Close multiple times, the later calls return the
+// error of the first call without closing the writer again.
 func (w *StandardWriter) Close() error {
+	w.closeOnce.Do(func() {
+		w.closeErr = w.close()
+	})
+	return w.closeErr
+}
+
+// close writes the buffered output and closes the output files
+func (w *StandardWriter) close() error {
 	var err error
+	if w.queue != nil {
+		err = w.queue.close()
+	}
+	if w.progress != nil {
+		w.progress.close()
+	}
+	if w.har {
+		err = multierr.Append(err, w.writeHAR())
+	}
+	if w.dedupByBody {
+		err = multierr.Append(err, w.writeBodyResults())
+	}
+	if w.sortOutput {
+		err = multierr.Append(err, w.writeSortedResults())
+	}
+	if w.paramsOnly {
+		err = multierr.Append(err, w.writeParams())
+	}
+	if w.jsonArray {
+		err = multierr.Append(err, w.closeJSONArray())
+	}
+	if w.tabular {
+		w.outputMutex.Lock()
+		err = multierr.Append(err, w.flushTabularRows())
+		w.outputMutex.Unlock()
+	}
+	if w.summary != nil {
+		err = multierr.Append(err, w.writeSummary())
+	}
+	if w.graph != nil {
+		err = multierr.Append(err, w.writeGraph())
+	}
 	if w.outputFile != nil {
-		err = w.outputFile.Close()
+		err = multierr.Append(err, w.outputFile.Close())
+	}
+	if w.split != nil {
+		err = multierr.Append(err, w.split.close())
+	}
+	if w.jsOutput != nil {
+		err = multierr.Append(err, w.jsOutput.Close())
+	}
+	for _, sink := range w.sinks {
+		err = multierr.Append(err, sink.Close())
+	}
+	for _, resultWriter := range w.resultWriters {
+		err = multierr.Append(err, resultWriter.Close())
+	}
+	if w.baseline != nil {
+		err = multierr.Append(err, w.baseline.write())
+	}
+	if w.bundleFile != "" {
+		err = multierr.Append(err, w.writeBundle())
+	}
+	if w.manifestPath != "" {
+		err = multierr.Append(err, w.writeManifest())
 	}
 	return err
 }
@@ -0,0 +1,135 @@
+package output
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// outputQueueItemsPerWriter is the number of queued results per
+// concurrent writer of the output queue
+const outputQueueItemsPerWriter = 64
+
+// errOutputClosed is returned by the writes and flushes of a closed
+// output writer
+var errOutputClosed = errors.New("output writer is closed")
+
+// outputQueue is a channel-backed queue of formatted results which are
+// written to screen and file by a single goroutine, so that concurrent
+// writers hand off their results without contending on the output mutex.
+type outputQueue struct {
+	writer *StandardWriter
+	items  chan outputItem
+	done   chan struct{}
+	closed bool
+	mutex  *sync.RWMutex
+
+	// errMutex protects the first write error, which is returned
+	// by the next flush or close
+	errMutex *sync.Mutex
+	err      error
+}
+
+// outputItem is a queued result, or a flush request if flushed is set
+type outputItem struct {
+	data     []byte
+	fileData []byte
+	splitKey string
+	flushed  chan struct{}
+}
+
+// newOutputQueue creates a queue for the concurrency hint and starts
+// writing the queued results with the writer
+func newOutputQueue(writer *StandardWriter, concurrency int) *outputQueue {
+	queue := &outputQueue{
+		writer:   writer,
+		items:    make(chan outputItem, concurrency*outputQueueItemsPerWriter),
+		done:     make(chan struct{}),
+		mutex:    &sync.RWMutex{},
+		errMutex: &sync.Mutex{},
+	}
+	go queue.run()
+	return queue
+}
+
+// push queues the formatted data of a result, returning the context error
+// if the context is done while the queue is full
+func (q *outputQueue) push(ctx context.Context, data, fileData []byte, splitKey string) error {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.closed {
+		return errOutputClosed
+	}
+	select {
+	case q.items <- outputItem{data: data, fileData: fileData, splitKey: splitKey}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run writes the queued results until the queue is closed
+func (q *outputQueue) run() {
+	defer close(q.done)
+
+	for item := range q.items {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		q.writer.outputMutex.Lock()
+		err := q.writer.writeData(item.data, item.fileData, item.splitKey)
+		q.writer.outputMutex.Unlock()
+
+		if err != nil {
+			q.errMutex.Lock()
+			if q.err == nil {
+				q.err = err
+			}
+			q.errMutex.Unlock()
+		}
+	}
+}
+
+// flush waits until the queued results are written, returning the first
+// write error since the last flush
+func (q *outputQueue) flush() error {
+	q.mutex.RLock()
+	defer q.mutex.RUnlock()
+
+	if q.closed {
+		return errOutputClosed
+	}
+	flushed := make(chan struct{})
+	q.items <- outputItem{flushed: flushed}
+	<-flushed
+	return q.getError()
+}
+
+// close writes the queued results and stops the queue, returning the
+// first write error since the last flush
+func (q *outputQueue) close() error {
+	q.mutex.Lock()
+	if q.closed {
+		q.mutex.Unlock()
+		return nil
+	}
+	q.closed = true
+	close(q.items)
+	q.mutex.Unlock()
+
+	<-q.done
+	return q.getError()
+}
+
+// getError returns and resets the first write error
+func (q *outputQueue) getError() error {
+	q.errMutex.Lock()
+	defer q.errMutex.Unlock()
+
+	err := q.err
+	q.err = nil
+	return err
+}
@@ -0,0 +1,585 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/levels"
+	"github.com/projectdiscovery/gologger/writer"
+	"github.com/stretchr/testify/require"
+)
+
+// screenWriter is a gologger writer which records the screen output
+type screenWriter struct {
+	data []string
+}
+
+func (w *screenWriter) Write(data []byte, _ levels.Level) {
+	w.data = append(w.data, string(data))
+}
+
+func TestNewWithOptionsDefaults(t *testing.T) {
+	writer, err := NewWithOptions(&Options{})
+	require.Nil(t, err, "could not create writer with zero options")
+	defer writer.Close()
+
+	standard, ok := writer.(*StandardWriter)
+	require.True(t, ok, "could not get standard writer")
+	require.False(t, standard.json || standard.jsonl || standard.csv, "got non-screen format for zero options")
+	require.Nil(t, standard.outputFile, "got output file for zero options")
+	require.False(t, standard.storeResponse, "got store response for zero options")
+
+	data, err := standard.formatScreen(&Result{URL: "https://example.com/", Tag: "a"})
+	require.Nil(t, err, "could not format screen output")
+	require.Equal(t, "https://example.com/", string(data), "got colored or verbose zero options output")
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+}
+
+func TestUpdateResultFromResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode:    200,
+		Proto:         "HTTP/2.0",
+		ContentLength: -1,
+		Header:        http.Header{},
+		Body:          io.NopCloser(strings.NewReader("chunked body")),
+	}
+	result := &Result{}
+	updateResultFromResponse(result, resp)
+	require.Equal(t, 200, result.StatusCode, "could not get status code")
+	require.Equal(t, int64(len("chunked body")), result.ContentLength, "could not get content length from body")
+	require.Equal(t, "HTTP/2.0", result.Proto, "could not get protocol")
+
+	body, _ := io.ReadAll(resp.Body)
+	require.Equal(t, "chunked body", string(body), "could not read body again")
+}
+
+func TestSniffContentType(t *testing.T) {
+	writer, err := NewWithOptions(&Options{SniffContentType: true, MatchContentTypes: []string{"text/html"}, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("<!DOCTYPE html><html><title>Sniffed</title></html>"))}
+	result := &Result{URL: "https://example.com/"}
+	require.Nil(t, writer.Write(result, resp), "could not write result")
+	require.Equal(t, "text/html", result.ContentType, "could not sniff content type")
+	require.Equal(t, "Sniffed", result.Title, "could not get title of sniffed html")
+
+	resp = &http.Response{StatusCode: 200, Header: http.Header{"Content-Type": []string{"application/json"}}, Body: io.NopCloser(strings.NewReader("<html></html>"))}
+	result = &Result{URL: "https://example.com/api"}
+	require.Nil(t, writer.Write(result, resp), "could not write result")
+	require.Equal(t, "application/json", result.ContentType, "could not keep content type header")
+
+	resp = &http.Response{StatusCode: 204, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}
+	result = &Result{URL: "https://example.com/empty"}
+	require.Nil(t, writer.Write(result, resp), "could not write result")
+	require.Empty(t, result.ContentType, "could not skip sniffing empty body")
+}
+
+func TestColorSchemeWithoutColors(t *testing.T) {
+	writer, err := NewWithOptions(&Options{ColorScheme: ColorSchemeSource, Verbose: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	data, err := writer.(*StandardWriter).formatScreen(&Result{URL: "https://example.com/", Tag: "a", Attribute: "href"})
+	require.Nil(t, err, "could not format screen output")
+	require.Equal(t, "[a] https://example.com/", string(data), "could not ignore color scheme without colors")
+
+	_, err = NewWithOptions(&Options{ColorScheme: "invalid"})
+	require.Error(t, err, "got no error for invalid color scheme")
+}
+
+func TestFormatScreenForm(t *testing.T) {
+	writer, err := NewWithOptions(&Options{Verbose: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	result := &Result{
+		Method: "POST",
+		URL:    "https://example.com/login",
+		Tag:    "form",
+		Form:   &Form{Action: "https://example.com/login", Method: "POST", Inputs: []string{"user", "pass"}},
+	}
+	data, err := writer.(*StandardWriter).formatScreen(result)
+	require.Nil(t, err, "could not format screen output")
+	require.Equal(t, "[form] [POST] https://example.com/login [form POST https://example.com/login user,pass]", string(data), "could not get form summary")
+}
+
+func TestFormatScreenError(t *testing.T) {
+	writer, err := NewWithOptions(&Options{Colors: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	result := &Result{URL: "https://example.com/", StatusCode: 200, Error: "connection refused"}
+	data, err := writer.(*StandardWriter).formatScreen(result)
+	require.Nil(t, err, "could not format screen output")
+	require.Equal(t, "\x1b[31mhttps://example.com/\x1b[0m [\x1b[31mconnection refused\x1b[0m]", string(data), "could not get red error output")
+}
+
+func TestNoColorEnvironment(t *testing.T) {
+	result := &Result{URL: "https://example.com/", Tag: "a", Attribute: "href", StatusCode: 200}
+
+	os.Setenv("NO_COLOR", "1")
+	writer, err := NewWithOptions(&Options{Colors: true, Verbose: true})
+	os.Unsetenv("NO_COLOR")
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	data, err := writer.(*StandardWriter).formatScreen(result)
+	require.Nil(t, err, "could not format screen output")
+	require.False(t, decolorizerRegex.Match(data), "got colors with NO_COLOR set")
+
+	writer, err = NewWithOptions(&Options{Colors: true, Verbose: true})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	data, err = writer.(*StandardWriter).formatScreen(result)
+	require.Nil(t, err, "could not format screen output")
+	require.True(t, decolorizerRegex.Match(data), "got no colors with NO_COLOR unset")
+}
+
+func TestPreserveFileColor(t *testing.T) {
+	for _, preserve := range []bool{false, true} {
+		file := filepath.Join(t.TempDir(), "output.txt")
+
+		writer, err := NewWithOptions(&Options{Colors: true, Verbose: true, OutputFile: file, Silent: true, PreserveFileColor: preserve})
+		require.Nil(t, err, "could not create writer")
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/", Tag: "a"}, nil), "could not write result")
+		require.Nil(t, writer.Close(), "could not close writer")
+
+		data, err := os.ReadFile(file)
+		require.Nil(t, err, "could not read output")
+		require.Equal(t, preserve, decolorizerRegex.Match(data), "could not get expected file colors")
+	}
+}
+
+func TestStandardWriterFlush(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := NewWithOptions(&Options{OutputFile: file})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Empty(t, data, "got unbuffered output before flush")
+
+	require.Nil(t, writer.Flush(), "could not flush writer")
+	data, err = os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "https://example.com/\n", string(data), "could not get flushed output")
+}
+
+func TestSilentWriter(t *testing.T) {
+	screen := &screenWriter{}
+	gologger.DefaultLogger.SetWriter(screen)
+	defer gologger.DefaultLogger.SetWriter(writer.NewCLI())
+
+	file := filepath.Join(t.TempDir(), "output.txt")
+	for _, silent := range []bool{false, true} {
+		screen.data = nil
+
+		standardWriter, err := NewWithOptions(&Options{OutputFile: file, Silent: silent})
+		require.Nil(t, err, "could not create writer")
+		require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+		require.Nil(t, standardWriter.Close(), "could not close writer")
+
+		data, err := os.ReadFile(file)
+		require.Nil(t, err, "could not read output")
+		require.Equal(t, "https://example.com/\n", string(data), "could not write output file")
+		if silent {
+			require.Empty(t, screen.data, "got screen output for silent writer")
+		} else {
+			require.Equal(t, []string{"https://example.com/"}, screen.data, "could not write screen output")
+		}
+	}
+}
+
+func TestWriteContext(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.txt")
+
+	writer, err := NewWithOptions(&Options{OutputFile: file})
+	require.Nil(t, err, "could not create writer")
+
+	contextWriter, ok := writer.(ContextWriter)
+	require.True(t, ok, "could not get context writer")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.Nil(t, contextWriter.WriteContext(ctx, &Result{URL: "https://example.com/a"}, nil), "could not write result")
+	cancel()
+	err = contextWriter.WriteContext(ctx, &Result{URL: "https://example.com/b"}, nil)
+	require.Equal(t, context.Canceled, err, "could not get context error")
+
+	require.Nil(t, writer.Close(), "could not close writer")
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "https://example.com/a\n", string(data), "could not skip cancelled write")
+}
+
+func TestOnResultCallback(t *testing.T) {
+	var results []string
+	writer, err := NewWithOptions(&Options{
+		FilterRegex: []string{"logout"},
+		OnResult: func(result *Result) {
+			if result.Tag == "panic" {
+				panic("callback failed")
+			}
+			results = append(results, result.URL)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/logout"}, nil), "could not write filtered result")
+	require.NotPanics(t, func() {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/a", Tag: "panic"}, nil), "could not write result")
+	}, "could not recover from callback panic")
+	require.Equal(t, []string{"https://example.com/"}, results, "could not get callback results")
+}
+
+func TestTransformers(t *testing.T) {
+	var results []string
+	writer, err := NewWithOptions(&Options{
+		FilterRegex: []string{"LOGOUT"},
+		Transformers: []func(*Result) *Result{
+			func(result *Result) *Result {
+				if result.Tag == "drop" {
+					return nil
+				}
+				if result.Tag == "panic" {
+					panic("transformer failed")
+				}
+				result.URL = strings.SplitN(result.URL, "?", 2)[0]
+				return result
+			},
+			func(result *Result) *Result {
+				return &Result{URL: strings.ToUpper(result.URL)}
+			},
+		},
+		OnResult: func(result *Result) {
+			results = append(results, result.URL)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/?a=1"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/b", Tag: "drop"}, nil), "could not drop result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/logout?a=1"}, nil), "could not filter transformed result")
+	require.NotPanics(t, func() {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/c", Tag: "panic"}, nil), "could not drop result")
+	}, "could not recover from transformer panic")
+	require.Equal(t, []string{"HTTPS://EXAMPLE.COM/"}, results, "could not transform results in order")
+}
+
+func TestSequenceIDs(t *testing.T) {
+	var seqs []int64
+	writer, err := NewWithOptions(&Options{
+		FilterRegex: []string{"logout"},
+		SequenceIDs: true,
+		OnResult: func(result *Result) {
+			seqs = append(seqs, result.Seq)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	mock := &mockWriter{}
+	multi := MultiWriter(writer, mock)
+	defer multi.Close()
+
+	for _, url := range []string{"https://example.com/a", "https://example.com/logout", "https://example.com/b"} {
+		require.Nil(t, multi.Write(&Result{URL: url}, nil), "could not write result")
+	}
+	require.Equal(t, []int64{1, 2}, seqs, "could not assign sequence ids to written results")
+	require.Equal(t, int64(1), mock.results[0].Seq, "could not share sequence id across writers")
+	require.Equal(t, int64(0), mock.results[1].Seq, "could not skip sequence id of filtered result")
+	require.Equal(t, int64(2), mock.results[2].Seq, "could not share sequence id across writers")
+}
+
+func TestCaptureResponseHeaders(t *testing.T) {
+	header := http.Header{
+		"Server":     []string{"nginx"},
+		"Set-Cookie": []string{"a=1", "b=2"},
+		"Date":       []string{"Mon, 01 Jan 2024 00:00:00 GMT"},
+	}
+
+	w := &StandardWriter{}
+	require.Len(t, w.getResponseHeaders(header), 3, "could not capture all headers")
+
+	w = &StandardWriter{captureHeaders: newHeaderSet([]string{"server", "SET-COOKIE", "X-Powered-By"})}
+	require.Equal(t, map[string]string{"Server": "nginx", "Set-Cookie": "a=1; b=2"}, w.getResponseHeaders(header), "could not capture allowed headers")
+
+	w = &StandardWriter{captureHeaders: newHeaderSet([]string{"X-Powered-By"})}
+	require.Nil(t, w.getResponseHeaders(header), "got headers without allowed headers")
+}
+
+func TestCaptureRequestHeaders(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	require.Nil(t, err, "could not create request")
+	req.Header.Set("User-Agent", "katana")
+	req.Header.Add("Cookie", "a=1")
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("")), Request: req}
+	for _, capture := range []bool{false, true} {
+		writer, err := NewWithOptions(&Options{CaptureRequestHeaders: capture, JSONL: true})
+		require.Nil(t, err, "could not create writer")
+
+		result := &Result{URL: "https://example.com/"}
+		require.Nil(t, writer.Write(result, resp), "could not write result")
+		if capture {
+			require.Equal(t, map[string]string{"User-Agent": "katana", "Cookie": "a=1", "Host": "example.com"}, result.RequestHeaders, "could not capture request headers")
+		} else {
+			require.Nil(t, result.RequestHeaders, "got request headers without capture")
+		}
+		writer.Close()
+	}
+}
+
+func TestGetRedirects(t *testing.T) {
+	newRedirect := func(URLs ...string) *http.Response {
+		var previous *http.Response
+		for _, URL := range URLs {
+			req, _ := http.NewRequest(http.MethodGet, URL, nil)
+			req.Response = previous
+			previous = &http.Response{StatusCode: 302, Request: req}
+		}
+		previous.StatusCode = 200
+		return previous
+	}
+
+	redirects, loop := getRedirects(newRedirect("https://example.com/"))
+	require.Nil(t, redirects, "got redirects without redirect")
+	require.False(t, loop, "got loop without redirect")
+
+	redirects, loop = getRedirects(newRedirect("https://example.com/a", "https://example.com/b", "https://example.com/c"))
+	require.Equal(t, []string{"https://example.com/b", "https://example.com/c"}, redirects, "could not get redirects")
+	require.False(t, loop, "got loop for redirects")
+
+	redirects, loop = getRedirects(newRedirect("https://example.com/a", "https://example.com/b", "https://example.com/a", "https://example.com/b"))
+	require.Equal(t, []string{"https://example.com/b", "https://example.com/a"}, redirects, "could not truncate redirect loop")
+	require.True(t, loop, "could not flag redirect loop")
+
+	URLs := make([]string, 0, maxRedirects+5)
+	for i := 0; i < maxRedirects+5; i++ {
+		URLs = append(URLs, "https://example.com/"+strconv.Itoa(i))
+	}
+	redirects, loop = getRedirects(newRedirect(URLs...))
+	require.Len(t, redirects, maxRedirects, "could not truncate redirects")
+	require.True(t, loop, "could not flag too many redirects")
+}
+
+func TestBodyHash(t *testing.T) {
+	require.Equal(t, "2e99758548972a8e8822ad47fa1017ff72f06f3ff6a016851f45c398732bc50c", hashBody(HashAlgorithmSHA256, []byte("this is a test")), "could not get sha256 hash")
+	require.Equal(t, "fa26be19de6bff93f70bc2308434e4a440bbad02", hashBody(HashAlgorithmSHA1, []byte("this is a test")), "could not get sha1 hash")
+	require.Equal(t, "54b0c58c7ce9f2a8b551351102ee0938", hashBody(HashAlgorithmMD5, []byte("this is a test")), "could not get md5 hash")
+
+	writer, err := NewWithOptions(&Options{})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("this is a test"))}
+	result := &Result{URL: "https://example.com/"}
+	require.Nil(t, writer.Write(result, resp), "could not write result")
+	require.Equal(t, hashBody(HashAlgorithmSHA256, []byte("this is a test")), result.BodyHash, "could not hash body with default algorithm")
+
+	_, err = NewWithOptions(&Options{HashAlgorithm: "crc32"})
+	require.Error(t, err, "got no error for invalid hash algorithm")
+}
+
+func TestCompressedOutputFile(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, options := range []*Options{
+		{OutputFile: filepath.Join(dir, "output.jsonl.gz"), JSONL: true, Silent: true},
+		{OutputFile: filepath.Join(dir, "output.txt"), CompressOutput: true, Colors: true, Silent: true},
+	} {
+		standardWriter, err := NewWithOptions(options)
+		require.Nil(t, err, "could not create writer")
+		// write more than the buffer size so that data is compressed
+		// before Close
+		for i := 0; i < 1000; i++ {
+			require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/" + strconv.Itoa(i)}, nil), "could not write result")
+		}
+		require.Nil(t, standardWriter.Close(), "could not close writer")
+
+		file, err := os.Open(options.OutputFile)
+		require.Nil(t, err, "could not open output file")
+		reader, err := gzip.NewReader(file)
+		require.Nil(t, err, "could not create gzip reader")
+
+		var lines []string
+		scanner := bufio.NewScanner(reader)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		require.Nil(t, scanner.Err(), "could not read truncated output file")
+		file.Close()
+
+		require.Len(t, lines, 1000, "could not read all results")
+		if options.JSONL {
+			require.Equal(t, `{"endpoint":"https://example.com/999"}`, lines[999], "could not read jsonl result")
+		} else {
+			require.Equal(t, "https://example.com/999", lines[999], "could not read decolorized result")
+		}
+	}
+}
+
+func TestConcurrentWriter(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.json")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: file, JSONArray: true, Silent: true, Concurrency: 4})
+	require.Nil(t, err, "could not create writer")
+	require.NotNil(t, standardWriter.(*StandardWriter).queue, "could not create output queue")
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 250; j++ {
+				_ = standardWriter.Write(&Result{URL: "https://example.com/" + strconv.Itoa(i*250+j)}, nil)
+			}
+		}(i)
+	}
+	wg.Wait()
+	require.Nil(t, standardWriter.Flush(), "could not flush writer")
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output file")
+	var results []Result
+	require.Nil(t, jsoniter.Unmarshal(data, &results), "could not decode json array")
+	require.Len(t, results, 1000, "could not write all results")
+}
+
+func TestConcurrentWriterContext(t *testing.T) {
+	standardWriter, err := NewWithOptions(&Options{Silent: true, Concurrency: 2})
+	require.Nil(t, err, "could not create writer")
+	defer standardWriter.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, standardWriter.(ContextWriter).WriteContext(ctx, &Result{URL: "https://example.com/"}, nil), context.Canceled, "could not get context error")
+}
+
+func TestConcurrentWriterClosed(t *testing.T) {
+	standardWriter, err := NewWithOptions(&Options{OutputFile: filepath.Join(t.TempDir(), "output.txt"), Silent: true, Concurrency: 2})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	require.NotNil(t, standardWriter.Write(&Result{URL: "https://example.com/a"}, nil), "could not fail write after close")
+	require.NotNil(t, standardWriter.(ContextWriter).WriteContext(context.Background(), &Result{URL: "https://example.com/b"}, nil), "could not fail write after close")
+	require.NotNil(t, standardWriter.Flush(), "could not fail flush after close")
+	require.Nil(t, standardWriter.Close(), "could not close writer twice")
+}
+
+func BenchmarkWriteJSON(b *testing.B) {
+	for _, concurrency := range []int{0, 8} {
+		b.Run("concurrency-"+strconv.Itoa(concurrency), func(b *testing.B) {
+			standardWriter, err := NewWithOptions(&Options{OutputFile: filepath.Join(b.TempDir(), "output.json"), JSONL: true, Silent: true, Concurrency: concurrency})
+			require.Nil(b, err, "could not create writer")
+
+			result := &Result{URL: "https://example.com/a?b=c", Source: "https://example.com/", Tag: "a", Attribute: "href", StatusCode: 200, ResponseHeaders: map[string]string{"Server": "nginx", "Content-Type": "text/html"}}
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_ = standardWriter.Write(result, nil)
+				}
+			})
+			require.Nil(b, standardWriter.Close(), "could not close writer")
+		})
+	}
+}
+
+func TestCloseTwice(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.json")
+
+	writer, err := NewWithOptions(&Options{OutputFile: file, JSONArray: true, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Nil(t, writer.Close(), "could not close writer twice")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "[{\"endpoint\":\"https://example.com/\"}\n]\n", string(data), "could not close json array once")
+
+	writer, err = NewWithOptions(&Options{OutputFile: filepath.Join(t.TempDir(), "output.txt"), Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	// closing the underlying file makes the flush of the buffered output fail
+	require.Nil(t, writer.(*StandardWriter).outputFile.file.Close(), "could not close underlying file")
+	closeErr := writer.Close()
+	require.NotNil(t, closeErr, "could not get close error")
+	require.Equal(t, closeErr, writer.Close(), "could not get first close error again")
+}
+
+func TestOutputWriter(t *testing.T) {
+	buffer := &bytes.Buffer{}
+
+	writer, err := NewWithOptions(&Options{OutputWriter: buffer, OutputFile: filepath.Join(t.TempDir(), "ignored.txt"), JSONL: true, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Equal(t, "{\"endpoint\":\"https://example.com/\"}\n", buffer.String(), "could not write through to output writer")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	buffer.Reset()
+	writer, err = NewWithOptions(&Options{OutputWriter: buffer, CompressOutput: true, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	reader, err := gzip.NewReader(buffer)
+	require.Nil(t, err, "could not create gzip reader")
+	data, err := io.ReadAll(reader)
+	require.Nil(t, err, "could not read compressed output")
+	require.Equal(t, "https://example.com/\n", string(data), "could not write compressed output")
+}
+
+func TestResultWriters(t *testing.T) {
+	resultWriter := NewMemoryWriter()
+	writer, err := NewWithOptions(&Options{
+		Silent:        true,
+		FilterRegex:   []string{"logout"},
+		Dedup:         true,
+		DedupKey:      DedupKeyURL,
+		ResultWriters: []Writer{resultWriter},
+	})
+	require.Nil(t, err, "could not create writer")
+
+	for _, URL := range []string{"https://example.com/", "https://example.com/logout", "https://example.com/", "https://example.com/app.js"} {
+		require.Nil(t, writer.Write(&Result{URL: URL}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	var urls []string
+	for _, result := range resultWriter.Results() {
+		urls = append(urls, result.URL)
+	}
+	require.Equal(t, []string{"https://example.com/", "https://example.com/app.js"}, urls, "could not write only the written results")
+}
+
+func TestResultWritersCountOnly(t *testing.T) {
+	resultWriter := NewMemoryWriter()
+	writer, err := NewWithOptions(&Options{CountOnly: true, ResultWriters: []Writer{resultWriter}})
+	require.Nil(t, err, "could not create writer")
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Empty(t, resultWriter.Results(), "could not skip result writers when counting")
+}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// getQueryParams returns the sorted unique names of the query parameters
+// of the URL, normalizing the array-style names like a[] and a[0] to a.
+func getQueryParams(rawURL string) []string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return nil
+	}
+	unique := make(map[string]struct{})
+	for _, pair := range strings.Split(parsed.RawQuery, "&") {
+		if index := strings.IndexByte(pair, '='); index != -1 {
+			pair = pair[:index]
+		}
+		name, err := url.QueryUnescape(pair)
+		if err != nil {
+			name = pair
+		}
+		if name = normalizeParamName(name); name != "" {
+			unique[name] = struct{}{}
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+	params := make([]string, 0, len(unique))
+	for name := range unique {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+	return params
+}
+
+// normalizeParamName strips the trailing array brackets of a parameter
+// name with an empty or numeric index, keeping the named keys like
+// user[name] as they are.
+func normalizeParamName(name string) string {
+	for strings.HasSuffix(name, "]") {
+		index := strings.LastIndexByte(name, '[')
+		if index <= 0 || strings.Trim(name[index+1:len(name)-1], "0123456789") != "" {
+			break
+		}
+		name = name[:index]
+	}
+	return name
+}
+
+// addParams adds the parameter names of the result to the written
+// parameters
+func (w *StandardWriter) addParams(event *Result) {
+	w.outputMutex.Lock()
+	for _, name := range event.Params {
+		w.params[name] = struct{}{}
+	}
+	w.outputMutex.Unlock()
+}
+
+// writeParams writes the sorted unique parameter names of all the
+// results, one per line, to file and/or screen
+func (w *StandardWriter) writeParams() error {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	params := make([]string, 0, len(w.params))
+	for name := range w.params {
+		params = append(params, name)
+	}
+	sort.Strings(params)
+
+	for _, name := range params {
+		w.writeScreen([]byte(name))
+		if w.outputFile != nil {
+			if err := w.outputFile.Write([]byte(name)); err != nil {
+				return errors.Wrap(err, "could not write to output")
+			}
+		}
+	}
+	return nil
+}
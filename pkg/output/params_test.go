@@ -0,0 +1,40 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetQueryParams(t *testing.T) {
+	require.Equal(t, []string{"a", "b", "ids", "user[name]"}, getQueryParams("https://example.com/?b=1&a=2&a=3&ids[]=1&ids[]=2&ids[0]=3&user%5Bname%5D=x"), "could not get query params")
+	require.Equal(t, []string{"flag"}, getQueryParams("https://example.com/?flag&="), "could not get params without values")
+	require.Nil(t, getQueryParams("https://example.com/path"), "could not get empty params")
+	require.Equal(t, "a", normalizeParamName("a[][]"), "could not normalize nested arrays")
+	require.Equal(t, "[]", normalizeParamName("[]"), "could not keep bracket only name")
+}
+
+func TestParamsOnly(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "params.txt")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: file, ParamsOnly: true, Silent: true, Concurrency: 4})
+	require.Nil(t, err, "could not create writer")
+	for _, url := range []string{
+		"https://example.com/search?q=1&page=2",
+		"https://example.com/list?ids[]=1&ids[]=2&page=3",
+		"https://example.com/",
+	} {
+		result := &Result{URL: url}
+		require.Nil(t, standardWriter.Write(result, nil), "could not write result")
+	}
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Empty(t, data, "could not skip writing results")
+
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+	data, err = os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, "ids\npage\nq\n", string(data), "could not write unique params")
+}
@@ -0,0 +1,4 @@
+// Package pb contains the protocol buffer messages for the katana output.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative result.proto
@@ -0,0 +1,101 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// progressInterval is the interval the progress line is updated at
+const progressInterval = time.Second
+
+// progress prints a progress line with the number of written results,
+// unique hosts and the write rate, updating it in place with a carriage
+// return at intervals.
+type progress struct {
+	// written is the number of written results, updated atomically. It
+	// is the first field to be 64-bit aligned on 32-bit platforms.
+	written int64
+
+	output   io.Writer
+	interval time.Duration
+	started  time.Time
+
+	mutex *sync.Mutex
+	hosts map[string]struct{}
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newProgress creates a progress printing to the output at the interval
+// until it is closed
+func newProgress(output io.Writer, interval time.Duration) *progress {
+	p := &progress{
+		output:   output,
+		interval: interval,
+		started:  time.Now(),
+		mutex:    &sync.Mutex{},
+		hosts:    make(map[string]struct{}),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// update counts the written result and its host
+func (p *progress) update(event *Result) {
+	atomic.AddInt64(&p.written, 1)
+	parsed, err := url.Parse(event.URL)
+	if err != nil || parsed.Host == "" {
+		return
+	}
+	p.mutex.Lock()
+	p.hosts[parsed.Host] = struct{}{}
+	p.mutex.Unlock()
+}
+
+// run prints the progress line with the rate since the previous line at
+// every interval until stopped
+func (p *progress) run() {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	last, lastTime := int64(0), p.started
+	for {
+		select {
+		case now := <-ticker.C:
+			written := atomic.LoadInt64(&p.written)
+			p.print(written, float64(written-last)/now.Sub(lastTime).Seconds(), "")
+			last, lastTime = written, now
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// print prints the progress line for the written results and rate
+func (p *progress) print(written int64, rate float64, end string) {
+	p.mutex.Lock()
+	hosts := len(p.hosts)
+	p.mutex.Unlock()
+
+	// the trailing spaces clear the rest of a longer previous line
+	fmt.Fprintf(p.output, "\rwritten: %d | unique hosts: %d | %.0f/s    %s", written, hosts, rate, end)
+}
+
+// close stops the updates and prints the final progress line with the
+// average rate of the crawl
+func (p *progress) close() {
+	close(p.stop)
+	<-p.done
+
+	written := atomic.LoadInt64(&p.written)
+	p.print(written, float64(written)/time.Since(p.started).Seconds(), "\n")
+}
@@ -0,0 +1,44 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgress(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	progress := newProgress(buffer, 10*time.Millisecond)
+	for _, result := range []*Result{
+		{URL: "https://example.com/"},
+		{URL: "https://example.com/login"},
+		{URL: "https://api.example.com/v1"},
+		{URL: "/relative"},
+	} {
+		progress.update(result)
+	}
+	time.Sleep(50 * time.Millisecond)
+	progress.close()
+
+	output := buffer.String()
+	require.True(t, strings.HasPrefix(output, "\r"), "could not update progress line in place")
+	require.True(t, strings.HasSuffix(output, "\n"), "could not end progress line on close")
+	lines := strings.Split(output, "\r")
+	require.Greater(t, len(lines), 2, "could not print periodic progress lines")
+	require.Contains(t, lines[len(lines)-1], "written: 4 | unique hosts: 2 |", "could not print final progress line")
+}
+
+func TestProgressWriter(t *testing.T) {
+	standardWriter, err := NewWithOptions(&Options{Progress: true, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	progress := standardWriter.(*StandardWriter).progress
+	require.NotNil(t, progress, "could not create progress")
+
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+	require.Nil(t, standardWriter.Close(), "could not close writer twice")
+	require.Equal(t, int64(1), progress.written, "could not count written result")
+}
@@ -0,0 +1,49 @@
+package output
+
+import "regexp"
+
+// redactedText is the text the redacted matches are replaced with
+const redactedText = "[REDACTED]"
+
+// redactBuiltinPatterns are the patterns of the common secrets redacted
+// with the builtin redaction patterns
+var redactBuiltinPatterns = []string{
+	// aws access key ids and secret access keys
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,
+	`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`,
+	// json web tokens
+	`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`,
+	// bearer tokens of the authorization headers
+	`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`,
+	// private keys in pem format
+	`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`,
+	// github, slack and google api tokens
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,
+	`\bxox[aboprs]-[A-Za-z0-9-]{10,}`,
+	`\bAIza[0-9A-Za-z_-]{35}\b`,
+}
+
+// compileRedactRegexes compiles the redaction patterns, appending the
+// builtin patterns if requested
+func compileRedactRegexes(patterns []string, builtins bool) ([]*regexp.Regexp, error) {
+	if builtins {
+		patterns = append(append([]string{}, patterns...), redactBuiltinPatterns...)
+	}
+	return compileRegexes(patterns)
+}
+
+// redact replaces the matches of the redaction regexes in the data
+func (w *StandardWriter) redact(data []byte) []byte {
+	for _, regex := range w.redactRegex {
+		data = regex.ReplaceAllLiteral(data, []byte(redactedText))
+	}
+	return data
+}
+
+// redactString replaces the matches of the redaction regexes in the value
+func (w *StandardWriter) redactString(value string) string {
+	for _, regex := range w.redactRegex {
+		value = regex.ReplaceAllLiteralString(value, redactedText)
+	}
+	return value
+}
@@ -0,0 +1,88 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// storedResponse is the first stored response with a body hash
+type storedResponse struct {
+	// file is the file the response data was written to
+	file string
+	// path is the path of the response in the index
+	path string
+}
+
+// writeResponseData writes the response data to the file with write.
+//
+// When deduplicating the stored responses, the file of a response with
+// the same body as an already stored response is linked to the file of
+// the first response instead, and the index path of the first response
+// is returned as the canonical path.
+func (w *StandardWriter) writeResponseData(resp *http.Response, fileName, indexPath string, write func() error) (string, error) {
+	if w.storedResponses == nil {
+		return "", write()
+	}
+	body := readResponseBody(resp)
+	if len(body) == 0 {
+		return "", write()
+	}
+	hash := hashBody(HashAlgorithmSHA256, body)
+
+	w.storedResponsesMutex.Lock()
+	defer w.storedResponsesMutex.Unlock()
+
+	// the file may be a link of a previous response of the URL, which
+	// must not be written through to the linked file
+	_ = os.Remove(fileName)
+	if stored, ok := w.storedResponses[hash]; ok && stored.file != fileName {
+		if err := linkResponseFile(stored.file, fileName); err != nil {
+			return "", errors.Wrap(err, "could not link stored response")
+		}
+		return stored.path, nil
+	}
+	if err := write(); err != nil {
+		return "", err
+	}
+	w.storedResponses[hash] = storedResponse{file: fileName, path: indexPath}
+	return "", nil
+}
+
+// linkResponseFile links the file to the target stored response file.
+//
+// A relative symlink is created, falling back to a hardlink and then to
+// a copy of the target on the filesystems which don't support them.
+func linkResponseFile(target, fileName string) error {
+	if relative, err := filepath.Rel(filepath.Dir(fileName), target); err == nil {
+		if err := os.Symlink(relative, fileName); err == nil {
+			return nil
+		}
+	}
+	if err := os.Link(target, fileName); err == nil {
+		return nil
+	}
+	return copyResponseFile(target, fileName)
+}
+
+// copyResponseFile copies the target stored response file to the file
+func copyResponseFile(target, fileName string) error {
+	source, err := os.Open(target)
+	if err != nil {
+		return err
+	}
+	defer source.Close()
+
+	file, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, source); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
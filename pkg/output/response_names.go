@@ -0,0 +1,180 @@
+package output
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// Naming modes for the stored response files
+const (
+	// ResponseFilenameDefault stores responses in a per-host directory
+	// named by the sha1 hash of the URL. The directory is named by the
+	// host and port of the URL, like example.com_8443.
+	ResponseFilenameDefault = "default"
+	// ResponseFilenameSHA1 stores responses in the store response
+	// directory named by the sha1 hash of the URL.
+	ResponseFilenameSHA1 = "sha1"
+	// ResponseFilenameHierarchical stores responses in a directory tree
+	// mirroring the host and path of the URL.
+	ResponseFilenameHierarchical = "hierarchical"
+)
+
+const (
+	// maxNameSegmentLength is the maximum length in bytes of a single
+	// hierarchical path segment, leaving room for suffixes and
+	// extensions within the common 255 byte filesystem limit.
+	maxNameSegmentLength = 200
+	// nameHashLength is the length of the hash suffixes for names
+	nameHashLength = 8
+	// indexName is the name used for URLs with a directory path
+	indexName = "index"
+)
+
+// responseNamer returns the paths of the stored response files for URLs
+type responseNamer struct {
+	dir  string
+	mode string
+
+	// mutex protects the names and urls used for detecting collisions
+	// of sanitized hierarchical names, which are kept for every stored
+	// URL for the lifetime of the writer.
+	mutex *sync.Mutex
+	names map[string]string
+	urls  map[string]string
+}
+
+// newResponseNamer creates a new response namer for a directory and mode
+func newResponseNamer(dir, mode string) (*responseNamer, error) {
+	switch mode {
+	case "":
+		mode = ResponseFilenameDefault
+	case ResponseFilenameDefault, ResponseFilenameSHA1, ResponseFilenameHierarchical:
+	default:
+		return nil, errors.Errorf("invalid response filename mode %s specified", mode)
+	}
+	return &responseNamer{
+		dir:   dir,
+		mode:  mode,
+		mutex: &sync.Mutex{},
+		names: make(map[string]string),
+		urls:  make(map[string]string),
+	}, nil
+}
+
+// getBasePath returns the path without extension for the stored
+// response of the URL. The same path is always returned for a URL.
+//
+// An error is returned if the path of a malicious URL would escape the
+// store response directory.
+func (n *responseNamer) getBasePath(URL string) (string, error) {
+	path, err := n.getPath(URL)
+	if err != nil {
+		return "", err
+	}
+	if err := confinePath(n.dir, path); err != nil {
+		return "", errors.Wrapf(err, "could not store response of %s", URL)
+	}
+	return path, nil
+}
+
+// getPath returns the path for the stored response of the URL in the mode
+func (n *responseNamer) getPath(URL string) (string, error) {
+	switch {
+	case n.mode == ResponseFilenameHierarchical:
+		return n.getHierarchicalPath(URL)
+	case n.mode == ResponseFilenameSHA1:
+		return filepath.Join(n.dir, getResponseHash(URL)), nil
+	default:
+		domain, err := getResponseHost(URL)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(n.dir, domain, getResponseHash(URL)), nil
+	}
+}
+
+// confinePath returns an error if the path is not inside the directory
+func confinePath(dir, path string) error {
+	relative, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	if relative == "." || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) || filepath.IsAbs(relative) {
+		return errors.Errorf("path %s escapes directory %s", path, dir)
+	}
+	return nil
+}
+
+// getHierarchicalPath returns the path mirroring the host and path of the
+// URL, appending a hash suffix if another URL already uses the same path.
+func (n *responseNamer) getHierarchicalPath(URL string) (string, error) {
+	parsed, err := url.Parse(URL)
+	if err != nil {
+		return "", err
+	}
+	if parsed.Host == "" {
+		return "", errors.Errorf("no host in url %s", URL)
+	}
+	segments := []string{n.dir, sanitizeNameSegment(parsed.Host)}
+
+	path := strings.Trim(parsed.EscapedPath(), "/")
+	var pathSegments []string
+	if path != "" {
+		for _, segment := range strings.Split(path, "/") {
+			if unescaped, err := url.PathUnescape(segment); err == nil {
+				segment = unescaped
+			}
+			pathSegments = append(pathSegments, sanitizeNameSegment(segment))
+		}
+	}
+	if len(pathSegments) == 0 || strings.HasSuffix(parsed.Path, "/") {
+		pathSegments = append(pathSegments, indexName)
+	}
+	if parsed.RawQuery != "" {
+		last := len(pathSegments) - 1
+		pathSegments[last] = pathSegments[last] + "_" + getResponseHash(parsed.RawQuery)[:nameHashLength]
+	}
+	name := filepath.Join(append(segments, pathSegments...)...)
+
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	if existing, ok := n.names[URL]; ok {
+		return existing, nil
+	}
+	if owner, ok := n.urls[name]; ok && owner != URL {
+		name = name + "_" + getResponseHash(URL)[:nameHashLength]
+	}
+	n.names[URL] = name
+	n.urls[name] = URL
+	return name, nil
+}
+
+// sanitizeNameSegment returns a segment safe to use as a file or directory
+// name, replacing reserved and control characters and truncating it to
+// the maximum segment length.
+func sanitizeNameSegment(segment string) string {
+	sanitized := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, segment)
+	if sanitized == "" || sanitized == "." || sanitized == ".." {
+		return "_"
+	}
+	if len(sanitized) > maxNameSegmentLength {
+		truncated := sanitized[:maxNameSegmentLength-nameHashLength-1]
+		for !utf8.ValidString(truncated) {
+			truncated = truncated[:len(truncated)-1]
+		}
+		sanitized = truncated + "_" + getResponseHash(segment)[:nameHashLength]
+	}
+	return sanitized
+}
@@ -0,0 +1,169 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseNamerModes(t *testing.T) {
+	URL := "https://example.com:8443/a/b.js?x=1"
+
+	namer, err := newResponseNamer("responses", "")
+	require.Nil(t, err, "could not create default namer")
+	name, err := namer.getBasePath(URL)
+	require.Nil(t, err, "could not get default name")
+	require.Equal(t, filepath.Join("responses", "example.com_8443", getResponseHash(URL)), name, "could not get default name")
+
+	namer, err = newResponseNamer("responses", ResponseFilenameSHA1)
+	require.Nil(t, err, "could not create sha1 namer")
+	name, err = namer.getBasePath(URL)
+	require.Nil(t, err, "could not get sha1 name")
+	require.Equal(t, filepath.Join("responses", getResponseHash(URL)), name, "could not get sha1 name")
+
+	namer, err = newResponseNamer("responses", ResponseFilenameHierarchical)
+	require.Nil(t, err, "could not create hierarchical namer")
+	name, err = namer.getBasePath(URL)
+	require.Nil(t, err, "could not get hierarchical name")
+	require.Equal(t, filepath.Join("responses", "example.com_8443", "a", "b.js_"+getResponseHash("x=1")[:nameHashLength]), name, "could not get hierarchical name")
+
+	name, err = namer.getBasePath("https://example.com/dir/")
+	require.Nil(t, err, "could not get hierarchical name")
+	require.Equal(t, filepath.Join("responses", "example.com", "dir", indexName), name, "could not get hierarchical directory name")
+
+	_, err = newResponseNamer("responses", "flat")
+	require.Error(t, err, "got no error for invalid mode")
+}
+
+func TestResponseNamerHierarchicalCollisions(t *testing.T) {
+	namer, err := newResponseNamer("responses", ResponseFilenameHierarchical)
+	require.Nil(t, err, "could not create hierarchical namer")
+
+	first, err := namer.getBasePath("https://example.com/a:b")
+	require.Nil(t, err, "could not get first name")
+	second, err := namer.getBasePath("https://example.com/a%3Fb")
+	require.Nil(t, err, "could not get second name")
+	require.Equal(t, filepath.Join("responses", "example.com", "a_b"), first, "could not sanitize first name")
+	require.NotEqual(t, first, second, "got colliding names for distinct urls")
+	require.True(t, strings.HasPrefix(second, first+"_"), "could not append hash suffix to colliding name")
+
+	again, err := namer.getBasePath("https://example.com/a%3Fb")
+	require.Nil(t, err, "could not get name again")
+	require.Equal(t, second, again, "could not get same name for same url")
+
+	traversal, err := namer.getBasePath("https://example.com/../../etc/passwd")
+	require.Nil(t, err, "could not get traversal name")
+	require.True(t, strings.HasPrefix(traversal, filepath.Join("responses", "example.com")+string(filepath.Separator)), "could escape store directory")
+}
+
+func TestResponseNamerUnicodeAndLongURLs(t *testing.T) {
+	namer, err := newResponseNamer("responses", ResponseFilenameHierarchical)
+	require.Nil(t, err, "could not create hierarchical namer")
+
+	name, err := namer.getBasePath("https://例子.测试/路径/文件.html")
+	require.Nil(t, err, "could not get unicode name")
+	require.Equal(t, filepath.Join("responses", "例子.测试", "路径", "文件.html"), name, "could not keep unicode name")
+
+	long := strings.Repeat("é", 300)
+	first, err := namer.getBasePath("https://example.com/" + long + "a")
+	require.Nil(t, err, "could not get long name")
+	second, err := namer.getBasePath("https://example.com/" + long + "b")
+	require.Nil(t, err, "could not get long name")
+	require.NotEqual(t, first, second, "got colliding names for long urls")
+
+	for _, name := range []string{first, second} {
+		segment := filepath.Base(name)
+		require.LessOrEqual(t, len(segment), maxNameSegmentLength, "could not truncate long segment")
+		require.True(t, utf8.ValidString(segment), "could not truncate at rune boundary")
+	}
+}
+
+func TestResponseNamerMaliciousURLs(t *testing.T) {
+	dir := filepath.Join("responses", "store")
+
+	for _, mode := range []string{ResponseFilenameDefault, ResponseFilenameSHA1, ResponseFilenameHierarchical} {
+		namer, err := newResponseNamer(dir, mode)
+		require.Nil(t, err, "could not create %s namer", mode)
+
+		for _, URL := range []string{
+			"https://example.com/../../../etc/passwd",
+			"https://example.com/%2e%2e/%2e%2e/etc/passwd",
+			"https://example.com/..%2f..%2fetc%2fpasswd",
+			"https://example.com/..%5c..%5cwindows",
+			"https://example.com/a/%2e%2e/%2e%2e/%2e%2e/b",
+		} {
+			name, err := namer.getBasePath(URL)
+			require.Nil(t, err, "could not get %s name for %s", mode, URL)
+			require.Nil(t, confinePath(dir, name), "could escape store directory in %s mode for %s", mode, URL)
+		}
+	}
+
+	namer, err := newResponseNamer(dir, ResponseFilenameDefault)
+	require.Nil(t, err, "could not create default namer")
+	for _, URL := range []string{"http://../x", "http://..:80/x", "http://./x"} {
+		_, err := namer.getBasePath(URL)
+		require.NotNil(t, err, "could not reject malicious host in %s", URL)
+	}
+
+	namer, err = newResponseNamer(dir, ResponseFilenameHierarchical)
+	require.Nil(t, err, "could not create hierarchical namer")
+	for _, URL := range []string{"http://../x", "http://..:80/x"} {
+		name, err := namer.getBasePath(URL)
+		require.Nil(t, err, "could not get name for %s", URL)
+		require.Nil(t, confinePath(dir, name), "could escape store directory for %s", URL)
+	}
+}
+
+func TestStoreResponseMaliciousURLs(t *testing.T) {
+	for _, split := range []bool{false, true} {
+		dir := t.TempDir()
+		writer, err := NewWithOptions(&Options{Silent: true, StoreResponse: true, StoreResponseDir: dir, SplitStoredResponses: split})
+		require.Nil(t, err, "could not create writer")
+
+		for _, URL := range []string{"http://../x", "http://..:80/x", "http://./x"} {
+			parsed, err := url.Parse(URL)
+			require.Nil(t, err, "could not parse %s", URL)
+			resp := &http.Response{StatusCode: 200, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("body")), Request: &http.Request{Method: http.MethodGet, URL: parsed, Header: http.Header{}}}
+			err = writer.Write(nil, resp)
+			require.NotNil(t, err, "could not reject malicious host in %s", URL)
+			require.Contains(t, err.Error(), URL, "could not get url in error for %s", URL)
+		}
+		require.Nil(t, writer.Close(), "could not close writer")
+
+		entries, err := os.ReadDir(dir)
+		require.Nil(t, err, "could not read store directory")
+		for _, entry := range entries {
+			require.True(t, entry.Name() == indexFile, "could store malicious response as %s", entry.Name())
+		}
+	}
+}
+
+func TestConfinePath(t *testing.T) {
+	require.Nil(t, confinePath("responses", filepath.Join("responses", "example.com", "a")), "could not confine path")
+	require.NotNil(t, confinePath("responses", filepath.Join("responses", "..", "a")), "could not reject escaping path")
+	require.NotNil(t, confinePath("responses", "responses"), "could not reject directory itself")
+	require.Nil(t, confinePath("responses", filepath.Join("responses", "..a")), "could not allow dotted name")
+}
+
+func TestResponseNamerHostDirectory(t *testing.T) {
+	namer, err := newResponseNamer("responses", ResponseFilenameDefault)
+	require.Nil(t, err, "could not create namer")
+
+	for URL, directory := range map[string]string{
+		"https://example.com:8443/a": "example.com_8443",
+		"https://example.com/a":      "example.com",
+		"http://[2001:db8::1]/":      "[2001_db8__1]",
+		"http://[2001:db8::1]:8080/": "[2001_db8__1]_8080",
+	} {
+		name, err := namer.getBasePath(URL)
+		require.Nil(t, err, "could not get name")
+		require.Equal(t, filepath.Join("responses", directory, getResponseHash(URL)), name, "could not sanitize host directory of %s", URL)
+	}
+}
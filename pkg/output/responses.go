@@ -1,19 +1,28 @@
 package output
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/sha1"
 	"encoding/hex"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	urlutil "github.com/projectdiscovery/utils/url"
 )
 
+// requestDelimiter separates the request from the response in the stored
+// response files
+const requestDelimiter = "--- response ---\n\n"
+
 func getResponseHash(URL string) string {
 	hash := sha1.Sum([]byte(URL))
 	return hex.EncodeToString(hash[:])
@@ -25,65 +34,123 @@ func (w *StandardWriter) formatResponse(resp *http.Response) ([]byte, error) {
 	builder.WriteString(resp.Request.URL.String())
 	builder.WriteString("\n\n\n")
 
-	builder.WriteString(resp.Request.Method)
+	if !w.omitRequest {
+		w.formatRequest(builder, resp.Request)
+		builder.WriteString(requestDelimiter)
+	}
+	formatResponseData(builder, resp)
+
+	return w.redact(builder.Bytes()), nil
+}
+
+// formatResponseData writes the status line, headers and body of the response
+func formatResponseData(builder *bytes.Buffer, resp *http.Response) {
+	builder.WriteString(resp.Proto)
+	builder.WriteString(" ")
+	builder.WriteString(resp.Status)
+	builder.WriteString("\n")
+	for k, v := range resp.Header {
+		builder.WriteString(k + ": " + strings.Join(v, "; ") + "\n")
+	}
+	builder.WriteString("\n")
+	builder.Write(readResponseBody(resp))
+}
+
+// formatRequest writes the request line, headers and body of the request
+func (w *StandardWriter) formatRequest(builder *bytes.Buffer, req *http.Request) {
+	builder.WriteString(req.Method)
 	builder.WriteString(" ")
-	path := resp.Request.URL.Path
-	if resp.Request.URL.Fragment != "" {
-		path = path + "#" + resp.Request.URL.Fragment
+	path := req.URL.Path
+	if req.URL.Fragment != "" {
+		path = path + "#" + req.URL.Fragment
 	}
 	builder.WriteString(path)
 	builder.WriteString(" ")
-	builder.WriteString(resp.Request.Proto)
+	builder.WriteString(req.Proto)
 	builder.WriteString("\n")
-	builder.WriteString("Host: " + resp.Request.Host)
+	builder.WriteString("Host: " + req.Host)
 	builder.WriteRune('\n')
-	for k, v := range resp.Request.Header {
+	for k, v := range req.Header {
 		builder.WriteString(k + ": " + strings.Join(v, "; ") + "\n")
 	}
 
-	if resp.Request.Body != nil {
-		bodyResp, _ := io.ReadAll(resp.Request.Body)
-		if string(bodyResp) != "" {
-			builder.WriteString("\n")
-			builder.WriteString(string(bodyResp))
-		}
+	if body := readRequestBody(req); len(body) > 0 {
+		builder.WriteString("\n")
+		builder.Write(body)
 	}
 	builder.WriteString("\n\n")
+}
 
-	builder.WriteString(resp.Proto)
-	builder.WriteString(" ")
-	builder.WriteString(resp.Status)
-	builder.WriteString("\n")
-	for k, v := range resp.Header {
-		builder.WriteString(k + ": " + strings.Join(v, "; ") + "\n")
+// readRequestBody reads the body of the request without consuming it.
+//
+// The body is retrieved using GetBody when available since the original
+// body may already have been consumed while sending the request.
+func readRequestBody(req *http.Request) []byte {
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			defer body.Close()
+			data, _ := io.ReadAll(body)
+			return data
+		}
 	}
-	builder.WriteString("\n")
-	body, _ := io.ReadAll(resp.Body)
-	builder.WriteString(string(body))
+	if req.Body == nil {
+		return nil
+	}
+	data, _ := io.ReadAll(req.Body)
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data
+}
 
-	return builder.Bytes(), nil
+// readResponseBody reads the body of the response, restoring it
+// so that it can be read again by other consumers.
+func readResponseBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return body
 }
 
+// getResponseHost returns the host of the URL to use as the directory
+// name of its stored responses, rejecting the hosts which are not safe
+// to use as a single directory name. The explicit port of the URL is
+// appended and the characters reserved on some filesystems, like the
+// colons of the IPv6 hosts, are replaced, like example.com_8443 for
+// example.com:8443 and [2001_db8__1] for [2001:db8::1].
 func getResponseHost(URL string) (string, error) {
 	u, err := urlutil.ParseWithScheme(URL)
 	if err != nil {
 		return "", err
 	}
-
-	return u.Host, nil
-}
-
-func createHostDir(storeResponseFolder, domain string) string {
-	_ = os.MkdirAll(filepath.Join(storeResponseFolder, domain), os.ModePerm)
-	return filepath.Join(storeResponseFolder, domain)
+	host := u.Host
+	if unescaped, err := url.PathUnescape(host); err == nil {
+		host = unescaped
+	}
+	if host == "" || host == "." || host == ".." || strings.ContainsAny(host, `/\`) || strings.IndexFunc(host, unicode.IsControl) != -1 {
+		return "", errors.Errorf("invalid host %q in url %s", u.Host, URL)
+	}
+	// the parsed host has no port, which is inferred from the scheme
+	if parsed, err := url.Parse(URL); err == nil && parsed.Port() != "" {
+		host += "_" + parsed.Port()
+	}
+	return sanitizeNameSegment(host), nil
 }
 
-func getResponseFile(storeResponseFolder, URL string) (*fileWriter, error) {
-	domain, err := getResponseHost(URL)
+func (w *StandardWriter) getResponseFile(URL string) (*fileWriter, error) {
+	fileName, err := w.getResponseFileName(URL)
 	if err != nil {
 		return nil, err
 	}
-	output, err := newFileOutputWriter(getResponseFileName(storeResponseFolder, domain, URL))
+	_ = os.MkdirAll(filepath.Dir(fileName), os.ModePerm)
+
+	var output *fileWriter
+	if w.compressResponse {
+		output, err = newCompressedFileOutputWriter(fileName)
+	} else {
+		output, err = newFileOutputWriter(fileName)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "could not create output file")
 	}
@@ -91,14 +158,89 @@ func getResponseFile(storeResponseFolder, URL string) (*fileWriter, error) {
 	return output, nil
 }
 
-func getResponseFileName(storeResponseFolder, domain, URL string) string {
-	folder := createHostDir(storeResponseFolder, domain)
-	file := getResponseHash(URL) + ".txt"
-	return filepath.Join(folder, file)
+// getResponseFileName returns the file name to store the response of a URL in
+func (w *StandardWriter) getResponseFileName(URL string) (string, error) {
+	basePath, err := w.responseNamer.getBasePath(URL)
+	if err != nil {
+		return "", err
+	}
+	fileName := basePath + ".txt"
+	if w.compressResponse {
+		fileName += ".gz"
+	}
+	return fileName, nil
+}
+
+// indexEntry is an entry of the json store response index
+type indexEntry struct {
+	URL        string    `json:"url"`
+	Path       string    `json:"path"`
+	StatusCode int       `json:"status_code"`
+	StoredAt   time.Time `json:"stored_at"`
+	// Canonical is the path of the first stored response with the same
+	// body, which the stored response is linked to
+	Canonical string `json:"canonical,omitempty"`
+}
+
+// getIndexFileName returns the name of the index file for a format
+func getIndexFileName(format string) string {
+	if format == IndexFormatJSON {
+		return jsonIndexFile
+	}
+	return indexFile
+}
+
+// readIndexURLs returns the URLs present in an existing index file
+func readIndexURLs(file, format string) (map[string]struct{}, error) {
+	urls := make(map[string]struct{})
+
+	index, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return urls, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer index.Close()
+
+	scanner := bufio.NewScanner(index)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if format == IndexFormatJSON {
+			var entry indexEntry
+			if err := jsoniter.Unmarshal([]byte(line), &entry); err == nil && entry.URL != "" {
+				urls[entry.URL] = struct{}{}
+			}
+			continue
+		}
+		// text index lines are formatted as "<path> <url> (<status>)"
+		if index := strings.LastIndex(line, " ("); index != -1 {
+			line = line[:index]
+		}
+		if index := strings.LastIndexByte(line, ' '); index != -1 {
+			urls[line[index+1:]] = struct{}{}
+		}
+	}
+	return urls, scanner.Err()
 }
 
-func updateIndex(storeResponseFolder string, resp *http.Response) error {
-	index, err := os.OpenFile(filepath.Join(storeResponseFolder, indexFile), os.O_APPEND|os.O_WRONLY, 0644)
+// updateIndex adds the stored response to the index, with the canonical
+// path of the response it is linked to if it is not empty
+func (w *StandardWriter) updateIndex(resp *http.Response, canonical string) error {
+	if w.indexedURLs != nil {
+		w.indexMutex.Lock()
+		defer w.indexMutex.Unlock()
+
+		if _, ok := w.indexedURLs[resp.Request.URL.String()]; ok {
+			return nil
+		}
+		w.indexedURLs[resp.Request.URL.String()] = struct{}{}
+	}
+	index, err := os.OpenFile(filepath.Join(w.storeResponseDir, getIndexFileName(w.indexFormat)), os.O_APPEND|os.O_WRONLY, 0644)
 	if err != nil {
 		return err
 	}
@@ -107,21 +249,118 @@ func updateIndex(storeResponseFolder string, resp *http.Response) error {
 
 	builder := &bytes.Buffer{}
 
-	domain, err := getResponseHost(resp.Request.URL.String())
+	var fileName string
+	if w.splitResponses {
+		fileName, err = w.responseNamer.getBasePath(resp.Request.URL.String())
+	} else {
+		fileName, err = w.getResponseFileName(resp.Request.URL.String())
+	}
 	if err != nil {
 		return err
 	}
 
-	builder.WriteString(getResponseFileName(storeResponseFolder, domain, resp.Request.URL.String()))
-	builder.WriteRune(' ')
-	builder.WriteString(resp.Request.URL.String())
-	builder.WriteRune(' ')
-	builder.WriteString("(" + resp.Status + ")")
+	switch w.indexFormat {
+	case IndexFormatJSON:
+		data, err := jsoniter.Marshal(indexEntry{
+			URL:        resp.Request.URL.String(),
+			Path:       fileName,
+			StatusCode: resp.StatusCode,
+			StoredAt:   time.Now(),
+			Canonical:  canonical,
+		})
+		if err != nil {
+			return errors.Wrap(err, "could not marshal index entry")
+		}
+		builder.Write(data)
+	default:
+		builder.WriteString(fileName)
+		builder.WriteRune(' ')
+		builder.WriteString(resp.Request.URL.String())
+		builder.WriteRune(' ')
+		builder.WriteString("(" + resp.Status + ")")
+		if canonical != "" {
+			builder.WriteString(" -> " + canonical)
+		}
+	}
 	builder.WriteRune('\n')
 
 	if _, writeErr := index.Write(builder.Bytes()); writeErr != nil {
-		return errors.Wrap(err, "could not update index")
+		return errors.Wrap(writeErr, "could not update index")
 	}
 
 	return nil
 }
+
+// responseMeta is the metadata of a stored response in split mode
+type responseMeta struct {
+	URL         string    `json:"url"`
+	StatusCode  int       `json:"status_code"`
+	ContentType string    `json:"content_type,omitempty"`
+	Timestamp   time.Time `json:"timestamp"`
+}
+
+// writeSplitResponse stores the request, response and metadata of the
+// response as separate files in a directory for the URL.
+func (w *StandardWriter) writeSplitResponse(resp *http.Response) error {
+	URL := resp.Request.URL.String()
+	dir, err := w.responseNamer.getBasePath(URL)
+	if err != nil {
+		return errors.Wrapf(err, "could not get response directory for %s", URL)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.Wrap(err, "could not create response directory")
+	}
+
+	if !w.omitRequest {
+		builder := &bytes.Buffer{}
+		w.formatRequest(builder, resp.Request)
+		if err := w.writeResponseFile(filepath.Join(dir, requestFile), w.redact(builder.Bytes())); err != nil {
+			return err
+		}
+	}
+	builder := &bytes.Buffer{}
+	formatResponseData(builder, resp)
+	fileName := filepath.Join(dir, responseFile)
+	linkedName := fileName
+	if w.compressResponse {
+		linkedName += ".gz"
+	}
+	canonical, err := w.writeResponseData(resp, linkedName, dir, func() error {
+		return w.writeResponseFile(fileName, w.redact(builder.Bytes()))
+	})
+	if err != nil {
+		return err
+	}
+
+	meta, err := jsoniter.Marshal(responseMeta{
+		URL:         URL,
+		StatusCode:  resp.StatusCode,
+		ContentType: resp.Header.Get("Content-Type"),
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal response metadata")
+	}
+	if err := os.WriteFile(filepath.Join(dir, metaFile), meta, 0644); err != nil {
+		return errors.Wrap(err, "could not write response metadata")
+	}
+	return w.updateIndex(resp, canonical)
+}
+
+// writeResponseFile writes the data to a stored response file, compressing
+// it if compression of stored responses is enabled.
+func (w *StandardWriter) writeResponseFile(fileName string, data []byte) error {
+	var file *fileWriter
+	var err error
+	if w.compressResponse {
+		file, err = newCompressedFileOutputWriter(fileName + ".gz")
+	} else {
+		file, err = newFileOutputWriter(fileName)
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not create output file")
+	}
+	defer file.Close()
+
+	return file.WriteRaw(data)
+}
@@ -5,8 +5,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/stretchr/testify/require"
 )
 
@@ -37,6 +40,8 @@ Host: projectdiscovery.io
 Test: test
 
 
+--- response ---
+
 HTTP/1.1 200 OK
 Test: test
 
@@ -60,3 +65,203 @@ func TestFormatResponses(t *testing.T) {
 		require.Equal(t, test.Result, string(result), "could not equal value")
 	}
 }
+
+func TestFormatResponsesWithoutRequest(t *testing.T) {
+	resp := &http.Response{
+		Status: "200 OK",
+		Proto:  "HTTP/1.1",
+		Header: http.Header{},
+		Body:   io.NopCloser(bytes.NewReader([]byte("test body"))),
+		Request: &http.Request{
+			Method: http.MethodPost,
+			URL:    &url.URL{Scheme: "https", Host: "projectdiscovery.io", Path: "/"},
+			Body:   io.NopCloser(bytes.NewReader([]byte("a=b"))),
+		},
+	}
+	w := StandardWriter{omitRequest: true}
+	result, err := w.formatResponse(resp)
+	require.Nil(t, err)
+	require.Equal(t, "https://projectdiscovery.io/\n\n\nHTTP/1.1 200 OK\n\ntest body", string(result), "could not equal value")
+}
+
+func TestReadRequestBody(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "https://projectdiscovery.io/", bytes.NewReader([]byte("a=b")))
+	require.Nil(t, err)
+	_, _ = io.ReadAll(req.Body)
+
+	require.Equal(t, "a=b", string(readRequestBody(req)), "could not read consumed body using GetBody")
+	require.Equal(t, "a=b", string(readRequestBody(req)), "could not read body again")
+}
+
+func TestReadIndexURLs(t *testing.T) {
+	directory := t.TempDir()
+
+	textIndex := filepath.Join(directory, indexFile)
+	err := os.WriteFile(textIndex, []byte("katana_responses/a/1.txt https://a.com/ (200 OK)\nkatana_responses/a/2.txt https://a.com/b?c=d (404 Not Found)\n"), 0644)
+	require.Nil(t, err, "could not write text index")
+	urls, err := readIndexURLs(textIndex, IndexFormatText)
+	require.Nil(t, err, "could not read text index")
+	require.Equal(t, map[string]struct{}{"https://a.com/": {}, "https://a.com/b?c=d": {}}, urls, "could not equal text index urls")
+
+	jsonIndex := filepath.Join(directory, jsonIndexFile)
+	err = os.WriteFile(jsonIndex, []byte(`{"url":"https://a.com/","path":"katana_responses/a/1.txt","status_code":200}`+"\n"), 0644)
+	require.Nil(t, err, "could not write json index")
+	urls, err = readIndexURLs(jsonIndex, IndexFormatJSON)
+	require.Nil(t, err, "could not read json index")
+	require.Equal(t, map[string]struct{}{"https://a.com/": {}}, urls, "could not equal json index urls")
+
+	urls, err = readIndexURLs(filepath.Join(directory, "missing.txt"), IndexFormatText)
+	require.Nil(t, err, "could not read missing index")
+	require.Empty(t, urls, "got urls for missing index")
+}
+
+func TestSplitStoredResponses(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := NewWithOptions(&Options{StoreResponse: true, StoreResponseDir: dir, SplitStoredResponses: true, IndexFormat: IndexFormatJSON})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	resp := &http.Response{
+		StatusCode: 200,
+		Status:     "200 OK",
+		Proto:      "HTTP/1.1",
+		Header:     http.Header{"Content-Type": []string{"text/html"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte("test body"))),
+		Request: &http.Request{
+			Method: http.MethodGet,
+			URL:    &url.URL{Scheme: "https", Host: "example.com", Path: "/a"},
+			Host:   "example.com",
+			Proto:  "HTTP/1.1",
+			Header: http.Header{},
+		},
+	}
+	require.Nil(t, writer.Write(nil, resp), "could not store response")
+
+	responseDir, err := writer.(*StandardWriter).responseNamer.getBasePath("https://example.com/a")
+	require.Nil(t, err, "could not get response directory")
+	request, err := os.ReadFile(filepath.Join(responseDir, requestFile))
+	require.Nil(t, err, "could not read request file")
+	require.Equal(t, "GET /a HTTP/1.1\nHost: example.com\n\n\n", string(request), "could not get stored request")
+
+	response, err := os.ReadFile(filepath.Join(responseDir, responseFile))
+	require.Nil(t, err, "could not read response file")
+	require.Equal(t, "HTTP/1.1 200 OK\nContent-Type: text/html\n\ntest body", string(response), "could not get stored response")
+
+	var meta responseMeta
+	data, err := os.ReadFile(filepath.Join(responseDir, metaFile))
+	require.Nil(t, err, "could not read meta file")
+	require.Nil(t, jsoniter.Unmarshal(data, &meta), "could not decode meta file")
+	require.Equal(t, "https://example.com/a", meta.URL, "could not get meta url")
+	require.Equal(t, 200, meta.StatusCode, "could not get meta status code")
+	require.Equal(t, "text/html", meta.ContentType, "could not get meta content-type")
+
+	var entry indexEntry
+	data, err = os.ReadFile(filepath.Join(dir, jsonIndexFile))
+	require.Nil(t, err, "could not read index file")
+	require.Nil(t, jsoniter.Unmarshal(data, &entry), "could not decode index entry")
+	require.Equal(t, responseDir, entry.Path, "could not point index to response directory")
+}
+
+func TestStoreResponseFilters(t *testing.T) {
+	dir := t.TempDir()
+
+	writer, err := NewWithOptions(&Options{StoreResponse: true, StoreResponseDir: dir, StoreMatchStatusCodes: []string{"2xx"}, StoreMatchContentTypes: []string{"text/html"}, IndexFormat: IndexFormatJSON})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	newResponse := func(path string, statusCode int, contentType string) *http.Response {
+		return &http.Response{
+			StatusCode: statusCode,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Content-Type": []string{contentType}},
+			Body:       io.NopCloser(bytes.NewReader([]byte("test body"))),
+			Request: &http.Request{
+				Method: http.MethodGet,
+				URL:    &url.URL{Scheme: "https", Host: "example.com", Path: path},
+				Host:   "example.com",
+				Header: http.Header{},
+			},
+		}
+	}
+	require.Nil(t, writer.Write(nil, newResponse("/a", 200, "text/html; charset=utf-8")), "could not store response")
+	require.Nil(t, writer.Write(nil, newResponse("/b", 404, "text/html")), "could not skip response")
+	require.Nil(t, writer.Write(nil, newResponse("/c", 200, "application/json")), "could not skip response")
+
+	var entry indexEntry
+	data, err := os.ReadFile(filepath.Join(dir, jsonIndexFile))
+	require.Nil(t, err, "could not read index file")
+	require.Equal(t, 1, bytes.Count(data, []byte("\n")), "could not index only stored responses")
+	require.Nil(t, jsoniter.Unmarshal(data, &entry), "could not decode index entry")
+	require.Equal(t, "https://example.com/a", entry.URL, "could not store matching response")
+
+	_, err = NewWithOptions(&Options{StoreMatchStatusCodes: []string{"abc"}})
+	require.NotNil(t, err, "could not get invalid store status code error")
+}
+
+func TestDedupStoredResponses(t *testing.T) {
+	for _, split := range []bool{false, true} {
+		dir := t.TempDir()
+
+		writer, err := NewWithOptions(&Options{StoreResponse: true, StoreResponseDir: dir, SplitStoredResponses: split, DedupResponsesByHash: true, IndexFormat: IndexFormatJSON})
+		require.Nil(t, err, "could not create writer")
+
+		newResponse := func(path, body string) *http.Response {
+			return &http.Response{
+				StatusCode: 200,
+				Status:     "200 OK",
+				Proto:      "HTTP/1.1",
+				Header:     http.Header{},
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+				Request: &http.Request{
+					Method: http.MethodGet,
+					URL:    &url.URL{Scheme: "https", Host: "example.com", Path: path},
+					Host:   "example.com",
+					Header: http.Header{},
+				},
+			}
+		}
+		require.Nil(t, writer.Write(nil, newResponse("/a", "same body")), "could not store response")
+		require.Nil(t, writer.Write(nil, newResponse("/b", "same body")), "could not store duplicate response")
+		require.Nil(t, writer.Write(nil, newResponse("/c", "other body")), "could not store response")
+		require.Nil(t, writer.Close(), "could not close writer")
+
+		data, err := os.ReadFile(filepath.Join(dir, jsonIndexFile))
+		require.Nil(t, err, "could not read index file")
+		var entries []indexEntry
+		for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+			var entry indexEntry
+			require.Nil(t, jsoniter.Unmarshal(line, &entry), "could not decode index entry")
+			entries = append(entries, entry)
+		}
+		require.Len(t, entries, 3, "could not index all stored responses")
+		require.Empty(t, entries[0].Canonical, "could not store first response")
+		require.Equal(t, entries[0].Path, entries[1].Canonical, "could not record canonical response")
+		require.Empty(t, entries[2].Canonical, "could not store different response")
+
+		first, second := entries[0].Path, entries[1].Path
+		if split {
+			first, second = filepath.Join(first, responseFile), filepath.Join(second, responseFile)
+		}
+		info, err := os.Lstat(second)
+		require.Nil(t, err, "could not stat duplicate response")
+		require.Equal(t, os.ModeSymlink, info.Mode()&os.ModeSymlink, "could not link duplicate response")
+		expected, err := os.ReadFile(first)
+		require.Nil(t, err, "could not read first response")
+		linked, err := os.ReadFile(second)
+		require.Nil(t, err, "could not read duplicate response")
+		require.Equal(t, expected, linked, "could not link to first response")
+	}
+}
+
+func TestLinkResponseFileCopy(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target.txt")
+	require.Nil(t, os.WriteFile(target, []byte("response"), 0644), "could not write target")
+
+	fileName := filepath.Join(dir, "copy.txt")
+	require.Nil(t, copyResponseFile(target, fileName), "could not copy response")
+	data, err := os.ReadFile(fileName)
+	require.Nil(t, err, "could not read copy")
+	require.Equal(t, "response", string(data), "could not copy response")
+}
@@ -0,0 +1,69 @@
+package output
+
+import "strings"
+
+// Clone returns a deep copy of the result, which can be modified without
+// changing the result, like in the transformers.
+func (r *Result) Clone() *Result {
+	if r == nil {
+		return nil
+	}
+	clone := *r
+	clone.ResponseHeaders = cloneStringMap(r.ResponseHeaders)
+	clone.RequestHeaders = cloneStringMap(r.RequestHeaders)
+	clone.Extracted = cloneStringMap(r.Extracted)
+	clone.Redirects = cloneStrings(r.Redirects)
+	clone.Technologies = cloneStrings(r.Technologies)
+	clone.Params = cloneStrings(r.Params)
+	if r.Form != nil {
+		form := *r.Form
+		form.Inputs = cloneStrings(r.Form.Inputs)
+		clone.Form = &form
+	}
+	if r.TLS != nil {
+		tls := *r.TLS
+		tls.SubjectOrg = cloneStrings(r.TLS.SubjectOrg)
+		tls.SANs = cloneStrings(r.TLS.SANs)
+		clone.TLS = &tls
+	}
+	return &clone
+}
+
+// Key returns the canonical key of the result for the dedup key mode
+// (url,method-url,url-no-query), the method and URL are used for an
+// empty or unknown mode.
+//
+// The URL is trimmed, and the url-no-query mode keeps only the lowercase
+// scheme and host and the path of the URL, ignoring its query parameters.
+func (r *Result) Key(mode string) string {
+	URL := strings.TrimSpace(r.URL)
+	switch mode {
+	case DedupKeyURL:
+		return URL
+	case DedupKeyURLNoQuery:
+		return normalizeUniqueURL(URL, UniqueOptions{})
+	}
+	method := strings.ToUpper(r.Method)
+	if method == "" {
+		method = "GET"
+	}
+	return method + " " + URL
+}
+
+func cloneStringMap(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	clone := make(map[string]string, len(values))
+	for key, value := range values {
+		clone[key] = value
+	}
+	return clone
+}
+
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(values)), values...)
+}
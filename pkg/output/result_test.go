@@ -0,0 +1,51 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultClone(t *testing.T) {
+	result := &Result{
+		URL:             "https://example.com/?a=1",
+		ResponseHeaders: map[string]string{"Server": "nginx"},
+		RequestHeaders:  map[string]string{"Cookie": "a=b"},
+		Redirects:       []string{"https://example.com/a"},
+		Params:          []string{"a"},
+		Form:            &Form{Action: "https://example.com/login", Inputs: []string{"user"}},
+		TLS:             &TLS{SubjectCN: "example.com", SANs: []string{"example.com"}, NotAfter: time.Unix(1, 0)},
+	}
+	clone := result.Clone()
+	require.Equal(t, result, clone, "could not clone result")
+
+	clone.ResponseHeaders["Server"] = "apache"
+	clone.RequestHeaders["Cookie"] = "c=d"
+	clone.Redirects[0] = "https://example.com/b"
+	clone.Params = append(clone.Params, "b")
+	clone.Form.Inputs[0] = "password"
+	clone.TLS.SANs[0] = "www.example.com"
+
+	require.Equal(t, "nginx", result.ResponseHeaders["Server"], "could not deep copy response headers")
+	require.Equal(t, "a=b", result.RequestHeaders["Cookie"], "could not deep copy request headers")
+	require.Equal(t, []string{"https://example.com/a"}, result.Redirects, "could not deep copy redirects")
+	require.Equal(t, []string{"a"}, result.Params, "could not deep copy params")
+	require.Equal(t, []string{"user"}, result.Form.Inputs, "could not deep copy form")
+	require.Equal(t, []string{"example.com"}, result.TLS.SANs, "could not deep copy tls")
+
+	require.Nil(t, (*Result)(nil).Clone(), "got clone of nil result")
+	require.Nil(t, (&Result{}).Clone().ResponseHeaders, "got headers for result without headers")
+}
+
+func TestResultKey(t *testing.T) {
+	result := &Result{URL: " https://Example.com/a?b=1 ", Method: "post"}
+	require.Equal(t, "https://Example.com/a?b=1", result.Key(DedupKeyURL), "could not get url key")
+	require.Equal(t, "POST https://Example.com/a?b=1", result.Key(DedupKeyMethodURL), "could not get method url key")
+	require.Equal(t, "GET https://example.com/a", (&Result{URL: "https://example.com/a"}).Key(""), "could not get default key")
+
+	other := &Result{URL: "https://example.com/a?c=2"}
+	require.Equal(t, "https://example.com/a", result.Key(DedupKeyURLNoQuery), "could not get url key without query")
+	require.Equal(t, result.Key(DedupKeyURLNoQuery), other.Key(DedupKeyURLNoQuery), "could not ignore query in key")
+	require.NotEqual(t, result.Key(DedupKeyURL), other.Key(DedupKeyURL), "got same key for different queries")
+}
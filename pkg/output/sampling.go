@@ -0,0 +1,56 @@
+package output
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// sampler selects a sample of the results to write
+type sampler struct {
+	// rate is the probability of writing a result, disabled if 0
+	rate float64
+	// everyN writes every nth result, disabled if not positive
+	everyN int
+
+	mutex   *sync.Mutex
+	random  *rand.Rand
+	counter int
+}
+
+// newSampler creates a new sampler for the sample rate and every nth
+// result, returning nil if sampling is disabled.
+func newSampler(rate float64, everyN int) (*sampler, error) {
+	if rate < 0 || rate > 1 {
+		return nil, errors.Errorf("invalid sample rate %v specified, expected a value between 0 and 1", rate)
+	}
+	if rate == 0 && everyN <= 1 {
+		return nil, nil
+	}
+	return &sampler{
+		rate:   rate,
+		everyN: everyN,
+		mutex:  &sync.Mutex{},
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+// sample returns true if the result should be written, both the sample
+// rate and the every nth sampling must select it if they are enabled.
+func (s *sampler) sample() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.everyN > 1 {
+		s.counter++
+		if s.counter%s.everyN != 0 {
+			return false
+		}
+	}
+	if s.rate > 0 && s.random.Float64() >= s.rate {
+		return false
+	}
+	return true
+}
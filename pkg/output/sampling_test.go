@@ -0,0 +1,44 @@
+package output
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSamplerEveryN(t *testing.T) {
+	s, err := newSampler(0, 3)
+	require.Nil(t, err, "could not create sampler")
+
+	var sampled int
+	for i := 0; i < 9; i++ {
+		if s.sample() {
+			sampled++
+		}
+	}
+	require.Equal(t, 3, sampled, "could not sample every nth result")
+}
+
+func TestSamplerRate(t *testing.T) {
+	s, err := newSampler(0.25, 0)
+	require.Nil(t, err, "could not create sampler")
+	s.random = rand.New(rand.NewSource(1))
+
+	var sampled int
+	for i := 0; i < 1000; i++ {
+		if s.sample() {
+			sampled++
+		}
+	}
+	require.InDelta(t, 250, sampled, 50, "could not sample results by rate")
+}
+
+func TestSamplerDisabled(t *testing.T) {
+	s, err := newSampler(0, 1)
+	require.Nil(t, err, "could not create sampler")
+	require.Nil(t, s, "got sampler without sampling")
+
+	_, err = newSampler(1.5, 0)
+	require.Error(t, err, "got no error for invalid sample rate")
+}
@@ -0,0 +1,176 @@
+package output
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// jsonSchemaDraft is the JSON Schema draft of the result schema
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is the subset of JSON Schema describing the json results
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Type                 interface{}            `json:"type,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties interface{}            `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+}
+
+// resultSchema is the schema of the json results generated from the json
+// tags of the Result fields
+var resultSchema = getResultSchema()
+
+// ResultJSONSchema returns the JSON Schema of the json, jsonl and yaml
+// results written by the output writer.
+//
+// The schema is generated from the json tags of the Result fields, the
+// results don't allow properties which are not in the schema.
+func ResultJSONSchema() []byte {
+	data, _ := jsoniter.ConfigCompatibleWithStandardLibrary.MarshalIndent(resultSchema, "", "  ")
+	return data
+}
+
+// getResultSchema returns the schema of the Result struct
+func getResultSchema() *jsonSchema {
+	schema := getJSONSchema(reflect.TypeOf(Result{}))
+	schema.Schema = jsonSchemaDraft
+	schema.Title = "katana result"
+	return schema
+}
+
+// getJSONSchema returns the schema of the json encoding of a type
+func getJSONSchema(valueType reflect.Type) *jsonSchema {
+	switch valueType {
+	case reflect.TypeOf(time.Time{}):
+		// the timestamp is a string or a number by the timestamp format
+		return &jsonSchema{Type: []string{"string", "integer"}, Format: "date-time"}
+	case reflect.TypeOf(Duration(0)):
+		return &jsonSchema{Type: "number"}
+	}
+	switch valueType.Kind() {
+	case reflect.Ptr:
+		return getJSONSchema(valueType.Elem())
+	case reflect.Bool:
+		return &jsonSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &jsonSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &jsonSchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &jsonSchema{Type: "array", Items: getJSONSchema(valueType.Elem())}
+	case reflect.Map:
+		return &jsonSchema{Type: "object", AdditionalProperties: getJSONSchema(valueType.Elem())}
+	case reflect.Struct:
+		schema := &jsonSchema{Type: "object", Properties: make(map[string]*jsonSchema), AdditionalProperties: false}
+		for i := 0; i < valueType.NumField(); i++ {
+			field := valueType.Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" || field.PkgPath != "" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			schema.Properties[name] = getJSONSchema(field.Type)
+		}
+		return schema
+	}
+	return &jsonSchema{Type: "string"}
+}
+
+// validateResultJSON validates the json encoded result against the
+// result schema
+func validateResultJSON(data []byte) error {
+	return resultSchema.validateJSON(data)
+}
+
+// validateJSON validates the json encoded result against the schema
+func (s *jsonSchema) validateJSON(data []byte) error {
+	var value interface{}
+	if err := jsoniter.Unmarshal(data, &value); err != nil {
+		return errors.Wrap(err, "could not parse json result")
+	}
+	return s.validate(value, "result")
+}
+
+// validate validates the decoded json value against the schema
+func (s *jsonSchema) validate(value interface{}, path string) error {
+	if !s.matchesType(value) {
+		return errors.Errorf("%s does not match the schema type %v", path, s.Type)
+	}
+	switch value := value.(type) {
+	case map[string]interface{}:
+		names := make([]string, 0, len(value))
+		for name := range value {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				additional, isSchema := s.AdditionalProperties.(*jsonSchema)
+				if !isSchema {
+					return errors.Errorf("%s has the property %s which is not in the schema", path, name)
+				}
+				property = additional
+			}
+			if err := property.validate(value[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			return nil
+		}
+		for _, item := range value {
+			if err := s.Items.validate(item, path+"[]"); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// matchesType returns true if the decoded json value has a type of the schema
+func (s *jsonSchema) matchesType(value interface{}) bool {
+	types, ok := s.Type.([]string)
+	if !ok {
+		types = []string{s.Type.(string)}
+	}
+	for _, schemaType := range types {
+		switch value := value.(type) {
+		case string:
+			if schemaType == "string" {
+				return true
+			}
+		case bool:
+			if schemaType == "boolean" {
+				return true
+			}
+		case float64:
+			if schemaType == "number" || (schemaType == "integer" && value == math.Trunc(value)) {
+				return true
+			}
+		case map[string]interface{}:
+			if schemaType == "object" {
+				return true
+			}
+		case []interface{}:
+			if schemaType == "array" {
+				return true
+			}
+		}
+	}
+	return false
+}
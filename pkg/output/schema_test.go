@@ -0,0 +1,60 @@
+package output
+
+import (
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultJSONSchema(t *testing.T) {
+	var schema map[string]interface{}
+	require.Nil(t, jsoniter.Unmarshal(ResultJSONSchema(), &schema), "could not parse schema")
+	require.Equal(t, jsonSchemaDraft, schema["$schema"], "could not get schema draft")
+	require.Equal(t, false, schema["additionalProperties"], "could not disallow unknown properties")
+
+	properties := schema["properties"].(map[string]interface{})
+	require.Len(t, properties, len(csvColumns), "could not get all result properties")
+	require.Equal(t, "integer", properties["status_code"].(map[string]interface{})["type"], "could not get integer property")
+	require.Equal(t, "number", properties["latency"].(map[string]interface{})["type"], "could not get duration property")
+	form := properties["form"].(map[string]interface{})
+	require.Contains(t, form["properties"], "inputs", "could not get nested form properties")
+}
+
+func TestValidateResultJSON(t *testing.T) {
+	for _, timestampFormat := range []string{TimestampFormatRFC3339, TimestampFormatEpoch} {
+		standardWriter := &StandardWriter{timestampFormat: timestampFormat}
+		data, err := standardWriter.formatJSONL(&Result{
+			Timestamp:       time.Now(),
+			URL:             "https://example.com/login",
+			StatusCode:      200,
+			Latency:         Duration(1500 * time.Microsecond),
+			ResponseHeaders: map[string]string{"Server": "nginx"},
+			RedirectLoop:    true,
+			Form:            &Form{Action: "/login", Inputs: []string{"user"}},
+			Technologies:    []string{"Nginx"},
+		})
+		require.Nil(t, err, "could not format result")
+		require.Nil(t, validateResultJSON(data), "could not validate result")
+	}
+
+	require.NotNil(t, validateResultJSON([]byte(`{"endpoint":"https://example.com","unknown":1}`)), "could not reject unknown property")
+	require.NotNil(t, validateResultJSON([]byte(`{"status_code":"200"}`)), "could not reject mismatched type")
+	require.NotNil(t, validateResultJSON([]byte(`{"depth":1.5}`)), "could not reject fractional integer")
+	require.NotNil(t, validateResultJSON([]byte(`{"form":{"inputs":[1]}}`)), "could not reject mismatched array item")
+}
+
+func TestValidateOutput(t *testing.T) {
+	screen := &screenWriter{}
+	gologger.DefaultLogger.SetWriter(screen)
+	defer gologger.DefaultLogger.SetWriter(writer.NewCLI())
+
+	standardWriter, err := NewWithOptions(&Options{JSONL: true, ValidateOutput: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/", StatusCode: 200}, nil), "could not write validated result")
+	require.Len(t, screen.data, 1, "could not write validated result")
+	require.Contains(t, screen.data[0], `"endpoint":"https://example.com/"`, "could not write validated result")
+}
@@ -0,0 +1,128 @@
+package output
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// Formats of the output sinks
+const (
+	SinkFormatText  = "text"
+	SinkFormatJSON  = "json"
+	SinkFormatJSONL = "jsonl"
+	SinkFormatCSV   = "csv"
+	SinkFormatTSV   = "tsv"
+	SinkFormatYAML  = "yaml"
+	SinkFormatCEF   = "cef"
+	SinkFormatCurl  = "curl"
+)
+
+// SinkConfig is the configuration of an output sink, which writes the
+// results of the crawl in its own format to its own file with its own
+// filters, like all the results as JSONL to a file and only the 200 HTML
+// results as CSV to stdout.
+type SinkConfig struct {
+	// Format is the format of the sink (text,json,jsonl,csv,tsv,yaml,cef,curl).
+	// The default is text.
+	Format string
+	// File is the file to write the sink to, the sink is written to
+	// stdout if it is empty.
+	File string
+	// Fields is the fields to format in the sink
+	Fields string
+	// MatchStatusCodes contains the status codes of the results to write
+	MatchStatusCodes []string
+	// FilterStatusCodes contains the status codes of the results to drop
+	FilterStatusCodes []string
+	// MatchContentTypes contains the content-types of the results to write
+	MatchContentTypes []string
+	// FilterContentTypes contains the content-types of the results to drop
+	FilterContentTypes []string
+	// MatchExtensions contains the extensions of the results to write
+	MatchExtensions []string
+	// FilterExtensions contains the extensions of the results to drop
+	FilterExtensions []string
+	// MatchRegex contains the regexes of the URLs of the results to write
+	MatchRegex []string
+	// FilterRegex contains the regexes of the URLs of the results to drop
+	FilterRegex []string
+}
+
+// newSinkWriter creates the writer of the sink, inheriting the display
+// options of the output writer.
+func newSinkWriter(config SinkConfig, options *Options) (*StandardWriter, error) {
+	sinkOptions := &Options{
+		Colors:             options.Colors && config.File == "",
+		ColorScheme:        options.ColorScheme,
+		Verbose:            options.Verbose,
+		TimestampFormat:    options.TimestampFormat,
+		CEFDeviceVendor:    options.CEFDeviceVendor,
+		CEFDeviceProduct:   options.CEFDeviceProduct,
+		CEFDeviceVersion:   options.CEFDeviceVersion,
+		FieldAliases:       options.FieldAliases,
+		OutputFile:         config.File,
+		Silent:             config.File != "",
+		Fields:             config.Fields,
+		MatchStatusCodes:   config.MatchStatusCodes,
+		FilterStatusCodes:  config.FilterStatusCodes,
+		MatchContentTypes:  config.MatchContentTypes,
+		FilterContentTypes: config.FilterContentTypes,
+		MatchExtensions:    config.MatchExtensions,
+		FilterExtensions:   config.FilterExtensions,
+		MatchRegex:         config.MatchRegex,
+		FilterRegex:        config.FilterRegex,
+	}
+	switch config.Format {
+	case "", SinkFormatText:
+	case SinkFormatJSON:
+		sinkOptions.JSON = true
+	case SinkFormatJSONL:
+		sinkOptions.JSONL = true
+	case SinkFormatCSV:
+		sinkOptions.CSV = true
+	case SinkFormatTSV:
+		sinkOptions.TSV = true
+	case SinkFormatYAML:
+		sinkOptions.YAML = true
+	case SinkFormatCEF:
+		sinkOptions.CEF = true
+	case SinkFormatCurl:
+		sinkOptions.OutputCurl = true
+	default:
+		return nil, errors.Errorf("invalid sink format %s specified", config.Format)
+	}
+	writer, err := NewWithOptions(sinkOptions)
+	if err != nil {
+		return nil, err
+	}
+	return writer.(*StandardWriter), nil
+}
+
+// createSinks creates the writers of the sinks, closing the created ones
+// if a sink can't be created
+func (w *StandardWriter) createSinks(options *Options) error {
+	for i, config := range options.Sinks {
+		sink, err := newSinkWriter(config, options)
+		if err != nil {
+			for _, created := range w.sinks {
+				_ = created.Close()
+			}
+			w.sinks = nil
+			return errors.Wrapf(err, "could not create sink %d", i+1)
+		}
+		w.sinks = append(w.sinks, sink)
+	}
+	return nil
+}
+
+// writeSinks writes a copy of the result to every sink, which applies
+// its own filters. All the sinks are written even if one fails.
+func (w *StandardWriter) writeSinks(ctx context.Context, event *Result) error {
+	var err error
+	for _, sink := range w.sinks {
+		err = multierr.Append(err, sink.WriteContext(ctx, event.Clone(), nil))
+	}
+	return err
+}
@@ -0,0 +1,89 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSinks(t *testing.T) {
+	dir := t.TempDir()
+	allFile := filepath.Join(dir, "all.jsonl")
+	htmlFile := filepath.Join(dir, "html.csv")
+
+	var written []string
+	writer, err := NewWithOptions(&Options{
+		Silent:      true,
+		FilterRegex: []string{"logout"},
+		Sinks: []SinkConfig{
+			{Format: SinkFormatJSONL, File: allFile},
+			{Format: SinkFormatCSV, File: htmlFile, MatchStatusCodes: []string{"200"}, MatchContentTypes: []string{"text/html"}},
+		},
+		OnResult: func(result *Result) {
+			written = append(written, result.URL)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+
+	results := []*Result{
+		{URL: "https://example.com/", StatusCode: 200, ContentType: "text/html"},
+		{URL: "https://example.com/app.js", StatusCode: 200, ContentType: "application/javascript"},
+		{URL: "https://example.com/missing", StatusCode: 404, ContentType: "text/html"},
+		{URL: "https://example.com/logout", StatusCode: 200, ContentType: "text/html"},
+	}
+	for _, result := range results {
+		require.Nil(t, writer.Write(result, nil), "could not write result")
+	}
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Equal(t, []string{"https://example.com/", "https://example.com/app.js", "https://example.com/missing"}, written, "could not filter output writer")
+
+	data, err := os.ReadFile(allFile)
+	require.Nil(t, err, "could not read jsonl sink")
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 4, "could not write all results to jsonl sink")
+	var last Result
+	require.Nil(t, jsoniter.Unmarshal([]byte(lines[3]), &last), "could not unmarshal jsonl sink result")
+	require.Equal(t, "https://example.com/logout", last.URL, "could not write filtered result to sink")
+
+	data, err = os.ReadFile(htmlFile)
+	require.Nil(t, err, "could not read csv sink")
+	require.Contains(t, string(data), "https://example.com/", "could not write matched result to csv sink")
+	require.Contains(t, string(data), "https://example.com/logout", "could not write matched result to csv sink")
+	require.NotContains(t, string(data), "app.js", "could not filter csv sink by content type")
+	require.NotContains(t, string(data), "missing", "could not filter csv sink by status code")
+}
+
+func TestSinksInvalidFormat(t *testing.T) {
+	_, err := NewWithOptions(&Options{Sinks: []SinkConfig{{Format: "xml"}}})
+	require.Error(t, err, "could not get invalid sink format error")
+}
+
+func TestSinksCopyResult(t *testing.T) {
+	sinkFile := filepath.Join(t.TempDir(), "sink.jsonl")
+	writer, err := NewWithOptions(&Options{
+		Silent: true,
+		Sinks:  []SinkConfig{{Format: SinkFormatJSONL, File: sinkFile}},
+		Transformers: []func(*Result) *Result{
+			func(result *Result) *Result {
+				result.Tag = "transformed"
+				return result
+			},
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+
+	result := &Result{URL: "https://example.com/", ResponseHeaders: map[string]string{"Server": "nginx"}}
+	require.Nil(t, writer.Write(result, nil), "could not write result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(sinkFile)
+	require.Nil(t, err, "could not read sink")
+	var sinkResult Result
+	require.Nil(t, jsoniter.Unmarshal(data, &sinkResult), "could not unmarshal sink result")
+	require.Equal(t, "transformed", sinkResult.Tag, "could not write transformed result to sink")
+	require.Equal(t, "nginx", sinkResult.ResponseHeaders["Server"], "could not write copied headers to sink")
+}
@@ -0,0 +1,35 @@
+package output
+
+import (
+	"context"
+	"sort"
+)
+
+// bufferSortedResult buffers the result to write it sorted on Close
+func (w *StandardWriter) bufferSortedResult(event *Result) {
+	w.outputMutex.Lock()
+	w.sortedResults = append(w.sortedResults, event)
+	w.outputMutex.Unlock()
+}
+
+// writeSortedResults writes the buffered results sorted by their URL and
+// then their source, keeping the write order of identical keys.
+func (w *StandardWriter) writeSortedResults() error {
+	w.outputMutex.Lock()
+	results := w.sortedResults
+	w.sortedResults = nil
+	w.outputMutex.Unlock()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].URL != results[j].URL {
+			return results[i].URL < results[j].URL
+		}
+		return results[i].Source < results[j].Source
+	})
+	for _, result := range results {
+		if err := w.writeResult(context.Background(), result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortOutput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "output.txt")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: file, SortOutput: true, Fields: "url,parent", Silent: true, Concurrency: 4})
+	require.Nil(t, err, "could not create writer")
+	for _, result := range []*Result{
+		{URL: "https://example.com/c", Parent: "https://example.com/"},
+		{URL: "https://example.com/a", Source: "https://example.com/z", Parent: "https://example.com/z"},
+		{URL: "https://example.com/b", Parent: "https://example.com/"},
+		{URL: "https://example.com/a", Source: "https://example.com/b", Parent: "https://example.com/b"},
+	} {
+		require.Nil(t, standardWriter.Write(result, nil), "could not write result")
+	}
+	require.Nil(t, standardWriter.Flush(), "could not flush writer")
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Empty(t, data, "could not buffer sorted results")
+
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+	data, err = os.ReadFile(file)
+	require.Nil(t, err, "could not read output")
+	require.Equal(t, []string{
+		"https://example.com/a,https://example.com/b",
+		"https://example.com/a,https://example.com/z",
+		"https://example.com/b,https://example.com/",
+		"https://example.com/c,https://example.com/",
+	}, strings.Fields(string(data)), "could not sort results by url and source")
+}
@@ -0,0 +1,166 @@
+package output
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+)
+
+// Keys to split the output files by
+const (
+	SplitBySource = "source"
+	SplitByStatus = "status"
+	SplitByHost   = "host"
+)
+
+// splitOutput is a set of output files in a directory, one for each key
+// of the results, which are created on the first result with the key.
+type splitOutput struct {
+	splitBy   string
+	directory string
+	extension string
+	options   fileWriterOptions
+	files     map[string]*fileWriter
+}
+
+// newSplitOutput returns a split output writing the files to the
+// directory, creating the directory if it doesn't exist
+func newSplitOutput(splitBy, directory, extension string, options fileWriterOptions) (*splitOutput, error) {
+	switch splitBy {
+	case SplitBySource, SplitByStatus, SplitByHost:
+	default:
+		return nil, errors.Errorf("invalid split by %s specified", splitBy)
+	}
+	if directory == "" {
+		return nil, errors.New("no split output directory specified")
+	}
+	if err := os.MkdirAll(directory, os.ModePerm); err != nil {
+		return nil, errors.Wrap(err, "could not create split output directory")
+	}
+	if options.compress {
+		extension += ".gz"
+	}
+	return &splitOutput{
+		splitBy:   splitBy,
+		directory: directory,
+		extension: extension,
+		options:   options,
+		files:     make(map[string]*fileWriter),
+	}, nil
+}
+
+// getKey returns the key of the file to write the result to.
+//
+// The results are split by their tag for the source, by the class of
+// their status code like 2xx for the status and by their host.
+func (s *splitOutput) getKey(event *Result) string {
+	var key string
+	switch s.splitBy {
+	case SplitBySource:
+		key = event.Tag
+	case SplitByStatus:
+		if event.StatusCode >= 100 && event.StatusCode <= 999 {
+			key = strconv.Itoa(event.StatusCode/100) + "xx"
+		}
+	case SplitByHost:
+		if parsed, err := url.Parse(event.URL); err == nil {
+			key = parsed.Host
+		}
+	}
+	return sanitizeSplitKey(key)
+}
+
+// sanitizeSplitKey returns the key usable as a file name, replacing the
+// unsafe characters and defaulting empty keys to unknown.
+func sanitizeSplitKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+	if strings.Trim(key, ".") == "" {
+		return "unknown"
+	}
+	return key
+}
+
+// getFile returns the file for the key, creating it on first use.
+//
+// The header is written to the newly created files, like the csv header.
+// It must be called with the output mutex held.
+func (s *splitOutput) getFile(key string, header func() ([]byte, error)) (*fileWriter, error) {
+	if file, ok := s.files[key]; ok {
+		return file, nil
+	}
+	file, err := newFileOutputWriterWithOptions(filepath.Join(s.directory, key+s.extension), s.options)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create split output file")
+	}
+	s.files[key] = file
+	if header != nil {
+		data, err := header()
+		if err != nil {
+			return nil, err
+		}
+		if err := file.Write(data); err != nil {
+			return nil, errors.Wrap(err, "could not write split output header")
+		}
+	}
+	return file, nil
+}
+
+// flush flushes the buffered data of all the files
+func (s *splitOutput) flush() error {
+	var err error
+	for _, file := range s.files {
+		err = multierr.Append(err, file.Flush())
+	}
+	return err
+}
+
+// close closes all the files
+func (s *splitOutput) close() error {
+	var err error
+	for _, file := range s.files {
+		err = multierr.Append(err, file.Close())
+	}
+	return err
+}
+
+// getPaths returns the sorted paths of all the written files
+func (s *splitOutput) getPaths() []string {
+	var paths []string
+	for _, file := range s.files {
+		paths = append(paths, file.getPaths()...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// getSplitExtension returns the file extension for the output format
+func getSplitExtension(options *Options) string {
+	switch {
+	case options.JSONL:
+		return ".jsonl"
+	case options.JSON:
+		return ".json"
+	case options.CSV:
+		return ".csv"
+	case options.TSV:
+		return ".tsv"
+	case options.YAML:
+		return ".yaml"
+	case options.CEF:
+		return ".cef"
+	case options.OutputCurl:
+		return ".sh"
+	}
+	return ".txt"
+}
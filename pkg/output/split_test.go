@@ -0,0 +1,74 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitOutput(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "output")
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: dir, SplitBy: SplitBySource, JSONL: true, Silent: true, Concurrency: 4})
+	require.Nil(t, err, "could not create writer")
+	for _, result := range []*Result{
+		{URL: "https://example.com/", Tag: "a"},
+		{URL: "https://example.com/app.js", Tag: "script"},
+		{URL: "https://example.com/api", Tag: "js"},
+		{URL: "https://example.com/login", Tag: "form"},
+		{URL: "https://example.com/other", Tag: "a"},
+		{URL: "https://example.com/seed"},
+	} {
+		require.Nil(t, standardWriter.Write(result, nil), "could not write result")
+	}
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	entries, err := os.ReadDir(dir)
+	require.Nil(t, err, "could not read split output directory")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	require.ElementsMatch(t, []string{"a.jsonl", "script.jsonl", "js.jsonl", "form.jsonl", "unknown.jsonl"}, names, "could not split output by source")
+
+	data, err := os.ReadFile(filepath.Join(dir, "a.jsonl"))
+	require.Nil(t, err, "could not read split output file")
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2, "could not write results with the same key to one file")
+	require.Contains(t, lines[1], `"endpoint":"https://example.com/other"`, "could not write result to split file")
+}
+
+func TestSplitOutputCSVHeader(t *testing.T) {
+	dir := t.TempDir()
+
+	standardWriter, err := NewWithOptions(&Options{OutputFile: dir, SplitBy: SplitByStatus, CSV: true, Silent: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/", StatusCode: 200}, nil), "could not write result")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/missing", StatusCode: 404}, nil), "could not write result")
+	require.Nil(t, standardWriter.Write(&Result{URL: "https://example.com/created", StatusCode: 201}, nil), "could not write result")
+	require.Nil(t, standardWriter.Close(), "could not close writer")
+
+	for file, rows := range map[string]int{"2xx.csv": 3, "4xx.csv": 2} {
+		data, err := os.ReadFile(filepath.Join(dir, file))
+		require.Nil(t, err, "could not read split output file")
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, rows, "could not write rows of %s", file)
+		require.True(t, strings.HasPrefix(lines[0], "timestamp,"), "could not write csv header to %s", file)
+	}
+}
+
+func TestSplitKey(t *testing.T) {
+	split := &splitOutput{splitBy: SplitByHost}
+	require.Equal(t, "example.com_8080", split.getKey(&Result{URL: "https://example.com:8080/path"}), "could not get host key")
+	require.Equal(t, "unknown", split.getKey(&Result{URL: "/relative"}), "could not get empty host key")
+	require.Equal(t, "unknown", sanitizeSplitKey(".."), "could not sanitize dot key")
+	require.Equal(t, "a_b_c", sanitizeSplitKey("a/b\\c"), "could not sanitize separators")
+
+	_, err := NewWithOptions(&Options{OutputFile: t.TempDir(), SplitBy: "invalid"})
+	require.NotNil(t, err, "could not validate split by")
+	_, err = NewWithOptions(&Options{OutputFile: t.TempDir(), SplitBy: SplitByHost, JSONArray: true})
+	require.NotNil(t, err, "could not reject split json array")
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"net/url"
+	"os"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+)
+
+// summary contains the counters of the results written by a crawl
+type summary struct {
+	TotalResults int            `json:"total_results"`
+	UniqueHosts  int            `json:"unique_hosts"`
+	StatusCodes  map[int]int    `json:"status_codes"`
+	Sources      map[string]int `json:"sources"`
+	Errors       int            `json:"errors,omitempty"`
+
+	hosts map[string]struct{}
+}
+
+// newSummary creates a new empty summary
+func newSummary() *summary {
+	return &summary{
+		StatusCodes: make(map[int]int),
+		Sources:     make(map[string]int),
+		hosts:       make(map[string]struct{}),
+	}
+}
+
+// update updates the summary counters with a result
+func (s *summary) update(event *Result) {
+	s.TotalResults++
+	if parsed, err := url.Parse(event.URL); err == nil && parsed.Host != "" {
+		if _, ok := s.hosts[parsed.Host]; !ok {
+			s.hosts[parsed.Host] = struct{}{}
+			s.UniqueHosts++
+		}
+	}
+	if event.Error != "" {
+		s.Errors++
+	}
+	if event.StatusCode != 0 {
+		s.StatusCodes[event.StatusCode]++
+	}
+	if event.Source != "" {
+		s.Sources[event.Source]++
+	}
+}
+
+// updateSummary updates the summary with a written result
+func (w *StandardWriter) updateSummary(event *Result) {
+	w.outputMutex.Lock()
+	w.summary.update(event)
+	w.outputMutex.Unlock()
+}
+
+// writeSummary writes the summary to the summary file, or to stderr if no
+// summary file is specified.
+func (w *StandardWriter) writeSummary() error {
+	w.outputMutex.Lock()
+	defer w.outputMutex.Unlock()
+
+	data, err := jsoniter.MarshalIndent(w.summary, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "could not marshal summary")
+	}
+	data = append(data, '\n')
+	if w.summaryFile == "" {
+		_, err = os.Stderr.Write(data)
+		return err
+	}
+	if err := os.WriteFile(w.summaryFile, data, 0644); err != nil {
+		return errors.Wrap(err, "could not write summary")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "summary.json")
+
+	writer, err := NewWithOptions(&Options{Summary: true, SummaryFile: file, MatchRegex: []string{"example"}})
+	require.Nil(t, err, "could not create writer")
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			URL := "https://a.example.com/" + strconv.Itoa(i)
+			if i%2 == 0 {
+				URL = "https://b.example.com/" + strconv.Itoa(i)
+			}
+			_ = writer.Write(&Result{URL: URL, Source: "https://example.com/", StatusCode: 200 + i%2}, nil)
+		}(i)
+	}
+	wg.Wait()
+	require.Nil(t, writer.Write(&Result{URL: "https://filtered.com/"}, nil), "could not write filtered result")
+	require.Nil(t, writer.Close(), "could not close writer")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read summary")
+
+	var decoded summary
+	require.Nil(t, jsoniter.Unmarshal(data, &decoded), "could not decode summary")
+	require.Equal(t, 20, decoded.TotalResults, "could not get total results")
+	require.Equal(t, 2, decoded.UniqueHosts, "could not get unique hosts")
+	require.Equal(t, map[int]int{200: 10, 201: 10}, decoded.StatusCodes, "could not get status codes")
+	require.Equal(t, map[string]int{"https://example.com/": 20}, decoded.Sources, "could not get sources")
+	require.Zero(t, decoded.Errors, "could not get errors")
+
+	errorSummary := newSummary()
+	errorSummary.update(&Result{URL: "https://example.com/", Error: "timeout"})
+	require.Equal(t, 1, errorSummary.Errors, "could not count error results")
+}
+
+func TestCountOnly(t *testing.T) {
+	screen := &screenWriter{}
+	gologger.DefaultLogger.SetWriter(screen)
+	defer gologger.DefaultLogger.SetWriter(writer.NewCLI())
+
+	dir := t.TempDir()
+	outputFile := filepath.Join(dir, "output.txt")
+	summaryFile := filepath.Join(dir, "summary.json")
+
+	countWriter, err := NewWithOptions(&Options{CountOnly: true, JSON: true, OutputFile: outputFile, SummaryFile: summaryFile, StoreResponse: true, StoreResponseDir: filepath.Join(dir, "responses")})
+	require.Nil(t, err, "could not create writer")
+	for _, statusCode := range []int{200, 200, 404} {
+		require.Nil(t, countWriter.Write(&Result{URL: "https://example.com/", Source: "a", StatusCode: statusCode}, nil), "could not write result")
+	}
+	require.Nil(t, countWriter.Close(), "could not close writer")
+
+	require.Empty(t, screen.data, "could not skip screen output")
+	require.NoFileExists(t, outputFile, "could not skip output file")
+	require.NoDirExists(t, filepath.Join(dir, "responses"), "could not skip stored responses")
+
+	data, err := os.ReadFile(summaryFile)
+	require.Nil(t, err, "could not read summary")
+	var decoded summary
+	require.Nil(t, jsoniter.Unmarshal(data, &decoded), "could not decode summary")
+	require.Equal(t, 3, decoded.TotalResults, "could not count results")
+	require.Equal(t, map[int]int{200: 2, 404: 1}, decoded.StatusCodes, "could not count status codes")
+	require.Equal(t, map[string]int{"a": 3}, decoded.Sources, "could not count sources")
+}
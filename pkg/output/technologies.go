@@ -0,0 +1,136 @@
+package output
+
+import (
+	"bytes"
+	"net/http"
+	"sort"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// technologyHeaders maps the response headers revealing a technology by
+// their presence to the technology name
+var technologyHeaders = map[string]string{
+	"Cf-Ray":                 "Cloudflare",
+	"X-Amz-Cf-Id":            "Amazon CloudFront",
+	"X-Aspnet-Version":       "ASP.NET",
+	"X-Aspnetmvc-Version":    "ASP.NET MVC",
+	"X-Drupal-Cache":         "Drupal",
+	"X-Drupal-Dynamic-Cache": "Drupal",
+	"X-Shopify-Stage":        "Shopify",
+	"X-Vercel-Id":            "Vercel",
+	"X-Sucuri-Id":            "Sucuri",
+	"X-Akamai-Transformed":   "Akamai",
+	"X-Varnish":              "Varnish",
+}
+
+// technologyCookies maps the cookie name prefixes revealing a technology
+// to the technology name
+var technologyCookies = map[string]string{
+	"PHPSESSID":         "PHP",
+	"JSESSIONID":        "Java",
+	"ASP.NET_SessionId": "ASP.NET",
+	"laravel_session":   "Laravel",
+	"ci_session":        "CodeIgniter",
+	"wordpress_":        "WordPress",
+	"wp-settings-":      "WordPress",
+	"__cf_bm":           "Cloudflare",
+	"incap_ses_":        "Imperva Incapsula",
+	"AWSALB":            "AWS Elastic Load Balancing",
+}
+
+// detectTechnologies returns the sorted technologies fingerprinted from
+// the headers, cookies and HTML generator of the response
+func detectTechnologies(resp *http.Response) []string {
+	detected := make(map[string]struct{})
+	for _, header := range []string{"Server", "X-Powered-By", "X-Generator"} {
+		for _, value := range resp.Header.Values(header) {
+			for _, product := range getProductNames(value) {
+				detected[product] = struct{}{}
+			}
+		}
+	}
+	for header, technology := range technologyHeaders {
+		if resp.Header.Get(header) != "" {
+			detected[technology] = struct{}{}
+		}
+	}
+	for _, cookie := range resp.Cookies() {
+		for prefix, technology := range technologyCookies {
+			if strings.HasPrefix(cookie.Name, prefix) {
+				detected[technology] = struct{}{}
+			}
+		}
+	}
+	if isHTMLContentType(getMediaType(resp.Header.Get("Content-Type"))) {
+		if generator := getHTMLGenerator(readResponseBody(resp)); generator != "" {
+			detected[generator] = struct{}{}
+		}
+	}
+	if len(detected) == 0 {
+		return nil
+	}
+	technologies := make([]string, 0, len(detected))
+	for technology := range detected {
+		technologies = append(technologies, technology)
+	}
+	sort.Strings(technologies)
+	return technologies
+}
+
+// getProductNames returns the product names without versions of a header
+// value like "Apache/2.4.1 (Unix) PHP/8.1" or "Express, Next.js".
+func getProductNames(value string) []string {
+	var products []string
+	for _, token := range strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' }) {
+		if strings.HasPrefix(token, "(") || strings.HasSuffix(token, ")") {
+			continue
+		}
+		if index := strings.IndexByte(token, '/'); index != -1 {
+			token = token[:index]
+		}
+		if token != "" {
+			products = append(products, token)
+		}
+	}
+	return products
+}
+
+// getHTMLGenerator returns the product name of the generator meta tag of
+// an HTML document, stopping at the body of the document.
+func getHTMLGenerator(body []byte) string {
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttributes := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Meta:
+				var isGenerator bool
+				var content string
+				for hasAttributes {
+					var key, value []byte
+					key, value, hasAttributes = tokenizer.TagAttr()
+					switch string(key) {
+					case "name":
+						isGenerator = strings.EqualFold(string(value), "generator")
+					case "content":
+						content = string(value)
+					}
+				}
+				if isGenerator {
+					if fields := strings.Fields(content); len(fields) > 0 {
+						return fields[0]
+					}
+					return ""
+				}
+			case atom.Body:
+				return ""
+			}
+		}
+	}
+}
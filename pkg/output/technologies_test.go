@@ -0,0 +1,30 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectTechnologies(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{
+			"Server":       []string{"Apache/2.4.41 (Ubuntu)"},
+			"X-Powered-By": []string{"PHP/7.4.3"},
+			"Cf-Ray":       []string{"6f1e2a3b4c5d-AMS"},
+			"Set-Cookie":   []string{"laravel_session=abc; path=/"},
+			"Content-Type": []string{"text/html; charset=utf-8"},
+		},
+		Body: io.NopCloser(bytes.NewReader([]byte(`<html><head><meta name="generator" content="WordPress 6.1"></head><body></body></html>`))),
+	}
+	require.Equal(t, []string{"Apache", "Cloudflare", "Laravel", "PHP", "WordPress"}, detectTechnologies(resp), "could not detect technologies")
+	require.Nil(t, detectTechnologies(&http.Response{Header: http.Header{}}), "could not get empty technologies")
+}
+
+func TestGetProductNames(t *testing.T) {
+	require.Equal(t, []string{"Microsoft-IIS"}, getProductNames("Microsoft-IIS/10.0"), "could not get product name")
+	require.Equal(t, []string{"Express", "Next.js"}, getProductNames("Express, Next.js"), "could not get product names")
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// throttle is a token bucket limiting the number of results written per
+// second, it allows a burst of one second of results.
+type throttle struct {
+	rate float64
+
+	mutex  *sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newThrottle creates a new throttle for the results per second,
+// returning nil if throttling is disabled.
+func newThrottle(perSecond int) *throttle {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &throttle{
+		rate:   float64(perSecond),
+		mutex:  &sync.Mutex{},
+		tokens: float64(perSecond),
+		last:   time.Now(),
+	}
+}
+
+// wait takes a token, blocking until it is available or the context is
+// done. The tokens are reserved in the order of the calls, so the waiting
+// results are released in order.
+func (t *throttle) wait(ctx context.Context) error {
+	t.mutex.Lock()
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.rate {
+		t.tokens = t.rate
+	}
+	t.last = now
+	t.tokens--
+	tokens := t.tokens
+	t.mutex.Unlock()
+
+	if tokens >= 0 {
+		return nil
+	}
+	timer := time.NewTimer(time.Duration(-tokens / t.rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		// give back the token reserved for the cancelled write
+		t.mutex.Lock()
+		t.tokens++
+		t.mutex.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,48 @@
+package output
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleWriter(t *testing.T) {
+	var results []string
+	writer, err := NewWithOptions(&Options{
+		MaxResultsPerSecond: 20,
+		OnResult: func(result *Result) {
+			results = append(results, result.URL)
+		},
+	})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	var expected []string
+	start := time.Now()
+	for i := 0; i < 25; i++ {
+		url := "https://example.com/" + string(rune('a'+i))
+		expected = append(expected, url)
+		require.Nil(t, writer.Write(&Result{URL: url}, nil), "could not write result")
+	}
+	require.GreaterOrEqual(t, time.Since(start), 200*time.Millisecond, "could not throttle results over the burst")
+	require.Equal(t, expected, results, "could not keep order of throttled results")
+}
+
+func TestThrottleContext(t *testing.T) {
+	writer, err := NewWithOptions(&Options{MaxResultsPerSecond: 1})
+	require.Nil(t, err, "could not create writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a"}, nil), "could not write result")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = writer.(ContextWriter).WriteContext(ctx, &Result{URL: "https://example.com/b"}, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded, "could not cancel throttled write")
+}
+
+func TestThrottleDisabled(t *testing.T) {
+	require.Nil(t, newThrottle(0), "got throttle without limit")
+}
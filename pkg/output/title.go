@@ -0,0 +1,37 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// isHTMLContentType returns true if the media type is of an HTML document
+func isHTMLContentType(contentType string) bool {
+	return contentType == "text/html" || contentType == "application/xhtml+xml"
+}
+
+// getHTMLTitle returns the title of an HTML document using the tokenizer
+// to avoid building the complete DOM of the page.
+func getHTMLTitle(body []byte) string {
+	tokenizer := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return ""
+		case html.StartTagToken:
+			name, _ := tokenizer.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				if tokenizer.Next() == html.TextToken {
+					return strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+				}
+				return ""
+			case atom.Body:
+				return ""
+			}
+		}
+	}
+}
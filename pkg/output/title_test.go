@@ -0,0 +1,23 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetHTMLTitle(t *testing.T) {
+	tests := []struct {
+		body  string
+		title string
+	}{
+		{"<html><head><title>Home</title></head></html>", "Home"},
+		{"<html><head><TITLE>\n  Login &amp; Register\n</TITLE></head></html>", "Login & Register"},
+		{"<html><head><title></title></head></html>", ""},
+		{"<html><body><svg><title>icon</title></svg></body></html>", ""},
+		{"no html", ""},
+	}
+	for _, test := range tests {
+		require.Equal(t, test.title, getHTMLTitle([]byte(test.body)), "could not get title for %s", test.body)
+	}
+}
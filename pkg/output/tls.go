@@ -0,0 +1,48 @@
+package output
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// TLS contains the details of the leaf certificate of a HTTPS response
+type TLS struct {
+	// SubjectCN is the common name of the certificate subject
+	SubjectCN string `json:"subject_cn,omitempty"`
+	// SubjectOrg is the organization of the certificate subject
+	SubjectOrg []string `json:"subject_org,omitempty"`
+	// Issuer is the common name of the certificate issuer, or the whole
+	// issuer name if it has no common name.
+	Issuer string `json:"issuer,omitempty"`
+	// NotBefore is the time the certificate is valid from
+	NotBefore time.Time `json:"not_before,omitempty"`
+	// NotAfter is the time the certificate expires at
+	NotAfter time.Time `json:"not_after,omitempty"`
+	// SANs contains the DNS names and IP addresses of the certificate
+	// subject alternative names
+	SANs []string `json:"sans,omitempty"`
+}
+
+// getTLSDetails returns the details of the leaf certificate of the
+// connection, or nil for plain HTTP connections.
+func getTLSDetails(state *tls.ConnectionState) *TLS {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+	certificate := state.PeerCertificates[0]
+	details := &TLS{
+		SubjectCN:  certificate.Subject.CommonName,
+		SubjectOrg: certificate.Subject.Organization,
+		Issuer:     certificate.Issuer.CommonName,
+		NotBefore:  certificate.NotBefore,
+		NotAfter:   certificate.NotAfter,
+	}
+	if details.Issuer == "" {
+		details.Issuer = certificate.Issuer.String()
+	}
+	details.SANs = append(details.SANs, certificate.DNSNames...)
+	for _, ip := range certificate.IPAddresses {
+		details.SANs = append(details.SANs, ip.String())
+	}
+	return details
+}
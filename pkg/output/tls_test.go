@@ -0,0 +1,41 @@
+package output
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/projectdiscovery/gologger"
+	"github.com/projectdiscovery/gologger/writer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL)
+	require.Nil(t, err, "could not get https response")
+	resp.Body.Close()
+
+	details := getTLSDetails(resp.TLS)
+	require.NotNil(t, details, "could not get tls details")
+	require.Contains(t, details.SANs, "example.com", "could not get dns names")
+	require.Contains(t, details.SANs, "127.0.0.1", "could not get ip addresses")
+	require.Equal(t, []string{"Acme Co"}, details.SubjectOrg, "could not get subject organization")
+	require.False(t, details.NotAfter.IsZero(), "could not get expiry")
+	require.Nil(t, getTLSDetails(nil), "could not get nil details for plain http")
+
+	screen := &screenWriter{}
+	gologger.DefaultLogger.SetWriter(screen)
+	defer gologger.DefaultLogger.SetWriter(writer.NewCLI())
+
+	standardWriter, err := NewWithOptions(&Options{JSONL: true, CaptureTLS: true, ValidateOutput: true})
+	require.Nil(t, err, "could not create writer")
+	require.Nil(t, standardWriter.Write(&Result{URL: server.URL}, resp), "could not write https result")
+	plain := &http.Response{StatusCode: 200, Header: http.Header{}, Request: resp.Request}
+	require.Nil(t, standardWriter.Write(&Result{URL: "http://example.com"}, plain), "could not write http result")
+	require.Len(t, screen.data, 2, "could not write results")
+	require.Contains(t, screen.data[0], `"tls":{"subject_org":["Acme Co"]`, "could not write tls details")
+	require.NotContains(t, screen.data[1], `"tls"`, "could not omit tls details for plain http")
+}
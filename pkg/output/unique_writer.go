@@ -0,0 +1,126 @@
+package output
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// UniqueOptions contains the URL normalization options of the unique writer
+type UniqueOptions struct {
+	// IncludeQuery specifies to include the query parameters in the
+	// normalized URL. The parameters are sorted so their order does
+	// not matter.
+	IncludeQuery bool
+	// CaseSensitiveHost specifies to not case-fold the host in the
+	// normalized URL.
+	CaseSensitiveHost bool
+}
+
+// uniqueWriter is a writer which only forwards the first result of each
+// normalized URL to the underlying writer
+type uniqueWriter struct {
+	writer  Writer
+	options UniqueOptions
+
+	mutex *sync.Mutex
+	seen  map[string]struct{}
+}
+
+// UniqueWriter returns a writer that only forwards the first occurrence of
+// each URL to the writer, ignoring the query parameters of the URLs.
+func UniqueWriter(w Writer) Writer {
+	return UniqueWriterWithOptions(w, UniqueOptions{})
+}
+
+// UniqueWriterWithOptions returns a writer that only forwards the first
+// occurrence of each URL normalized with the options to the writer.
+//
+// Writes without a result, like the responses of the seed URLs, are
+// always forwarded.
+func UniqueWriterWithOptions(w Writer, options UniqueOptions) Writer {
+	return &uniqueWriter{
+		writer:  w,
+		options: options,
+		mutex:   &sync.Mutex{},
+		seen:    make(map[string]struct{}),
+	}
+}
+
+// Write writes the event to the writer if its URL was not written before
+func (u *uniqueWriter) Write(event *Result, resp *http.Response) error {
+	return u.WriteContext(context.Background(), event, resp)
+}
+
+// WriteContext writes the event to the writer if its URL was not written
+// before, returning the context error as soon as the context is done.
+func (u *uniqueWriter) WriteContext(ctx context.Context, event *Result, resp *http.Response) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if event != nil && !u.isUnique(event.URL) {
+		return nil
+	}
+	if contextWriter, ok := u.writer.(ContextWriter); ok {
+		return contextWriter.WriteContext(ctx, event, resp)
+	}
+	return u.writer.Write(event, resp)
+}
+
+// isUnique returns true if the normalized URL has not been seen before
+func (u *uniqueWriter) isUnique(URL string) bool {
+	key := normalizeUniqueURL(URL, u.options)
+
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	if _, ok := u.seen[key]; ok {
+		return false
+	}
+	u.seen[key] = struct{}{}
+	return true
+}
+
+// Flush flushes the writer
+func (u *uniqueWriter) Flush() error {
+	return u.writer.Flush()
+}
+
+// Close closes the writer
+func (u *uniqueWriter) Close() error {
+	return u.writer.Close()
+}
+
+// normalizeUniqueURL returns the scheme, host and path of the URL with
+// the sorted query parameters if they are included.
+//
+// URLs which can't be parsed are used as is.
+func normalizeUniqueURL(URL string, options UniqueOptions) string {
+	URL = strings.TrimSpace(URL)
+	parsed, err := url.Parse(URL)
+	if err != nil || parsed.Host == "" {
+		return URL
+	}
+	host := parsed.Host
+	if !options.CaseSensitiveHost {
+		host = strings.ToLower(host)
+	}
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	builder := &strings.Builder{}
+	builder.WriteString(strings.ToLower(parsed.Scheme))
+	builder.WriteString("://")
+	builder.WriteString(host)
+	builder.WriteString(path)
+	if options.IncludeQuery && parsed.RawQuery != "" {
+		// Encode sorts the query parameters by key
+		builder.WriteRune('?')
+		builder.WriteString(parsed.Query().Encode())
+	}
+	return builder.String()
+}
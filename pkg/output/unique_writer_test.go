@@ -0,0 +1,51 @@
+package output
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniqueWriter(t *testing.T) {
+	mock := &mockWriter{}
+	writer := UniqueWriter(mock)
+
+	for _, URL := range []string{
+		"https://example.com/a?x=1",
+		"https://EXAMPLE.com/a?y=2",
+		"https://example.com/b",
+		"https://example.com",
+		"https://example.com/",
+	} {
+		require.Nil(t, writer.Write(&Result{URL: URL}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Write(nil, nil), "could not write empty result")
+
+	var urls []string
+	for _, result := range mock.results {
+		if result != nil {
+			urls = append(urls, result.URL)
+		}
+	}
+	require.Equal(t, []string{"https://example.com/a?x=1", "https://example.com/b", "https://example.com"}, urls, "could not get unique urls")
+	require.Len(t, mock.results, 4, "could not forward write without result")
+
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.True(t, mock.closed, "could not close underlying writer")
+}
+
+func TestUniqueWriterQuery(t *testing.T) {
+	mock := &mockWriter{}
+	writer := UniqueWriterWithOptions(mock, UniqueOptions{IncludeQuery: true})
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a?x=1&y=2"}, nil), "could not write result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a?y=2&x=1"}, nil), "could not write reordered result")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/a?x=2&y=2"}, nil), "could not write result")
+	require.Len(t, mock.results, 2, "could not ignore reordered query parameters")
+}
+
+func TestNormalizeUniqueURL(t *testing.T) {
+	require.Equal(t, "https://example.com/a", normalizeUniqueURL(" HTTPS://Example.COM/a?b=1 ", UniqueOptions{}), "could not normalize url")
+	require.Equal(t, "https://Example.COM/a?a=2&b=1", normalizeUniqueURL("https://Example.COM/a?b=1&a=2", UniqueOptions{IncludeQuery: true, CaseSensitiveHost: true}), "could not normalize url with options")
+	require.Equal(t, "not a url", normalizeUniqueURL("not a url", UniqueOptions{}), "could not keep invalid url")
+}
@@ -0,0 +1,271 @@
+package output
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	// DefaultUnixSocketQueueSize is the default number of results queued
+	// for the unix socket
+	DefaultUnixSocketQueueSize = 1000
+	// maxUnixSocketBackoff is the maximum wait between reconnections
+	maxUnixSocketBackoff = 5 * time.Second
+)
+
+// UnixSocketOptions contains the options of the unix socket writer
+type UnixSocketOptions struct {
+	// Path is the path of the unix socket
+	Path string
+	// Listen specifies to listen on the socket and stream the results to
+	// the last connected client, instead of connecting to the socket.
+	Listen bool
+	// QueueSize is the number of results queued in memory while the
+	// socket is not connected, the default is used if it is not positive.
+	// The results are dropped once the queue is full.
+	QueueSize int
+}
+
+// unixSocketWriter is a writer which streams the results as JSONL to a
+// unix socket from a background goroutine.
+type unixSocketWriter struct {
+	connected int32
+
+	options UnixSocketOptions
+	// retryBackoff is the wait before the first reconnection, it is
+	// doubled after each failed reconnection.
+	retryBackoff time.Duration
+
+	queue   chan unixSocketItem
+	done    chan struct{}
+	closing chan struct{}
+	closed  bool
+	mutex   *sync.RWMutex
+	// dropWarning and lostWarning show the warnings once
+	dropWarning *sync.Once
+	lostWarning *sync.Once
+
+	// conn is the current connection, only used by the sender goroutine
+	conn     net.Conn
+	listener net.Listener
+	accepted chan net.Conn
+}
+
+// unixSocketItem is a queued line, or a flush request if flushed is set
+type unixSocketItem struct {
+	line    []byte
+	flushed chan struct{}
+}
+
+// NewUnixSocketWriter returns a writer which streams the results as JSONL
+// to a unix socket for co-located processes.
+//
+// The connection is reestablished with backoff when it is lost, while the
+// results are kept in a bounded queue and dropped once it is full, so a
+// missing reader never blocks the crawl.
+func NewUnixSocketWriter(options UnixSocketOptions) (Writer, error) {
+	if options.Path == "" {
+		return nil, errors.New("no unix socket path specified")
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultUnixSocketQueueSize
+	}
+	writer := &unixSocketWriter{
+		options:      options,
+		retryBackoff: 100 * time.Millisecond,
+		queue:        make(chan unixSocketItem, options.QueueSize),
+		done:         make(chan struct{}),
+		closing:      make(chan struct{}),
+		mutex:        &sync.RWMutex{},
+		dropWarning:  &sync.Once{},
+		lostWarning:  &sync.Once{},
+	}
+	if options.Listen {
+		listener, err := net.Listen("unix", options.Path)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not listen on unix socket")
+		}
+		writer.listener = listener
+		writer.accepted = make(chan net.Conn, 1)
+		go writer.accept()
+	}
+	go writer.run()
+	return writer, nil
+}
+
+// Write queues the event as a JSONL line for the socket, dropping it if
+// the queue is full
+func (w *unixSocketWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	line, err := jsoniter.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal unix socket result")
+	}
+	line = append(line, '\n')
+
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return errors.New("unix socket writer is closed")
+	}
+	select {
+	case w.queue <- unixSocketItem{line: line}:
+	default:
+		w.dropWarning.Do(func() {
+			gologger.Warning().Msgf("Unix socket queue is full, dropping results\n")
+		})
+	}
+	return nil
+}
+
+// run sends the queued lines until the queue is closed
+func (w *unixSocketWriter) run() {
+	defer close(w.done)
+	defer w.closeConn()
+
+	for item := range w.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		w.send(item.line)
+	}
+}
+
+// send writes the line to the socket, reconnecting with backoff until it
+// is written. The line is dropped if the socket is not connected while
+// the writer is closing.
+func (w *unixSocketWriter) send(line []byte) {
+	backoff := w.retryBackoff
+	for {
+		conn, err := w.getConn()
+		if err == nil {
+			if _, err = conn.Write(line); err == nil {
+				return
+			}
+			w.closeConn()
+		}
+		w.lostWarning.Do(func() {
+			gologger.Warning().Msgf("Could not write to unix socket, reconnecting: %s\n", err)
+		})
+		select {
+		case <-w.closing:
+			return
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxUnixSocketBackoff {
+			backoff = maxUnixSocketBackoff
+		}
+	}
+}
+
+// getConn returns the current connection, connecting to the socket or
+// switching to the last accepted client
+func (w *unixSocketWriter) getConn() (net.Conn, error) {
+	if w.options.Listen {
+		select {
+		case conn := <-w.accepted:
+			w.closeConn()
+			w.setConn(conn)
+		default:
+		}
+		if w.conn == nil {
+			return nil, errors.New("no unix socket client connected")
+		}
+		return w.conn, nil
+	}
+	if w.conn == nil {
+		conn, err := net.Dial("unix", w.options.Path)
+		if err != nil {
+			return nil, err
+		}
+		w.setConn(conn)
+	}
+	return w.conn, nil
+}
+
+// accept accepts the clients of the socket until the listener is closed
+func (w *unixSocketWriter) accept() {
+	for {
+		conn, err := w.listener.Accept()
+		if err != nil {
+			return
+		}
+		// only the last client is kept, replacing a pending one
+		select {
+		case previous := <-w.accepted:
+			previous.Close()
+		default:
+		}
+		w.accepted <- conn
+	}
+}
+
+func (w *unixSocketWriter) setConn(conn net.Conn) {
+	w.conn = conn
+	atomic.StoreInt32(&w.connected, 1)
+}
+
+func (w *unixSocketWriter) closeConn() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn = nil
+	}
+	atomic.StoreInt32(&w.connected, 0)
+}
+
+// Flush waits until the queued lines are written. It returns immediately
+// if the socket is not connected, as the lines are only written once it
+// is connected again.
+func (w *unixSocketWriter) Flush() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed || atomic.LoadInt32(&w.connected) == 0 {
+		return nil
+	}
+	flushed := make(chan struct{})
+	w.queue <- unixSocketItem{flushed: flushed}
+	<-flushed
+	return nil
+}
+
+// Close writes the queued lines if the socket is connected and stops the
+// writer, removing the socket file in listen mode
+func (w *unixSocketWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.closing)
+	close(w.queue)
+	w.mutex.Unlock()
+
+	<-w.done
+	if w.listener == nil {
+		return nil
+	}
+	err := w.listener.Close()
+	select {
+	case conn := <-w.accepted:
+		conn.Close()
+	default:
+	}
+	if removeErr := os.Remove(w.options.Path); removeErr != nil && !os.IsNotExist(removeErr) && err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+package output
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+// unixSocketLine is a line read by the test server from a connection
+type unixSocketLine struct {
+	conn int
+	url  string
+}
+
+func TestUnixSocketWriterReconnect(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "katana.sock")
+	listener, err := net.Listen("unix", path)
+	require.Nil(t, err, "could not listen on unix socket")
+	defer listener.Close()
+
+	lines := make(chan unixSocketLine, 100)
+	conns := make(chan net.Conn, 10)
+	go func() {
+		for i := 0; ; i++ {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+			go func(i int, conn net.Conn) {
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var result Result
+					if jsoniter.Unmarshal(scanner.Bytes(), &result) == nil {
+						lines <- unixSocketLine{conn: i, url: result.URL}
+					}
+				}
+			}(i, conn)
+		}
+	}()
+
+	writer, err := NewUnixSocketWriter(UnixSocketOptions{Path: path})
+	require.Nil(t, err, "could not create unix socket writer")
+	defer writer.Close()
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/first"}, nil), "could not write result")
+	line := <-lines
+	require.Equal(t, unixSocketLine{conn: 0, url: "https://example.com/first"}, line, "could not stream result")
+
+	// close the first connection, the next results are written after
+	// reconnecting
+	(<-conns).Close()
+	timeout := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/" + strconv.Itoa(i)}, nil), "could not write result")
+		select {
+		case line := <-lines:
+			require.Equal(t, 1, line.conn, "could not reconnect to unix socket")
+			require.Nil(t, writer.Close(), "could not close writer")
+			return
+		case <-timeout:
+			t.Fatal("could not stream results after reconnecting")
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+func TestUnixSocketWriterListen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "katana.sock")
+
+	writer, err := NewUnixSocketWriter(UnixSocketOptions{Path: path, Listen: true})
+	require.Nil(t, err, "could not create unix socket writer")
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+
+	conn, err := net.Dial("unix", path)
+	require.Nil(t, err, "could not connect to unix socket")
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	require.True(t, scanner.Scan(), "could not read result")
+	var result Result
+	require.Nil(t, jsoniter.Unmarshal(scanner.Bytes(), &result), "could not parse result")
+	require.Equal(t, "https://example.com/", result.URL, "could not stream result")
+	require.Nil(t, writer.Close(), "could not close writer")
+	require.Nil(t, writer.Close(), "could not close writer twice")
+
+	_, err = net.Dial("unix", path)
+	require.NotNil(t, err, "could not stop listening")
+}
+
+func TestUnixSocketWriterDisconnected(t *testing.T) {
+	writer, err := NewUnixSocketWriter(UnixSocketOptions{Path: filepath.Join(t.TempDir(), "missing.sock"), QueueSize: 2})
+	require.Nil(t, err, "could not create unix socket writer")
+	for i := 0; i < 5; i++ {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not drop result")
+	}
+	require.Nil(t, writer.Flush(), "could not flush disconnected writer")
+	require.Nil(t, writer.Close(), "could not close disconnected writer")
+}
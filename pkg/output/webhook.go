@@ -0,0 +1,268 @@
+package output
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	// DefaultWebhookQueueSize is the default number of results queued for
+	// the webhook
+	DefaultWebhookQueueSize = 1000
+	// DefaultWebhookMaxRetries is the default number of retries of the
+	// failed webhook requests
+	DefaultWebhookMaxRetries = 3
+	// DefaultWebhookTimeout is the default timeout of a webhook request
+	DefaultWebhookTimeout = 10 * time.Second
+)
+
+// WebhookOptions contains the options of the webhook writer
+type WebhookOptions struct {
+	// URL is the endpoint to POST the results to
+	URL string
+	// BatchSize is the maximum number of results sent in a request. The
+	// results are sent as a JSON object if it is not greater than 1 and
+	// as a JSON array of objects otherwise.
+	BatchSize int
+	// Headers are the headers sent with the requests, like the
+	// authentication headers.
+	Headers map[string]string
+	// QueueSize is the number of results queued in memory for the
+	// webhook, the default is used if it is not positive.
+	QueueSize int
+	// DropWhenFull specifies to drop the results when the queue is full
+	// instead of blocking the crawl until there is room.
+	DropWhenFull bool
+	// MaxRetries is the number of times a request failing with a 5xx
+	// status is retried, the default is used if it is not positive.
+	MaxRetries int
+	// Timeout is the timeout of a request, the default is used if it is
+	// not positive.
+	Timeout time.Duration
+	// Client is the http client to send the requests with, a client with
+	// the timeout is used if it is nil.
+	Client *http.Client
+}
+
+// webhookWriter is a writer which queues the results and POSTs them to a
+// webhook from a background goroutine.
+type webhookWriter struct {
+	options WebhookOptions
+	// retryBackoff is the wait before the first retry, it is doubled
+	// after each retry.
+	retryBackoff time.Duration
+
+	queue  chan webhookItem
+	done   chan struct{}
+	closed bool
+	mutex  *sync.RWMutex
+	// dropWarning shows the dropped results warning once
+	dropWarning *sync.Once
+}
+
+// webhookItem is a queued result, or a flush request if flushed is set
+type webhookItem struct {
+	result  *Result
+	flushed chan struct{}
+}
+
+// NewWebhookWriter returns a writer which POSTs the results as JSON to
+// the webhook URL.
+//
+// The results are sent from a bounded in-memory queue, so a slow webhook
+// blocks the crawl only once the queue is full, or never if the results
+// are dropped when it is full. Requests failing with a 5xx status are
+// retried with exponential backoff, the failures are logged.
+func NewWebhookWriter(options WebhookOptions) (Writer, error) {
+	if options.URL == "" {
+		return nil, errors.New("no webhook url specified")
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1
+	}
+	if options.QueueSize <= 0 {
+		options.QueueSize = DefaultWebhookQueueSize
+	}
+	if options.MaxRetries <= 0 {
+		options.MaxRetries = DefaultWebhookMaxRetries
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultWebhookTimeout
+	}
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: options.Timeout}
+	}
+	writer := &webhookWriter{
+		options:      options,
+		retryBackoff: 500 * time.Millisecond,
+		queue:        make(chan webhookItem, options.QueueSize),
+		done:         make(chan struct{}),
+		mutex:        &sync.RWMutex{},
+		dropWarning:  &sync.Once{},
+	}
+	go writer.run()
+	return writer, nil
+}
+
+// Write queues the event for the webhook
+func (w *webhookWriter) Write(event *Result, _ *http.Response) error {
+	if event == nil {
+		return nil
+	}
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return errors.New("webhook writer is closed")
+	}
+	if !w.options.DropWhenFull {
+		w.queue <- webhookItem{result: event}
+		return nil
+	}
+	select {
+	case w.queue <- webhookItem{result: event}:
+	default:
+		w.dropWarning.Do(func() {
+			gologger.Warning().Msgf("Webhook queue is full, dropping results\n")
+		})
+	}
+	return nil
+}
+
+// run sends the queued results until the queue is closed.
+//
+// The results are batched while more results are queued, so a batch is
+// sent as soon as the queue is empty.
+func (w *webhookWriter) run() {
+	defer close(w.done)
+
+	var batch []*Result
+	for {
+		var item webhookItem
+		var ok bool
+		select {
+		case item, ok = <-w.queue:
+		default:
+			// the queue is empty, send the pending results before
+			// waiting for the next one
+			batch = w.sendBatch(batch)
+			item, ok = <-w.queue
+		}
+		if !ok {
+			w.sendBatch(batch)
+			return
+		}
+		if item.flushed != nil {
+			batch = w.sendBatch(batch)
+			close(item.flushed)
+			continue
+		}
+		batch = append(batch, item.result)
+		if len(batch) >= w.options.BatchSize {
+			batch = w.sendBatch(batch)
+		}
+	}
+}
+
+// sendBatch sends the batch, logging the failures, and returns the empty
+// batch for reuse
+func (w *webhookWriter) sendBatch(batch []*Result) []*Result {
+	if len(batch) == 0 {
+		return batch
+	}
+	if err := w.send(batch); err != nil {
+		gologger.Warning().Msgf("Could not send %d results to webhook: %s\n", len(batch), err)
+	}
+	return batch[:0]
+}
+
+// send POSTs the results to the webhook, retrying on 5xx statuses and
+// request errors with backoff
+func (w *webhookWriter) send(batch []*Result) error {
+	var body []byte
+	var err error
+	if w.options.BatchSize > 1 {
+		body, err = jsoniter.Marshal(batch)
+	} else {
+		body, err = jsoniter.Marshal(batch[0])
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not marshal webhook results")
+	}
+
+	backoff := w.retryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := w.post(body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= w.options.MaxRetries {
+			return err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends the body to the webhook, returning whether a failed request
+// can be retried
+func (w *webhookWriter) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.options.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, errors.Wrap(err, "could not create webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range w.options.Headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := w.options.Client.Do(req)
+	if err != nil {
+		return true, errors.Wrap(err, "could not send webhook request")
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true, errors.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return false, errors.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// Flush waits until the queued results are sent
+func (w *webhookWriter) Flush() error {
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+
+	if w.closed {
+		return nil
+	}
+	flushed := make(chan struct{})
+	w.queue <- webhookItem{flushed: flushed}
+	<-flushed
+	return nil
+}
+
+// Close sends the queued results and stops the writer
+func (w *webhookWriter) Close() error {
+	w.mutex.Lock()
+	if w.closed {
+		w.mutex.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mutex.Unlock()
+
+	<-w.done
+	return nil
+}
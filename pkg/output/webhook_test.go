@@ -0,0 +1,108 @@
+package output
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookWriter(t *testing.T) {
+	var mutex sync.Mutex
+	var urls []string
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("X-Token") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		var results []Result
+		if err := jsoniter.Unmarshal(data, &results); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for _, result := range results {
+			urls = append(urls, result.URL)
+		}
+	}))
+	defer server.Close()
+
+	writer, err := NewWebhookWriter(WebhookOptions{URL: server.URL, BatchSize: 2, Headers: map[string]string{"X-Token": "secret"}})
+	require.Nil(t, err, "could not create webhook writer")
+	writer.(*webhookWriter).retryBackoff = 0
+
+	for _, URL := range []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"} {
+		require.Nil(t, writer.Write(&Result{URL: URL}, nil), "could not write result")
+	}
+	require.Nil(t, writer.Flush(), "could not flush webhook writer")
+	mutex.Lock()
+	require.Equal(t, []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"}, urls, "could not send results after retry")
+	mutex.Unlock()
+
+	require.Nil(t, writer.Close(), "could not close webhook writer")
+	require.NotNil(t, writer.Write(&Result{URL: "https://example.com/d"}, nil), "could write to closed writer")
+}
+
+func TestWebhookWriterDropWhenFull(t *testing.T) {
+	block := make(chan struct{})
+	var mutex sync.Mutex
+	var received int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+		mutex.Lock()
+		received++
+		mutex.Unlock()
+	}))
+	defer server.Close()
+
+	writer, err := NewWebhookWriter(WebhookOptions{URL: server.URL, QueueSize: 1, DropWhenFull: true})
+	require.Nil(t, err, "could not create webhook writer")
+
+	// the writes don't block while the webhook is not responding
+	for i := 0; i < 10; i++ {
+		require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	}
+	close(block)
+	require.Nil(t, writer.Close(), "could not close webhook writer")
+	require.Less(t, received, 10, "could not drop results when queue is full")
+	require.Greater(t, received, 0, "could not send queued results")
+}
+
+func TestWebhookWriterTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		attempts++
+		mutex.Unlock()
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	writer, err := NewWebhookWriter(WebhookOptions{URL: server.URL, MaxRetries: 1, Timeout: 50 * time.Millisecond})
+	require.Nil(t, err, "could not create webhook writer")
+	writer.(*webhookWriter).retryBackoff = 0
+
+	require.Nil(t, writer.Write(&Result{URL: "https://example.com/"}, nil), "could not write result")
+	require.Nil(t, writer.Flush(), "could not flush webhook writer")
+	require.Nil(t, writer.Close(), "could not close webhook writer")
+	mutex.Lock()
+	defer mutex.Unlock()
+	require.Equal(t, 2, attempts, "could not retry timed out request")
+}
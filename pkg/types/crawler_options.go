@@ -31,6 +31,39 @@ type CrawlerOptions struct {
 	Dialer *fastdialer.Dialer
 }
 
+// newResultWriters creates the webhook and unix socket writers, which
+// receive only the results written by the output writer, closing the
+// created ones if a writer can't be created
+func newResultWriters(options *Options) ([]output.Writer, error) {
+	var resultWriters []output.Writer
+	if options.WebhookURL != "" {
+		webhookWriter, err := output.NewWebhookWriter(output.WebhookOptions{
+			URL:          options.WebhookURL,
+			BatchSize:    options.WebhookBatchSize,
+			Headers:      options.ParseWebhookHeaders(),
+			DropWhenFull: options.WebhookDrop,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create webhook writer")
+		}
+		resultWriters = append(resultWriters, webhookWriter)
+	}
+	if options.UnixSocket != "" {
+		unixSocketWriter, err := output.NewUnixSocketWriter(output.UnixSocketOptions{
+			Path:   options.UnixSocket,
+			Listen: options.UnixSocketListen,
+		})
+		if err != nil {
+			for _, resultWriter := range resultWriters {
+				_ = resultWriter.Close()
+			}
+			return nil, errors.Wrap(err, "could not create unix socket writer")
+		}
+		resultWriters = append(resultWriters, unixSocketWriter)
+	}
+	return resultWriters, nil
+}
+
 // NewCrawlerOptions creates a new crawler options structure
 // from user specified options.
 func NewCrawlerOptions(options *Options) (*CrawlerOptions, error) {
@@ -49,10 +82,170 @@ func NewCrawlerOptions(options *Options) (*CrawlerOptions, error) {
 		return nil, errors.Wrap(err, "could not create filter")
 	}
 
-	outputWriter, err := output.New(!options.NoColors, options.JSON, options.Verbose, options.StoreResponse, options.OutputFile, options.Fields, options.StoreFields, options.StoreResponseDir)
+	resultWriters, err := newResultWriters(options)
 	if err != nil {
+		return nil, err
+	}
+	outputOptions := &output.Options{
+		Colors:      !options.NoColors,
+		ColorScheme: options.ColorScheme,
+		// json flag is documented to write JSONL(ines) output
+		JSONL:                options.JSON,
+		JSONArray:            options.JSONArray,
+		CSV:                  options.CSV,
+		TSV:                  options.TSV,
+		YAML:                 options.YAML,
+		CEF:                  options.CEF,
+		OutputCurl:           options.OutputCurl,
+		CEFDeviceVendor:      options.CEFVendor,
+		CEFDeviceProduct:     options.CEFProduct,
+		CEFDeviceVersion:     options.CEFVersion,
+		HAR:                  options.HAR,
+		Protobuf:             options.Protobuf,
+		Verbose:              options.Verbose,
+		TimestampFormat:      options.TimestampFormat,
+		StoreResponse:        options.StoreResponse,
+		OutputFile:           options.OutputFile,
+		SplitBy:              options.SplitBy,
+		SortOutput:           options.SortOutput,
+		DedupByBody:          options.DedupByBody,
+		NormalizeURLs:        options.NormalizeURLs,
+		SortQueryParams:      options.SortQueryParams,
+		ParamsOnly:           options.ParamsOnly,
+		JSOutput:             options.JSOutput,
+		CompressOutput:       options.CompressOutput,
+		Silent:               options.NoStdout,
+		Fields:               options.Fields,
+		OutputTemplate:       options.OutputTemplate,
+		StoreFields:          options.StoreFields,
+		StoreFieldsDir:       options.StoreFieldsDir,
+		StoreFieldsRunID:     options.StoreFieldsRunID,
+		StoreResponseDir:     options.StoreResponseDir,
+		CompressResponses:    options.CompressResponses,
+		SplitStoredResponses: options.SplitResponses,
+		ResponseFilenameMode: options.StoreResponseNaming,
+		IndexFormat:          options.StoreResponseIndex,
+		ResumeResponses:      options.ResumeResponses,
+		DedupResponsesByHash: options.StoreResponseDedup,
+		RedactPatterns:       options.RedactPatterns,
+		RedactBuiltins:       options.RedactBuiltins,
+
+		PreserveFileColor:     options.PreserveFileColor,
+		Tabular:               options.Tabular,
+		SyncEvery:             options.SyncEvery,
+		SyncInterval:          options.SyncInterval,
+		RotateSize:            int64(options.RotateSize),
+		RotateInterval:        options.RotateInterval,
+		BundleOnClose:         options.Bundle,
+		BundleRemoveOriginals: options.BundleRemove,
+		Sinks:                 options.ParseSinks(),
+		Manifest:              options.Manifest,
+		CaptureHeaders:        options.CaptureHeaders,
+		HashAlgorithm:         options.HashAlgorithm,
+		Summary:               options.Summary || options.SummaryFile != "",
+		SummaryFile:           options.SummaryFile,
+		CountOnly:             options.CountOnly,
+		Progress:              options.Progress,
+		GraphOutput:           options.GraphOutput,
+		CaptureRequestHeaders: options.CaptureRequestHeaders,
+		DetectTechnologies:    options.TechDetect,
+		ValidateOutput:        options.ValidateOutput,
+		CaptureTLS:            options.CaptureTLS,
+		SniffContentType:      options.SniffContentType,
+		FieldAliases:          options.ParseFieldAliases(),
+		ExtractSelectors:      options.ParseExtractSelectors(),
+		StoreMatchStatusCodes: options.StoreMatchStatusCode,
+		MatchContentTypes:     options.MatchContentType,
+		FilterContentTypes:    options.FilterContentType,
+		MatchStatusCodes:      options.MatchStatusCode,
+		FilterStatusCodes:     options.FilterStatusCode,
+		MatchRegex:            options.MatchRegex,
+		FilterRegex:           options.FilterRegex,
+		MaxOutputDepth:        options.MaxOutputDepth,
+		MinBodySize:           int64(options.MinBodySize),
+		MaxBodySize:           int64(options.MaxBodySize),
+		LogFiltered:           options.LogFiltered,
+		SequenceIDs:           options.SequenceIDs,
+		MaxResultsPerSecond:   options.MaxResultsPerSecond,
+		BaselineFile:          options.BaselineFile,
+		UpdateBaseline:        options.UpdateBaseline,
+		SampleRate:            options.SampleRate,
+		SampleEveryN:          options.SampleEveryN,
+
+		StoreMatchContentTypes: options.StoreMatchContentType,
+		ResultWriters:          resultWriters,
+	}
+	outputWriter, err := output.NewWithOptions(outputOptions)
+	if err != nil {
+		for _, resultWriter := range resultWriters {
+			_ = resultWriter.Close()
+		}
 		return nil, errors.Wrap(err, "could not create output writer")
 	}
+	// the output writer and the writers wrapped with it are closed if
+	// one of the next writers can't be created
+	created := false
+	defer func() {
+		if !created {
+			_ = outputWriter.Close()
+		}
+	}()
+	if options.SARIFExport != "" {
+		sarifWriter, err := output.NewSARIFWriter(options.SARIFExport)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create sarif writer")
+		}
+		outputWriter = output.MultiWriter(outputWriter, sarifWriter)
+	}
+	if options.SQLiteOutput != "" {
+		sqliteWriter, err := output.NewSQLiteWriter(options.SQLiteOutput)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create sqlite writer")
+		}
+		outputWriter = output.MultiWriter(outputWriter, sqliteWriter)
+	}
+	if options.ESURL != "" {
+		esWriter, err := output.NewElasticsearchWriter(output.ElasticsearchOptions{
+			URL:        options.ESURL,
+			Index:      options.ESIndex,
+			AuthHeader: options.ESAuthHeader,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create elasticsearch writer")
+		}
+		outputWriter = output.MultiWriter(outputWriter, esWriter)
+	}
+	if len(options.KafkaBrokers) > 0 {
+		kafkaWriter, err := output.NewKafkaWriter(output.KafkaOptions{
+			Brokers:    options.KafkaBrokers,
+			Topic:      options.KafkaTopic,
+			RequireAck: options.KafkaRequireAck,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create kafka writer")
+		}
+		outputWriter = output.MultiWriter(outputWriter, kafkaWriter)
+	}
+	for _, storage := range []struct{ provider, bucket, prefix string }{
+		{output.ObjectStorageS3, options.S3Bucket, options.S3Prefix},
+		{output.ObjectStorageGCS, options.GCSBucket, options.GCSPrefix},
+	} {
+		if storage.bucket == "" {
+			continue
+		}
+		storageWriter, err := output.NewObjectStorageWriter(output.ObjectStorageOptions{
+			Provider: storage.provider,
+			Bucket:   storage.bucket,
+			Prefix:   storage.prefix,
+			Paths:    output.GetArtifactPaths(outputOptions),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not create object storage writer")
+		}
+		// the writer is closed after the output writer, uploading the
+		// closed output files
+		outputWriter = output.MultiWriter(outputWriter, storageWriter)
+	}
 
 	var ratelimiter ratelimit.Limiter
 	if options.RateLimit > 0 {
@@ -70,6 +263,7 @@ func NewCrawlerOptions(options *Options) (*CrawlerOptions, error) {
 		Dialer:              fastdialerInstance,
 		OutputWriter:        outputWriter,
 	}
+	created = true
 	return crawlerOptions, nil
 }
 
@@ -0,0 +1,27 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCrawlerOptionsClosesOutput(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "output.json")
+
+	_, err := NewCrawlerOptions(&Options{
+		FieldScope:   "rdn",
+		OutputFile:   file,
+		JSONArray:    true,
+		NoStdout:     true,
+		SQLiteOutput: filepath.Join(dir, "missing", "results.db"),
+	})
+	require.NotNil(t, err, "could not fail on sqlite writer error")
+
+	data, err := os.ReadFile(file)
+	require.Nil(t, err, "could not read output file")
+	require.Equal(t, "[]\n", string(data), "could not close output writer")
+}
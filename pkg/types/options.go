@@ -2,6 +2,7 @@ package types
 
 import (
 	"strings"
+	"time"
 
 	"github.com/projectdiscovery/goflags"
 	"github.com/projectdiscovery/katana/pkg/output"
@@ -21,12 +22,64 @@ type Options struct {
 	NoScope bool
 	// DisplayOutScope displays out of scope items in results
 	DisplayOutScope bool
+	// IncludeErrors writes the failed requests as error results
+	IncludeErrors bool
 	// ExtensionsMatch contains extensions to match explicitly
 	ExtensionsMatch goflags.StringSlice
 	// ExtensionFilter contains additional items for filter list
 	ExtensionFilter goflags.StringSlice
+	// Summary enables writing a summary of the crawl results
+	Summary bool
+	// SummaryFile is the file to write the summary of the crawl results to
+	SummaryFile string
+	// CountOnly enables only counting the crawl results without writing output
+	CountOnly bool
+	// Progress enables printing a progress line of the written results to stderr
+	Progress bool
+	// GraphOutput is the file to write a DOT graph of the crawl links to
+	GraphOutput string
+	// HashAlgorithm is the algorithm to hash response bodies with
+	HashAlgorithm string
+	// CaptureHeaders contains response headers to capture in output
+	CaptureHeaders goflags.StringSlice
+	// CaptureRequestHeaders enables capturing the sent request headers in output
+	CaptureRequestHeaders bool
+	// TechDetect enables fingerprinting the technologies of the responses in output
+	TechDetect bool
+	// MatchContentType contains content-types to match in output
+	MatchContentType goflags.StringSlice
+	// FilterContentType contains content-types to filter from output
+	FilterContentType goflags.StringSlice
+	// MatchStatusCode contains response status codes to match in output
+	MatchStatusCode goflags.StringSlice
+	// FilterStatusCode contains response status codes to filter from output
+	FilterStatusCode goflags.StringSlice
+	// MatchRegex contains regexes to match output URLs
+	MatchRegex goflags.StringSlice
+	// FilterRegex contains regexes to filter output URLs
+	FilterRegex goflags.StringSlice
 	// MaxDepth is the maximum depth to crawl
 	MaxDepth int
+	// MaxOutputDepth is the maximum depth of the results to output
+	MaxOutputDepth int
+	// BaselineFile is a file of known urls to drop from output
+	BaselineFile string
+	// UpdateBaseline appends the new urls written to output to the baseline file
+	UpdateBaseline bool
+	// MaxResultsPerSecond is the maximum number of results to output per second
+	MaxResultsPerSecond int
+	// SequenceIDs assigns an increasing sequence id to every result in output
+	SequenceIDs bool
+	// LogFiltered logs the results dropped by the output filters with the reason
+	LogFiltered bool
+	// MinBodySize is the minimum response body size in bytes of the results to output
+	MinBodySize int
+	// MaxBodySize is the maximum response body size in bytes of the results to output
+	MaxBodySize int
+	// SampleRate is the probability of writing each result to output
+	SampleRate float64
+	// SampleEveryN writes only every nth result to output
+	SampleEveryN int
 	// BodyReadSize is the maximum size of response body to read
 	BodyReadSize int
 	// Timeout is the time to wait for request in seconds
@@ -55,24 +108,144 @@ type Options struct {
 	FieldScope string
 	// OutputFile is the file to write output to
 	OutputFile string
+	// JSOutput is the file to write the javascript extracted results to
+	JSOutput string
+	// ParamsOnly writes only the unique query parameter names on exit
+	ParamsOnly bool
+	// SortOutput writes the results sorted by URL and source on exit
+	SortOutput bool
+	// DedupByBody writes only the first result for every unique response body
+	DedupByBody bool
+	// NormalizeURLs writes absolute normalized urls keeping the original as raw url
+	NormalizeURLs bool
+	// SortQueryParams sorts the query parameters of the normalized urls
+	SortQueryParams bool
+	// SplitBy splits the output into a file per key (source,status,host)
+	// in the output directory
+	SplitBy string
+	// CompressOutput enables gzip compressing the output file
+	CompressOutput bool
+	// Bundle is the .zip or .tar.gz file to package the output into on close
+	Bundle string
+	// BundleRemove removes the bundled output files after bundling
+	BundleRemove bool
+	// Sinks contains the additional outputs as format[:file] pairs written to stdout without a file
+	Sinks goflags.StringSlice
+	// Manifest writes a manifest.json of the output artifacts with checksums on close
+	Manifest bool
+	// SyncEvery is the number of results to sync the output file after
+	SyncEvery int
+	// SyncInterval is the interval to sync the output file at
+	SyncInterval time.Duration
+	// RotateSize is the size in bytes to rotate the output file at
+	RotateSize int
+	// RotateInterval is the interval to rotate the output file at
+	RotateInterval time.Duration
+	// NoStdout disables writing the results to stdout
+	NoStdout bool
 	// KnownFiles enables crawling of knows files like robots.txt, sitemap.xml, etc
 	KnownFiles string
 	// Fields is the fields to format in output
 	Fields string
+	// FieldAliases renames json output keys with key=alias pairs
+	FieldAliases goflags.StringSlice
+	// ExtractSelectors extracts values from html responses with name=css-selector pairs
+	ExtractSelectors goflags.StringSlice
+	// OutputTemplate is the template with {field} placeholders to format output
+	OutputTemplate string
 	// StoreFields is the fields to store in separate per-host files
 	StoreFields string
+	// StoreFieldsDir is the base directory of the per-run store fields directories
+	StoreFieldsDir string
+	// StoreFieldsRunID is the name of the store fields directory of the run
+	StoreFieldsRunID string
 	// NoColors disables coloring of response output
 	NoColors bool
+	// ColorScheme is the color scheme for the response output
+	ColorScheme string
+	// TimestampFormat is the format of the result timestamps (rfc3339,epoch,epochmillis)
+	TimestampFormat string
+	// Tabular enables displaying the output in aligned columns
+	Tabular bool
+	// PreserveFileColor keeps the output coloring in the output file
+	PreserveFileColor bool
 	// JSON enables writing output in JSON format
 	JSON bool
+	// JSONArray enables writing output as a single JSON array
+	JSONArray bool
+	// CSV enables writing output in CSV format
+	CSV bool
+	// TSV enables writing output in TSV format
+	TSV bool
+	// YAML enables writing output in YAML format
+	YAML bool
+	// CEF enables writing output in Common Event Format
+	CEF bool
+	// OutputCurl enables writing output as curl commands
+	OutputCurl bool
+	// CEFVendor is the device vendor of the CEF output
+	CEFVendor string
+	// CEFProduct is the device product of the CEF output
+	CEFProduct string
+	// CEFVersion is the device version of the CEF output
+	CEFVersion string
+	// Protobuf enables writing output as length-prefixed protobuf messages
+	Protobuf bool
+	// HAR enables writing requests/responses in HAR format
+	HAR bool
+	// SARIFExport is the file to write output to in SARIF format
+	SARIFExport string
+	// SQLiteOutput is the sqlite database file to write the results to
+	SQLiteOutput string
+	// ESURL is the elasticsearch/opensearch URL to send the results to
+	ESURL string
+	// ESIndex is the elasticsearch index of the result documents
+	ESIndex string
+	// ESAuthHeader is the authentication header of the elasticsearch requests
+	ESAuthHeader string
+	// KafkaBrokers are the bootstrap brokers of the kafka cluster to produce the results to
+	KafkaBrokers goflags.StringSlice
+	// KafkaTopic is the kafka topic to produce the results to
+	KafkaTopic string
+	// KafkaRequireAck specifies to require the acknowledgement of all replicas and fail on produce errors
+	KafkaRequireAck bool
+	// S3Bucket is the S3 bucket to upload the results and output files to
+	S3Bucket string
+	// S3Prefix is the prefix of the objects uploaded to the S3 bucket
+	S3Prefix string
+	// GCSBucket is the GCS bucket to upload the results and output files to
+	GCSBucket string
+	// GCSPrefix is the prefix of the objects uploaded to the GCS bucket
+	GCSPrefix string
+	// WebhookURL is the URL to POST the results to as JSON
+	WebhookURL string
+	// WebhookBatchSize is the maximum number of results per webhook request
+	WebhookBatchSize int
+	// WebhookHeaders is a list of headers to add to the webhook requests
+	WebhookHeaders goflags.StringSlice
+	// WebhookDrop specifies to drop the results when the webhook queue is full
+	WebhookDrop bool
+	// UnixSocket is the path of the unix socket to stream the results to as JSONL
+	UnixSocket string
+	// UnixSocketListen specifies to listen on the unix socket instead of connecting to it
+	UnixSocketListen bool
 	// Silent shows only output
 	Silent bool
+	// SniffContentType detects the content-type of responses without the header
+	SniffContentType bool
+	// CaptureTLS captures the TLS certificate details of the HTTPS responses
+	CaptureTLS bool
+	// ValidateOutput validates the json output against the result schema
+	ValidateOutput bool
 	// Verbose specifies showing verbose output
 	Verbose bool
 	// Version enables showing of crawler version
 	Version bool
 	// ScrapeJSResponses enables scraping of relative endpoints from javascript
 	ScrapeJSResponses bool
+	// IncludeSnippet includes the source snippet around the URLs extracted
+	// from scripts in the results
+	IncludeSnippet bool
 	// CustomHeaders is a list of custom headers to add to request
 	CustomHeaders goflags.StringSlice
 	// Headless enables headless scraping
@@ -95,6 +268,26 @@ type Options struct {
 	StoreResponse bool
 	// StoreResponseDir specifies if katana should use a custom directory to store http requests/responses
 	StoreResponseDir string
+	// StoreMatchStatusCode contains response status codes of the responses to store
+	StoreMatchStatusCode goflags.StringSlice
+	// StoreMatchContentType contains content-types of the responses to store
+	StoreMatchContentType goflags.StringSlice
+	// StoreResponseIndex is the format of the stored responses index file
+	StoreResponseIndex string
+	// ResumeResponses specifies if katana should keep previously stored http requests/responses
+	ResumeResponses bool
+	// StoreResponseNaming is the naming scheme of the stored http responses
+	StoreResponseNaming string
+	// RedactPatterns contains regexes of the secrets to redact from the request bodies and stored responses
+	RedactPatterns goflags.StringSlice
+	// RedactBuiltins redacts the common secrets like aws keys and jwts
+	RedactBuiltins bool
+	// StoreResponseDedup stores the http responses with an already stored body as a link to the first response
+	StoreResponseDedup bool
+	// SplitResponses stores requests, responses and metadata in separate files
+	SplitResponses bool
+	// CompressResponses specifies if katana should gzip compress stored http requests/responses
+	CompressResponses bool
 }
 
 func (options *Options) ParseCustomHeaders() map[string]string {
@@ -107,6 +300,59 @@ func (options *Options) ParseCustomHeaders() map[string]string {
 	return customHeaders
 }
 
+// ParseWebhookHeaders returns the webhook headers as a map
+func (options *Options) ParseWebhookHeaders() map[string]string {
+	webhookHeaders := make(map[string]string)
+	for _, v := range options.WebhookHeaders {
+		if headerParts := strings.SplitN(v, ":", 2); len(headerParts) >= 2 {
+			webhookHeaders[strings.TrimSpace(headerParts[0])] = strings.TrimSpace(headerParts[1])
+		}
+	}
+	return webhookHeaders
+}
+
+// ParseFieldAliases returns the json output key aliases as a map
+func (options *Options) ParseFieldAliases() map[string]string {
+	if len(options.FieldAliases) == 0 {
+		return nil
+	}
+	fieldAliases := make(map[string]string)
+	for _, v := range options.FieldAliases {
+		if aliasParts := strings.SplitN(v, "=", 2); len(aliasParts) >= 2 {
+			fieldAliases[strings.TrimSpace(aliasParts[0])] = strings.TrimSpace(aliasParts[1])
+		}
+	}
+	return fieldAliases
+}
+
+// ParseExtractSelectors returns the extract selectors as a map of names to css selectors
+func (options *Options) ParseExtractSelectors() map[string]string {
+	if len(options.ExtractSelectors) == 0 {
+		return nil
+	}
+	extractSelectors := make(map[string]string)
+	for _, v := range options.ExtractSelectors {
+		if selectorParts := strings.SplitN(v, "=", 2); len(selectorParts) >= 2 {
+			extractSelectors[strings.TrimSpace(selectorParts[0])] = strings.TrimSpace(selectorParts[1])
+		}
+	}
+	return extractSelectors
+}
+
+// ParseSinks returns the additional outputs as sink configs
+func (options *Options) ParseSinks() []output.SinkConfig {
+	var sinks []output.SinkConfig
+	for _, v := range options.Sinks {
+		sinkParts := strings.SplitN(v, ":", 2)
+		sink := output.SinkConfig{Format: strings.TrimSpace(sinkParts[0])}
+		if len(sinkParts) >= 2 {
+			sink.File = strings.TrimSpace(sinkParts[1])
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks
+}
+
 func (options *Options) ParseHeadlessOptionalArguments() map[string]string {
 	optionalArguments := make(map[string]string)
 	for _, v := range options.HeadlessOptionalArguments {